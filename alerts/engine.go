@@ -0,0 +1,465 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// Default 进程内的告警引擎, 每次保存快照后评估
+var Default = NewEngine()
+
+// Engine 保存关注规则, 按快照评估并记录、投递告警
+// 去重记录随规则落盘并由 leader 写入共享缓存, 重启或切换 leader 后不会重复告警
+type Engine struct {
+	mu        sync.Mutex
+	saveMu    sync.Mutex // 串行写去重文件, 不与 mu 同时持有写盘
+	file      string
+	rules     []globals.WatchRule
+	regexes   map[string]*regexp.Regexp
+	fired     map[string]time.Time
+	alerts    []globals.Alert
+	notifiers map[string]Notifier
+}
+
+func NewEngine() *Engine {
+	e := &Engine{
+		regexes:   map[string]*regexp.Regexp{},
+		fired:     map[string]time.Time{},
+		notifiers: map[string]Notifier{},
+	}
+	e.RegisterNotifier(LogNotifier{})
+	return e
+}
+
+// RegisterNotifier 注册投递方式, 同名覆盖
+func (e *Engine) RegisterNotifier(n Notifier) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.notifiers[n.Name()] = n
+}
+
+// Notifiers 已注册的投递方式名称
+func (e *Engine) Notifiers() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var result []string
+	for k := range e.notifiers {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Load 从 file 读取规则, 之后规则变化都会写回 file; file 不存在时视为没有规则
+func (e *Engine) Load(file string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.file = file
+
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var rules []globals.WatchRule
+	if err := json.Unmarshal(content, &rules); err != nil {
+		return fmt.Errorf("parse %s: %w", file, err)
+	}
+	for _, r := range rules {
+		if err := e.compile(r); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Id, err)
+		}
+	}
+	e.rules = rules
+
+	// 去重记录丢失只会重复告警, 不影响启动
+	content, err = os.ReadFile(e.firedFile())
+	if err == nil {
+		fired := map[string]time.Time{}
+		if err := json.Unmarshal(content, &fired); err != nil {
+			globals.GoLogger.Errorf("PARSE %s ERR %s", e.firedFile(), err.Error())
+		}
+		for key, t := range fired {
+			if time.Since(t) < globals.AlertDedupWindow {
+				e.fired[key] = t
+			}
+		}
+	}
+	return nil
+}
+
+// firedFile 去重记录保存在规则文件旁
+func (e *Engine) firedFile() string {
+	return e.file + ".fired"
+}
+
+// saveFired 调用方不能持有 mu; 在 saveMu 内读取记录, 保证最后写入的是最新的去重记录
+func (e *Engine) saveFired() error {
+	e.saveMu.Lock()
+	defer e.saveMu.Unlock()
+
+	e.mu.Lock()
+	if e.file == "" {
+		e.mu.Unlock()
+		return nil
+	}
+	file := e.firedFile()
+	content, err := json.Marshal(e.fired)
+	e.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// save 调用方需持有锁
+func (e *Engine) save() error {
+	if e.file == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(e.rules, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := e.file + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.file)
+}
+
+// compile 校验规则并缓存正则, 调用方需持有锁
+func (e *Engine) compile(r globals.WatchRule) error {
+	if len(r.Keywords) == 0 && r.Regex == "" {
+		return errors.New("keywords or regex is required")
+	}
+	if r.MinRank < 0 || r.MinHeat < 0 {
+		return errors.New("min_rank and min_heat must not be negative")
+	}
+	for _, v := range r.Notifiers {
+		if _, ok := e.notifiers[v]; !ok {
+			return fmt.Errorf("unknown notifier: %s", v)
+		}
+	}
+
+	if r.Regex != "" {
+		rex, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+		e.regexes[r.Id] = rex
+	}
+	return nil
+}
+
+// AddRule 新增规则, 分配ID和创建时间; 关键词统一转为小写
+func (e *Engine) AddRule(r globals.WatchRule) (globals.WatchRule, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	r.Id = strconv.FormatInt(now.UnixNano(), 36)
+	r.CreatedAt = now
+
+	var keywords []string
+	for _, v := range r.Keywords {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			keywords = append(keywords, v)
+		}
+	}
+	r.Keywords = keywords
+	if r.Name == "" {
+		r.Name = r.Id
+	}
+
+	if err := e.compile(r); err != nil {
+		return r, err
+	}
+
+	e.rules = append(e.rules, r)
+	if err := e.save(); err != nil {
+		globals.GoLogger.Errorf("SAVE WATCH RULES ERR %s", err.Error())
+	}
+	return r, nil
+}
+
+// RemoveRule 删除规则及其去重记录
+func (e *Engine) RemoveRule(id string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for k, r := range e.rules {
+		if r.Id != id {
+			continue
+		}
+		e.rules = append(e.rules[:k:k], e.rules[k+1:]...)
+		delete(e.regexes, id)
+		for key := range e.fired {
+			if strings.HasPrefix(key, id+"|") {
+				delete(e.fired, key)
+			}
+		}
+		if err := e.save(); err != nil {
+			globals.GoLogger.Errorf("SAVE WATCH RULES ERR %s", err.Error())
+		}
+		return true
+	}
+	return false
+}
+
+func (e *Engine) Rule(id string) (globals.WatchRule, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, r := range e.rules {
+		if r.Id == id {
+			return r, true
+		}
+	}
+	return globals.WatchRule{}, false
+}
+
+func (e *Engine) Rules() []globals.WatchRule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]globals.WatchRule(nil), e.rules...)
+}
+
+// Alerts 按时间倒序列出告警, 参数为空时不过滤, limit<=0 不限制数量
+func (e *Engine) Alerts(ruleId, platform string, since time.Time, limit int) []globals.Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var result []globals.Alert
+	for k := len(e.alerts) - 1; k >= 0; k-- {
+		a := e.alerts[k]
+		if !since.IsZero() && a.FiredAt.Before(since) {
+			break
+		}
+		if ruleId != "" && a.RuleId != ruleId {
+			continue
+		}
+		if platform != "" && a.Platform != platform {
+			continue
+		}
+		result = append(result, a)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// candidate 命中规则且本地没有去重记录的条目
+type candidate struct {
+	rule   globals.WatchRule
+	item   globals.GblRespData
+	reason string
+}
+
+// Evaluate 用新快照评估全部规则, prev 为同一榜单的上一份快照, 用于判断排名是否刚进入阈值
+// 返回本次触发的告警, 投递在后台进行, 开启选举时只有 leader 投递
+// 持锁只做匹配和记录, 读写共享缓存和去重文件都在锁外进行
+func (e *Engine) Evaluate(prev, snap globals.Snapshot) []globals.Alert {
+	candidates := e.candidates(prev, snap)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// 本地没有记录时以共享缓存为准, 同一快照在各实例上都会评估, 只有更早的触发才算重复
+	shared := make([]time.Time, len(candidates))
+	if globals.GoCache != nil {
+		for k, c := range candidates {
+			var firedAt time.Time
+			if globals.GoCache.Get(utils.GetAlertFiredKey(c.rule.Id, c.item.Id), &firedAt) && firedAt.Before(snap.FetchedAt) {
+				shared[k] = firedAt
+			}
+		}
+	}
+
+	e.mu.Lock()
+	var result []globals.Alert
+	for k, c := range candidates {
+		// 锁外读取缓存期间可能已被并发的评估记录
+		key := c.rule.Id + "|" + c.item.Id
+		if _, found := e.fired[key]; found {
+			continue
+		}
+		if !shared[k].IsZero() {
+			e.fired[key] = shared[k]
+			continue
+		}
+		e.fired[key] = snap.FetchedAt
+
+		result = append(result, globals.Alert{
+			Id:       snap.Platform + "-" + snap.Id + "-" + strconv.Itoa(len(result)),
+			RuleId:   c.rule.Id,
+			RuleName: c.rule.Name,
+			Platform: snap.Platform,
+			Reason:   c.reason,
+			Item:     c.item,
+			FiredAt:  snap.FetchedAt,
+		})
+	}
+
+	e.alerts = append(e.alerts, result...)
+	if len(e.alerts) > globals.AlertKeep {
+		e.alerts = e.alerts[len(e.alerts)-globals.AlertKeep:]
+	}
+	deliveries := e.deliveries(result)
+	e.mu.Unlock()
+
+	if len(result) == 0 {
+		return result
+	}
+
+	isLeader := leader.IsLeader()
+	if globals.GoCache != nil && isLeader {
+		for _, a := range result {
+			globals.GoCache.Set(utils.GetAlertFiredKey(a.RuleId, a.Item.Id), a.FiredAt, globals.AlertDedupWindow)
+		}
+	}
+	if err := e.saveFired(); err != nil {
+		globals.GoLogger.Errorf("SAVE ALERT DEDUP ERR %s", err.Error())
+	}
+
+	// 从实例只记录告警, 由 leader 投递
+	if !isLeader {
+		return result
+	}
+	for _, d := range deliveries {
+		go d.send()
+	}
+	return result
+}
+
+// candidates 清理过期的去重记录, 返回命中规则且本地没有去重记录的条目
+func (e *Engine) candidates(prev, snap globals.Snapshot) []candidate {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	prevPos := map[string]int{}
+	for _, v := range prev.Data {
+		prevPos[v.Id] = v.Pos
+	}
+
+	for key, t := range e.fired {
+		if snap.FetchedAt.Sub(t) >= globals.AlertDedupWindow {
+			delete(e.fired, key)
+		}
+	}
+
+	var result []candidate
+	for _, r := range e.rules {
+		if !ruleHasPlatform(r, snap.Platform) {
+			continue
+		}
+		for _, v := range snap.Data {
+			if !e.match(r, v) {
+				continue
+			}
+			if _, found := e.fired[r.Id+"|"+v.Id]; found {
+				continue
+			}
+
+			reason := globals.AlertReasonMatched
+			if pos, found := prevPos[v.Id]; found && r.MinRank > 0 && pos > r.MinRank {
+				reason = globals.AlertReasonRankCrossed
+			}
+			result = append(result, candidate{rule: r, item: v, reason: reason})
+		}
+	}
+	return result
+}
+
+// match 调用方需持有锁
+func (e *Engine) match(r globals.WatchRule, v globals.GblRespData) bool {
+	if r.MinRank > 0 && (v.Pos <= 0 || v.Pos > r.MinRank) {
+		return false
+	}
+	if r.MinHeat > 0 && utils.ParseCount(v.HotVal) < r.MinHeat {
+		return false
+	}
+
+	text := strings.ToLower(v.Title + "\n" + v.Desc)
+	for _, k := range r.Keywords {
+		if strings.Contains(text, k) {
+			return true
+		}
+	}
+	if rex, ok := e.regexes[r.Id]; ok && rex.MatchString(v.Title+"\n"+v.Desc) {
+		return true
+	}
+	return false
+}
+
+func ruleHasPlatform(r globals.WatchRule, flag string) bool {
+	if len(r.Platforms) == 0 {
+		return true
+	}
+	for _, v := range r.Platforms {
+		if v == flag {
+			return true
+		}
+	}
+	return false
+}
+
+type delivery struct {
+	notifier Notifier
+	alert    globals.Alert
+}
+
+func (d delivery) send() {
+	if err := d.notifier.Notify(d.alert); err != nil {
+		globals.GoLogger.Errorf("NOTIFY %s ALERT %s ERR %s", d.notifier.Name(), d.alert.Id, err.Error())
+	}
+}
+
+// deliveries 规则未指定投递方式时只写日志, 机器人需要在规则中显式指定; 调用方需持有锁
+func (e *Engine) deliveries(list []globals.Alert) []delivery {
+	var result []delivery
+	for _, a := range list {
+		var names []string
+		for _, r := range e.rules {
+			if r.Id == a.RuleId {
+				names = r.Notifiers
+			}
+		}
+		if len(names) == 0 {
+			names = []string{LogNotifier{}.Name()}
+		}
+		for _, name := range names {
+			if n, ok := e.notifiers[name]; ok {
+				result = append(result, delivery{notifier: n, alert: a})
+			}
+		}
+	}
+	return result
+}
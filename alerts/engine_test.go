@@ -0,0 +1,194 @@
+package alerts
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/caches"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+)
+
+func TestMain(m *testing.M) {
+	// 投递在后台写日志, 只设置一次避免与后续测试竞争
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func TestEvaluateDedupSurvivesRestart(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rules.json")
+
+	e := NewEngine()
+	if err := e.Load(file); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AddRule(globals.WatchRule{Keywords: []string{"苹果"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	snap := globals.Snapshot{
+		Id:        "1",
+		Platform:  globals.WeiboFlag,
+		FetchedAt: time.Now(),
+		Data:      []globals.GblRespData{{Id: "weibo-1", Title: "苹果发布会", Pos: 1}},
+	}
+	if got := e.Evaluate(globals.Snapshot{}, snap); len(got) != 1 {
+		t.Fatalf("first evaluate fired %d alerts, want 1", len(got))
+	}
+
+	// 重启后从文件恢复去重记录
+	restarted := NewEngine()
+	if err := restarted.Load(file); err != nil {
+		t.Fatal(err)
+	}
+	snap.Id, snap.FetchedAt = "2", snap.FetchedAt.Add(time.Minute)
+	if got := restarted.Evaluate(globals.Snapshot{}, snap); len(got) != 0 {
+		t.Fatalf("evaluate after restart fired %d alerts, want 0", len(got))
+	}
+}
+
+func TestEvaluateDedupSharedCache(t *testing.T) {
+	globals.GoCache = caches.NewMemory(cache.New(time.Hour, time.Hour))
+	t.Cleanup(func() { globals.GoCache = nil })
+
+	rule := globals.WatchRule{Keywords: []string{"苹果"}}
+	snap := globals.Snapshot{
+		Id:        "1",
+		Platform:  globals.WeiboFlag,
+		FetchedAt: time.Now(),
+		Data:      []globals.GblRespData{{Id: "weibo-1", Title: "苹果发布会", Pos: 1}},
+	}
+
+	// 只有 leader 写入共享的去重记录
+	leader.Standalone()
+	first := NewEngine()
+	r, err := first.AddRule(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := first.Evaluate(globals.Snapshot{}, snap); len(got) != 1 {
+		t.Fatalf("leader fired %d alerts, want 1", len(got))
+	}
+
+	// 同一份快照在其他实例上照常记录
+	follower := NewEngine()
+	follower.rules = []globals.WatchRule{r}
+	if got := follower.Evaluate(globals.Snapshot{}, snap); len(got) != 1 {
+		t.Fatalf("follower fired %d alerts for the same snapshot, want 1", len(got))
+	}
+
+	// 接管后的新快照不再重复告警
+	takeover := NewEngine()
+	takeover.rules = []globals.WatchRule{r}
+	snap.Id, snap.FetchedAt = "2", snap.FetchedAt.Add(time.Minute)
+	if got := takeover.Evaluate(globals.Snapshot{}, snap); len(got) != 0 {
+		t.Fatalf("new leader fired %d alerts, want 0", len(got))
+	}
+}
+
+// blockingCache Get 时等待 release, 用于确认读取共享缓存时不持有引擎的锁
+type blockingCache struct {
+	globals.Cache
+	getting chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingCache) Get(key string, v interface{}) bool {
+	c.getting <- struct{}{}
+	<-c.release
+	return c.Cache.Get(key, v)
+}
+
+func TestEvaluateCacheOutsideLock(t *testing.T) {
+	c := &blockingCache{Cache: caches.NewMemory(cache.New(time.Hour, time.Hour)), getting: make(chan struct{}), release: make(chan struct{})}
+	globals.GoCache = c
+	t.Cleanup(func() { globals.GoCache = nil })
+	leader.Standalone()
+
+	e := NewEngine()
+	if _, err := e.AddRule(globals.WatchRule{Keywords: []string{"苹果"}}); err != nil {
+		t.Fatal(err)
+	}
+	snap := globals.Snapshot{Id: "1", Platform: globals.WeiboFlag, FetchedAt: time.Now(), Data: []globals.GblRespData{{Id: "weibo-1", Title: "苹果发布会", Pos: 1}}}
+
+	done := make(chan []globals.Alert)
+	go func() { done <- e.Evaluate(globals.Snapshot{}, snap) }()
+	<-c.getting
+
+	// 读取缓存期间其他调用不被阻塞
+	rules := make(chan int)
+	go func() { rules <- len(e.Rules()) }()
+	select {
+	case n := <-rules:
+		if n != 1 {
+			t.Errorf("rules = %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Rules blocked while Evaluate was reading the cache")
+	}
+
+	close(c.release)
+	if got := <-done; len(got) != 1 {
+		t.Fatalf("fired %d alerts, want 1", len(got))
+	}
+}
+
+// recordNotifier 记录收到的告警
+type recordNotifier struct {
+	name string
+	mu   sync.Mutex
+	got  []string
+	wg   *sync.WaitGroup
+}
+
+func (n *recordNotifier) Name() string {
+	return n.name
+}
+
+func (n *recordNotifier) Notify(alert globals.Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.got = append(n.got, alert.RuleName)
+	n.wg.Done()
+	return nil
+}
+
+func TestDeliveriesDefaultToLog(t *testing.T) {
+	leader.Standalone()
+
+	var wg sync.WaitGroup
+	logs := &recordNotifier{name: "log", wg: &wg}
+	bot := &recordNotifier{name: "bot", wg: &wg}
+	e := NewEngine()
+	e.RegisterNotifier(logs)
+	e.RegisterNotifier(bot)
+
+	// 未指定投递方式的规则只写日志, 机器人需要显式指定
+	if _, err := e.AddRule(globals.WatchRule{Name: "default", Keywords: []string{"苹果"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AddRule(globals.WatchRule{Name: "explicit", Keywords: []string{"苹果"}, Notifiers: []string{"bot"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	wg.Add(2)
+	snap := globals.Snapshot{Id: "1", Platform: globals.WeiboFlag, FetchedAt: time.Now(), Data: []globals.GblRespData{{Id: "weibo-1", Title: "苹果发布会", Pos: 1}}}
+	if got := e.Evaluate(globals.Snapshot{}, snap); len(got) != 2 {
+		t.Fatalf("fired %d alerts, want 2", len(got))
+	}
+	wg.Wait()
+
+	if len(logs.got) != 1 || logs.got[0] != "default" {
+		t.Errorf("log got %v, want [default]", logs.got)
+	}
+	if len(bot.got) != 1 || bot.got[0] != "explicit" {
+		t.Errorf("bot got %v, want [explicit]", bot.got)
+	}
+}
@@ -0,0 +1,21 @@
+package alerts
+
+import "github.com/turbo-uid/hots/globals"
+
+// Notifier 告警的投递方式, 规则通过 Name 选择
+type Notifier interface {
+	Name() string
+	Notify(alert globals.Alert) error
+}
+
+// LogNotifier 将告警写入日志, 默认注册
+type LogNotifier struct{}
+
+func (LogNotifier) Name() string {
+	return "log"
+}
+
+func (LogNotifier) Notify(alert globals.Alert) error {
+	globals.GoLogger.Infof("ALERT %s [%s] %s #%d %s %s", alert.RuleName, alert.Platform, alert.Reason, alert.Item.Pos, alert.Item.Title, alert.Item.ToUrl)
+	return nil
+}
@@ -0,0 +1,219 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// 明文 Key 的前缀, 便于在日志和配置中辨认
+const keyPrefix = "hots_"
+
+// Default 进程内的 API Key 存储
+var Default = NewStore()
+
+// Store 保存 API Key 的哈希, 设置 file 后变化写回 file
+type Store struct {
+	mu   sync.RWMutex
+	file string
+	keys []globals.ApiKey
+	// 多实例部署时通过共享缓存同步
+	shared bool
+}
+
+func NewStore() *Store {
+	return &Store{}
+}
+
+func newId(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Load 从 file 读取 API Key, 之后的变化都会写回 file; file 不存在时视为没有 Key
+func (s *Store) Load(file string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.file = file
+
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var keys []globals.ApiKey
+	if err := json.Unmarshal(content, &keys); err != nil {
+		return fmt.Errorf("parse %s: %w", file, err)
+	}
+	s.keys = keys
+	return nil
+}
+
+// save 调用方需持有锁; leader 同时写入共享缓存
+func (s *Store) save() {
+	if s.shared && leader.IsLeader() {
+		globals.GoCache.Set(utils.GetApiKeysKey(), s.keys, globals.HotStaleExpired)
+	}
+	if s.file == "" {
+		return
+	}
+
+	content, err := json.MarshalIndent(s.keys, "", "  ")
+	if err == nil {
+		tmp := s.file + ".tmp"
+		if err = os.WriteFile(tmp, content, 0600); err == nil {
+			err = os.Rename(tmp, s.file)
+		}
+	}
+	if err != nil {
+		globals.GoLogger.Errorf("SAVE API KEYS ERR %s", err.Error())
+	}
+}
+
+// Create 生成新的 Key, role 默认为 read; 返回值带明文 Key, 之后的查询不再返回
+func (s *Store) Create(name, role string) (globals.ApiKey, error) {
+	if role == "" {
+		role = globals.ApiKeyRoleRead
+	}
+	if role != globals.ApiKeyRoleRead && role != globals.ApiKeyRoleAdmin {
+		return globals.ApiKey{}, fmt.Errorf("unknown role: %s", role)
+	}
+	if strings.TrimSpace(name) == "" {
+		return globals.ApiKey{}, errors.New("name is required")
+	}
+
+	plain := keyPrefix + newId(20)
+	k := globals.ApiKey{
+		Id:        newId(8),
+		Name:      name,
+		Role:      role,
+		Prefix:    plain[:len(keyPrefix)+6],
+		Hash:      hash(plain),
+		CreatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.keys = append(s.keys, k)
+	s.save()
+	s.mu.Unlock()
+
+	k.Hash = ""
+	k.Key = plain
+	return k, nil
+}
+
+// Revoke 删除 Key, 立即生效
+func (s *Store) Revoke(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, k := range s.keys {
+		if k.Id == id {
+			s.keys = append(s.keys[:i:i], s.keys[i+1:]...)
+			s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// List 全部 Key, 不含哈希
+func (s *Store) List() []globals.ApiKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]globals.ApiKey, 0, len(s.keys))
+	for _, k := range s.keys {
+		k.Hash = ""
+		result = append(result, k)
+	}
+	return result
+}
+
+// HasAdmin 是否存在 admin 权限的 Key
+func (s *Store) HasAdmin() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, k := range s.keys {
+		if k.Role == globals.ApiKeyRoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Check 校验明文 Key, 返回对应的 Key (不含哈希)
+func (s *Store) Check(plain string) (globals.ApiKey, bool) {
+	if !strings.HasPrefix(plain, keyPrefix) {
+		return globals.ApiKey{}, false
+	}
+	h := hash(plain)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, k := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(k.Hash), []byte(h)) == 1 {
+			k.Hash = ""
+			return k, true
+		}
+	}
+	return globals.ApiKey{}, false
+}
+
+// SyncLoop 多实例部署时每隔 interval 同步一次: leader 将 Key 写入共享缓存, 从实例用共享缓存中的 Key 替换本地的 Key 并写回 file
+// 从实例的 file 因此与 leader 一致, 接管后不会恢复已删除的 Key
+func (s *Store) SyncLoop(interval time.Duration) {
+	s.mu.Lock()
+	s.shared = true
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.Sync()
+		<-ticker.C
+	}
+}
+
+// Sync 同步一次, 见 SyncLoop
+func (s *Store) Sync() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if leader.IsLeader() {
+		globals.GoCache.Set(utils.GetApiKeysKey(), s.keys, globals.HotStaleExpired)
+		return
+	}
+
+	var keys []globals.ApiKey
+	if !globals.GoCache.Get(utils.GetApiKeysKey(), &keys) {
+		return
+	}
+	s.keys = keys
+	s.save()
+}
@@ -0,0 +1,138 @@
+package apikeys
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/caches"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+)
+
+func TestCheck(t *testing.T) {
+	s := NewStore()
+	read, err := s.Create("frontend", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := s.Create("ops", globals.ApiKeyRoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Role != globals.ApiKeyRoleRead || !strings.HasPrefix(read.Key, keyPrefix) || read.Hash != "" {
+		t.Errorf("created %+v", read)
+	}
+
+	k, ok := s.Check(admin.Key)
+	if !ok || k.Id != admin.Id || k.Role != globals.ApiKeyRoleAdmin || k.Hash != "" || k.Key != "" {
+		t.Errorf("check admin = %+v, %v", k, ok)
+	}
+	for _, plain := range []string{"", "hots_", read.Key + "x", strings.TrimPrefix(read.Key, keyPrefix), read.Prefix} {
+		if _, ok := s.Check(plain); ok {
+			t.Errorf("check %q succeeded", plain)
+		}
+	}
+
+	// 吊销后立即失效
+	if !s.Revoke(read.Id) || s.Revoke(read.Id) {
+		t.Fatal("revoke should succeed exactly once")
+	}
+	if _, ok := s.Check(read.Key); ok {
+		t.Error("revoked key still valid")
+	}
+	if _, ok := s.Check(admin.Key); !ok {
+		t.Error("revoking one key invalidated another")
+	}
+
+	if _, err := s.Create("x", "owner"); err == nil {
+		t.Error("unknown role accepted")
+	}
+	if _, err := s.Create(" ", ""); err == nil {
+		t.Error("empty name accepted")
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "keys.json")
+	s := NewStore()
+	if err := s.Load(file); err != nil {
+		t.Fatal(err)
+	}
+	k, _ := s.Create("frontend", "")
+
+	content, _ := os.ReadFile(file)
+	if strings.Contains(string(content), k.Key) {
+		t.Error("plain key written to file")
+	}
+
+	loaded := NewStore()
+	if err := loaded.Load(file); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loaded.Check(k.Key); !ok {
+		t.Error("key not valid after reload")
+	}
+}
+
+// denyElector 从不授予租约
+type denyElector struct{}
+
+func (denyElector) Acquire(ctx context.Context) (bool, error) { return false, nil }
+func (denyElector) Release(ctx context.Context) error         { return nil }
+
+func TestSync(t *testing.T) {
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+	globals.GoCache = caches.NewMemory(cache.New(time.Hour, time.Hour))
+	t.Cleanup(func() { globals.GoCache = nil })
+
+	leaderStore, followerStore := NewStore(), NewStore()
+	leaderStore.shared, followerStore.shared = true, true
+	followerFile := filepath.Join(t.TempDir(), "keys.json")
+	followerStore.Load(followerFile)
+	stale, _ := followerStore.Create("stale", "")
+
+	// leader 的修改写入共享缓存
+	leader.Standalone()
+	k, _ := leaderStore.Create("frontend", "")
+	revoked, _ := leaderStore.Create("old", "")
+	leaderStore.Revoke(revoked.Id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		leader.Run(ctx, denyElector{}, time.Hour)
+		close(done)
+	}()
+	for leader.IsLeader() {
+		time.Sleep(time.Millisecond)
+	}
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// 从实例用 leader 的 Key 替换本地的 Key, 并写回 file
+	followerStore.Sync()
+	if _, ok := followerStore.Check(k.Key); !ok {
+		t.Error("key created on leader not valid on follower")
+	}
+	for _, plain := range []string{revoked.Key, stale.Key} {
+		if _, ok := followerStore.Check(plain); ok {
+			t.Errorf("key %q missing on leader still valid on follower", plain)
+		}
+	}
+
+	reloaded := NewStore()
+	reloaded.Load(followerFile)
+	if _, ok := reloaded.Check(k.Key); !ok {
+		t.Error("synced keys not written to follower file")
+	}
+}
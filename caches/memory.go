@@ -0,0 +1,54 @@
+package caches
+
+import (
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// Memory 进程内缓存, 值原样保存, 读取时要求 v 指向同一类型
+type Memory struct {
+	c *cache.Cache
+}
+
+func NewMemory(c *cache.Cache) *Memory {
+	return &Memory{c: c}
+}
+
+func (m *Memory) Get(key string, v interface{}) bool {
+	cacheResult, found := m.c.Get(key)
+	if !found {
+		return false
+	}
+
+	target := reflect.ValueOf(v).Elem()
+	value := reflect.ValueOf(cacheResult)
+	if !value.Type().AssignableTo(target.Type()) {
+		return false
+	}
+	target.Set(value)
+	return true
+}
+
+func (m *Memory) Set(key string, v interface{}, ttl time.Duration) {
+	m.c.Set(key, v, ttl)
+}
+
+var lockSeq uint64
+
+// Lock 基于 go-cache 的 Add, 只在本进程内互斥
+func (m *Memory) Lock(key string, ttl time.Duration) (func(), bool) {
+	token := strconv.FormatUint(atomic.AddUint64(&lockSeq, 1), 10)
+	if err := m.c.Add(key, token, ttl); err != nil {
+		return nil, false
+	}
+
+	return func() {
+		if v, found := m.c.Get(key); found && v == token {
+			m.c.Delete(key)
+		}
+	}, true
+}
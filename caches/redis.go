@@ -0,0 +1,94 @@
+package caches
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Redis 多实例共享的缓存, 值以 JSON 保存, 键统一加上 prefix
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// 单次 Redis 操作超时
+var redisTimeout = 3 * time.Second
+
+func (r *Redis) Get(key string, v interface{}) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	content, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			globals.GoLogger.Errorf("REDIS GET %s ERR %s", key, err.Error())
+		}
+		return false
+	}
+
+	if err := json.Unmarshal(content, v); err != nil {
+		globals.GoLogger.Errorf("REDIS GET %s ERR %s", key, err.Error())
+		return false
+	}
+	return true
+}
+
+func (r *Redis) Set(key string, v interface{}, ttl time.Duration) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		globals.GoLogger.Errorf("REDIS SET %s ERR %s", key, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := r.client.Set(ctx, r.prefix+key, content, ttl).Err(); err != nil {
+		globals.GoLogger.Errorf("REDIS SET %s ERR %s", key, err.Error())
+	}
+}
+
+// 只删除自己持有的锁
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Lock SET NX PX 实现的分布式锁, Redis 不可用时视为获取失败
+func (r *Redis) Lock(key string, ttl time.Duration) (func(), bool) {
+	b := make([]byte, 16)
+	rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	ok, err := r.client.SetNX(ctx, r.prefix+key, token, ttl).Result()
+	if err != nil {
+		globals.GoLogger.Errorf("REDIS LOCK %s ERR %s", key, err.Error())
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+		defer cancel()
+
+		if err := unlockScript.Run(ctx, r.client, []string{r.prefix + key}, token).Err(); err != nil {
+			globals.GoLogger.Errorf("REDIS UNLOCK %s ERR %s", key, err.Error())
+		}
+	}, true
+}
@@ -0,0 +1,103 @@
+package caches
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/globals"
+)
+
+func newTestRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	t.Helper()
+	if globals.GoLogger == nil {
+		globals.GoLogger = logrus.New()
+		globals.GoLogger.SetOutput(io.Discard)
+	}
+
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedis(client, "hots:"), srv
+}
+
+func TestRedisGetSet(t *testing.T) {
+	r, srv := newTestRedis(t)
+
+	want := globals.GblResp{Succ: "ok", Data: []globals.GblRespData{{Title: "热搜", Pos: 1}}}
+	r.Set("weibo", want, time.Minute)
+
+	// 键带前缀, 值为 JSON
+	if !srv.Exists("hots:weibo") {
+		t.Fatalf("keys = %v, want hots:weibo", srv.Keys())
+	}
+	if ttl := srv.TTL("hots:weibo"); ttl != time.Minute {
+		t.Errorf("ttl = %s, want 1m", ttl)
+	}
+
+	var got globals.GblResp
+	if !r.Get("weibo", &got) {
+		t.Fatal("get after set returned false")
+	}
+	if got.Succ != "ok" || len(got.Data) != 1 || got.Data[0].Title != "热搜" {
+		t.Errorf("got %+v", got)
+	}
+
+	srv.FastForward(time.Minute)
+	if r.Get("weibo", &got) {
+		t.Error("get after ttl returned true")
+	}
+	if r.Get("missing", &got) {
+		t.Error("get missing key returned true")
+	}
+}
+
+func TestRedisLock(t *testing.T) {
+	r, srv := newTestRedis(t)
+
+	unlock, ok := r.Lock("refresh_weibo", time.Minute)
+	if !ok {
+		t.Fatal("first lock failed")
+	}
+	if _, ok := r.Lock("refresh_weibo", time.Minute); ok {
+		t.Fatal("second lock succeeded while held")
+	}
+
+	unlock()
+	unlock2, ok := r.Lock("refresh_weibo", time.Minute)
+	if !ok {
+		t.Fatal("lock after unlock failed")
+	}
+	defer unlock2()
+
+	// 锁过期后被其他实例拿到, 原持有者解锁不能删除别人的锁
+	srv.FastForward(time.Minute)
+	other, ok := r.Lock("refresh_weibo", time.Minute)
+	if !ok {
+		t.Fatal("lock after expiry failed")
+	}
+	unlock2()
+	if !srv.Exists("hots:refresh_weibo") {
+		t.Fatal("stale owner released another instance's lock")
+	}
+	other()
+	if srv.Exists("hots:refresh_weibo") {
+		t.Error("owner unlock did not release the lock")
+	}
+}
+
+func TestRedisUnavailable(t *testing.T) {
+	r, srv := newTestRedis(t)
+	srv.Close()
+
+	if _, ok := r.Lock("refresh_weibo", time.Minute); ok {
+		t.Error("lock succeeded with redis down")
+	}
+	var v string
+	if r.Get("weibo", &v) {
+		t.Error("get succeeded with redis down")
+	}
+}
@@ -0,0 +1,219 @@
+// Package client hots 接口的 Go 客户端, 响应直接使用 globals 中的类型, 调用方无需再定义一遍
+//
+//	c, err := client.New("http://127.0.0.1:8081", client.WithAPIKey(os.Getenv("HOTS_API_KEY")))
+//	resp, err := c.Hot(ctx, "weibo", nil)
+//
+// 接口返回 code 非 0 时错误为 *Error; GET、DELETE 请求在网络错误和 429/502/503/504 时按指数退避重试
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client 并发安全, 可在多个 goroutine 中共用
+type Client struct {
+	base    *url.URL
+	apiKey  string
+	http    *http.Client
+	retries int
+	backoff time.Duration
+}
+
+// Option New 的可选配置
+type Option func(*Client)
+
+// WithAPIKey 以 X-API-Key 请求头发送 API Key, 管理接口和修改数据的接口传 ADMIN_TOKEN 或 admin 权限的 Key
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithHTTPClient 替换默认的 http.Client, 如需自定义超时或代理
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithRetries 最多重试 n 次, 第 n 次重试前等待 backoff * 2^(n-1); n 为 0 时不重试, 默认重试 2 次、backoff 500ms
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = n
+		c.backoff = backoff
+	}
+}
+
+// New baseUrl 为服务端地址, 如 http://127.0.0.1:8081, 可以带路径前缀
+func New(baseUrl string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseUrl, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid base url: %s", baseUrl)
+	}
+
+	c := &Client{
+		base:    u,
+		http:    &http.Client{Timeout: time.Minute},
+		retries: 2,
+		backoff: 500 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Error 接口返回的错误, Status 为 HTTP 状态码, 业务错误时为 200
+type Error struct {
+	Status int
+	Code   int
+	Err    string
+}
+
+func (e *Error) Error() string {
+	if e.Status != http.StatusOK {
+		return fmt.Sprintf("%d %s", e.Status, e.Err)
+	}
+	return e.Err
+}
+
+// envelope 各响应共有的字段
+type envelope struct {
+	Err  string `json:"err"`
+	Code int    `json:"code"`
+}
+
+func (c *Client) url(path string, query url.Values) string {
+	u := *c.base
+	u.Path += path
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// newRequest 创建带 API Key 的请求
+func (c *Client) newRequest(ctx context.Context, method, u string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// retryable 幂等请求在网络错误或服务暂时不可用时重试
+func retryable(method string, status int, err error) bool {
+	if method != http.MethodGet && method != http.MethodDelete {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter 第 attempt 次重试前的等待时间, 响应带 Retry-After 秒数时以其为准
+func (c *Client) retryAfter(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return c.backoff * time.Duration(1<<(attempt-1))
+}
+
+// raw 发送请求并读取完整响应体, 按需重试
+func (c *Client) raw(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Response, []byte, error) {
+	var content []byte
+	if body != nil {
+		var err error
+		if content, err = json.Marshal(body); err != nil {
+			return nil, nil, err
+		}
+	}
+	u := c.url(path, query)
+
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(ctx, method, u, content)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var data []byte
+		resp, err := c.http.Do(req)
+		if err == nil {
+			data, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		if attempt >= c.retries || !retryable(method, status, err) {
+			return resp, data, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(c.retryAfter(attempt+1, resp)):
+		}
+	}
+}
+
+// do 请求 JSON 接口并解析到 v, 响应 code 非 0 时返回 *Error, 此时 v 仍会被填充
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	resp, data, err := c.raw(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	return decode(resp, data, v)
+}
+
+func decode(resp *http.Response, data []byte, v interface{}) error {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		msg := strings.TrimSpace(string(data))
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		return &Error{Status: resp.StatusCode, Code: 1, Err: msg}
+	}
+	if v != nil {
+		if err := json.Unmarshal(data, v); err != nil {
+			return err
+		}
+	}
+	if env.Code != 0 || resp.StatusCode != http.StatusOK {
+		return &Error{Status: resp.StatusCode, Code: env.Code, Err: env.Err}
+	}
+	return nil
+}
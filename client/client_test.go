@@ -0,0 +1,264 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/apikeys"
+	"github.com/turbo-uid/hots/caches"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+	"github.com/turbo-uid/hots/routers"
+	"github.com/turbo-uid/hots/snapshots"
+	"github.com/turbo-uid/hots/upstream"
+)
+
+var setupOnce sync.Once
+
+// newTestServer 运行完整的服务端, 上游请求回放 testdata/fixtures, 不访问网络
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	setupOnce.Do(func() {
+		gin.SetMode(gin.TestMode)
+		globals.GoLogger = logrus.New()
+		globals.GoLogger.SetOutput(io.Discard)
+		globals.GoCache = caches.NewMemory(cache.New(5*time.Minute, 10*time.Minute))
+		store, err := snapshots.NewStore("", snapshots.DefaultRetention())
+		if err != nil {
+			panic(err)
+		}
+		globals.GoSnapshots = store
+		leader.Standalone()
+
+		recorder, err := upstream.NewRecorder(upstream.ModeReplay, "../testdata/fixtures", nil)
+		if err != nil {
+			panic(err)
+		}
+		upstream.Use(recorder)
+	})
+
+	srv := httptest.NewServer(routers.InitRouter())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(t *testing.T, baseUrl string, opts ...Option) *Client {
+	t.Helper()
+	c, err := New(baseUrl, append([]Option{WithRetries(2, time.Millisecond)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestHot(t *testing.T) {
+	srv := newTestServer(t)
+	c := newTestClient(t, srv.URL)
+	ctx := context.Background()
+
+	// zhihu、endata-s 的路由与榜单名不同, 同样可以按榜单名请求
+	for _, board := range []string{"weibo", "zhihu", "endata-s"} {
+		resp, err := c.Hot(ctx, board, nil)
+		if err != nil {
+			t.Errorf("%s: %s", board, err)
+			continue
+		}
+		if len(resp.Data) == 0 || resp.Data[0].Title == "" || resp.Data[0].Id == "" {
+			t.Errorf("%s: got %+v", board, resp.Data)
+		}
+	}
+
+	results := c.Batch(ctx, []string{"baidu", "nope"}, nil)
+	if results[0].Err != nil || len(results[0].Resp.Data) == 0 {
+		t.Errorf("batch baidu: %+v", results[0])
+	}
+	var apiErr *Error
+	if !errors.As(results[1].Err, &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Errorf("batch unknown board err = %v, want 404", results[1].Err)
+	}
+}
+
+func TestHistoryAndSearch(t *testing.T) {
+	srv := newTestServer(t)
+	c := newTestClient(t, srv.URL)
+	ctx := context.Background()
+
+	resp, err := c.Hot(ctx, "weibo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := c.History(ctx, "weibo", HistoryOptions{From: "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) == 0 || list[len(list)-1].Platform != globals.WeiboFlag || len(list[len(list)-1].Data) != len(resp.Data) {
+		t.Fatalf("history = %+v", list)
+	}
+
+	title := resp.Data[0].Title
+	hits, total, err := c.Search(ctx, SearchOptions{Q: title, Platforms: []string{"weibo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total == 0 || hits[0].Title != title || hits[0].Platform != globals.WeiboFlag {
+		t.Errorf("search %q: total %d, hits %+v", title, total, hits)
+	}
+
+	if _, _, err := c.Search(ctx, SearchOptions{}); err == nil {
+		t.Error("empty search succeeded")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, `{"code":1,"err":"Not the leader"}`)
+			return
+		}
+		io.WriteString(w, `{"succ":"ok","code":0,"data":[{"title":"a"}]}`)
+	}))
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+
+	// GET 重试 2 次后成功
+	resp, err := newTestClient(t, srv.URL).Hot(ctx, "weibo", nil)
+	if err != nil || len(resp.Data) != 1 || calls.Load() != 3 {
+		t.Errorf("err = %v, calls = %d", err, calls.Load())
+	}
+
+	// 重试次数用尽时返回最后一次的错误
+	calls.Store(0)
+	_, err = newTestClient(t, srv.URL, WithRetries(1, time.Millisecond)).Hot(ctx, "weibo", nil)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusServiceUnavailable || apiErr.Err != "Not the leader" || calls.Load() != 2 {
+		t.Errorf("err = %v, calls = %d", err, calls.Load())
+	}
+
+	// POST 不重试
+	calls.Store(0)
+	if _, err := newTestClient(t, srv.URL).AddWatchRule(ctx, globals.WatchRule{}); err == nil || calls.Load() != 1 {
+		t.Errorf("err = %v, calls = %d", err, calls.Load())
+	}
+}
+
+func TestApiKey(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+
+	globals.ApiKeyRequired, globals.AdminToken = true, "admin-secret"
+	t.Cleanup(func() { globals.ApiKeyRequired, globals.AdminToken = false, "" })
+	key, err := apikeys.Default.Create("client-test", globals.ApiKeyRoleRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { apikeys.Default.Revoke(key.Id) })
+
+	_, err = newTestClient(t, srv.URL).Hot(ctx, "baidu", nil)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Errorf("without key err = %v, want 401", err)
+	}
+
+	c := newTestClient(t, srv.URL, WithAPIKey(key.Key))
+	if _, err := c.Hot(ctx, "baidu", nil); err != nil {
+		t.Errorf("with key: %s", err)
+	}
+	// read 权限的 Key 不能修改数据
+	if _, err := c.AddWatchRule(ctx, globals.WatchRule{Keywords: []string{"a"}}); !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Errorf("add watch rule with read key err = %v, want 401", err)
+	}
+
+	// WebSocket 握手失败时同样返回 *Error
+	if _, err := newTestClient(t, srv.URL).Dial(ctx); !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Errorf("dial without key err = %v, want 401", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	srv := newTestServer(t)
+	c := newTestClient(t, srv.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := c.Hot(ctx, "weibo", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// 连接后先收到当前快照, fn 返回错误时结束
+	stop := errors.New("stop")
+	var got StreamEvent
+	err := c.Stream(ctx, StreamOptions{Platforms: []string{"weibo"}, Mode: StreamSnapshot}, func(e StreamEvent) error {
+		got = e
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("stream err = %v", err)
+	}
+	if got.Id == "" || got.Event != "snapshot" || got.Snapshot == nil || got.Snapshot.Platform != globals.WeiboFlag {
+		t.Errorf("event = %+v", got)
+	}
+}
+
+func TestDial(t *testing.T) {
+	srv := newTestServer(t)
+	c := newTestClient(t, srv.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := c.Hot(ctx, "weibo", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := c.Dial(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	if err := conn.Subscribe([]string{"weibo"}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	// 先回复 ack, 再推送订阅榜单的当前快照
+	var types []string
+	for len(types) < 2 {
+		msg, err := conn.Read()
+		if err != nil {
+			t.Fatalf("read after %v: %s", types, err)
+		}
+		types = append(types, msg.Type)
+		if msg.Type == "snapshot" {
+			var snap globals.Snapshot
+			if err := msg.Decode(&snap); err != nil || snap.Platform != globals.WeiboFlag {
+				t.Errorf("snapshot = %+v, err = %v", snap, err)
+			}
+		}
+	}
+	if strings.Join(types, ",") != "ack,snapshot" {
+		t.Errorf("messages = %v, want ack,snapshot", types)
+	}
+
+	if err := conn.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := conn.Read(); err != nil || msg.Type != "pong" {
+		t.Errorf("ping reply = %+v, err = %v", msg, err)
+	}
+}
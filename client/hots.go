@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// HotOptions Hot 和 Aggregate 的可选参数
+type HotOptions struct {
+	// 翻译标题的目标语言, 如 en
+	Lang string
+}
+
+func (o *HotOptions) query() url.Values {
+	query := url.Values{}
+	if o != nil && o.Lang != "" {
+		query.Set("lang", o.Lang)
+	}
+	return query
+}
+
+// Hot 单个榜单, board 为路由名, 如 weibo、bili、zhihu、endata-s
+// 上游失败时服务端返回之前的榜单, 此时 resp.Stale 为 true
+func (c *Client) Hot(ctx context.Context, board string, opts *HotOptions) (globals.GblResp, error) {
+	var resp globals.GblResp
+
+	// 每个榜单都可以按 /api/hot/<name> 访问
+	err := c.do(ctx, http.MethodGet, "/api/hot/"+url.PathEscape(board), opts.query(), nil, &resp)
+	return resp, err
+}
+
+// BatchResult Batch 中一个榜单的结果
+type BatchResult struct {
+	Board string
+	Resp  globals.GblResp
+	Err   error
+}
+
+// 批量请求的并发数
+var BatchConcurrency = 4
+
+// Batch 并发请求多个榜单, 结果顺序与 boards 一致, 单个榜单失败不影响其他榜单
+// 需要合并排序的结果时用 Aggregate, 由服务端一次返回
+func (c *Client) Batch(ctx context.Context, boards []string, opts *HotOptions) []BatchResult {
+	result := make([]BatchResult, len(boards))
+	sem := make(chan struct{}, BatchConcurrency)
+
+	var wg sync.WaitGroup
+	for k, board := range boards {
+		wg.Add(1)
+		go func(k int, board string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			resp, err := c.Hot(ctx, board, opts)
+			result[k] = BatchResult{Board: board, Resp: resp, Err: err}
+		}(k, board)
+	}
+	wg.Wait()
+
+	return result
+}
+
+// AggregateOptions Aggregate 的参数, Platforms 和 Category 都为空时合并全部榜单
+type AggregateOptions struct {
+	Platforms []string
+	Category  string
+	// 每个榜单取前 Top 条, 0 为服务端默认的 10
+	Top  int
+	Lang string
+}
+
+// Aggregate 多个榜单合并输出, 条目的 Platform 标明来源
+func (c *Client) Aggregate(ctx context.Context, opts AggregateOptions) ([]globals.GblRespData, error) {
+	var resp globals.GblResp
+
+	query := (&HotOptions{Lang: opts.Lang}).query()
+	setList(query, "platforms", opts.Platforms)
+	setString(query, "category", opts.Category)
+	setInt(query, "top", opts.Top)
+
+	err := c.do(ctx, http.MethodGet, "/api/aggregate", query, nil, &resp)
+	return resp.Data, err
+}
+
+// HistoryOptions From、To 可以是快照ID、Unix 时间戳、RFC3339 时间或 24h 这样的相对时长
+type HistoryOptions struct {
+	From  string
+	To    string
+	Limit int
+}
+
+// History 榜单的历史快照, 按时间升序; platform 为路由名或标识
+func (c *Client) History(ctx context.Context, platform string, opts HistoryOptions) ([]globals.Snapshot, error) {
+	var resp globals.SnapshotListResp
+
+	query := url.Values{}
+	setString(query, "from", opts.From)
+	setString(query, "to", opts.To)
+	setInt(query, "limit", opts.Limit)
+
+	err := c.do(ctx, http.MethodGet, "/api/hot/"+url.PathEscape(platform)+"/history", query, nil, &resp)
+	return resp.Data, err
+}
+
+// Diff 对比 since 时与 to 时的快照, to 为空时对比当前榜单; 格式同 HistoryOptions
+func (c *Client) Diff(ctx context.Context, platform, since, to string) (globals.BoardDiff, error) {
+	var resp globals.DiffResp
+
+	query := url.Values{}
+	setString(query, "since", since)
+	setString(query, "to", to)
+
+	err := c.do(ctx, http.MethodGet, "/api/hot/"+url.PathEscape(platform)+"/diff", query, nil, &resp)
+	if resp.Data == nil {
+		return globals.BoardDiff{}, err
+	}
+	return *resp.Data, err
+}
+
+// SearchOptions Q 为必填的关键词, From、To 格式同 HistoryOptions
+type SearchOptions struct {
+	Q         string
+	Platforms []string
+	From      string
+	To        string
+	Limit     int
+	Offset    int
+}
+
+// Search 搜索历史快照中的标题和描述, 返回当前页的结果和结果总数
+func (c *Client) Search(ctx context.Context, opts SearchOptions) ([]globals.SearchHit, int, error) {
+	var resp globals.SearchResp
+
+	query := url.Values{}
+	setString(query, "q", opts.Q)
+	setList(query, "platforms", opts.Platforms)
+	setString(query, "from", opts.From)
+	setString(query, "to", opts.To)
+	setInt(query, "limit", opts.Limit)
+	setInt(query, "offset", opts.Offset)
+
+	err := c.do(ctx, http.MethodGet, "/api/search", query, nil, &resp)
+	return resp.Data, resp.Total, err
+}
+
+// TermsOptions 零值使用服务端默认值
+type TermsOptions struct {
+	Window    time.Duration
+	Baseline  time.Duration
+	Platforms []string
+	Top       int
+	// 最少出现的条目数
+	Min int
+}
+
+// Terms 一段时间内的热词
+func (c *Client) Terms(ctx context.Context, opts TermsOptions) (globals.TermsData, error) {
+	var resp globals.TermsResp
+
+	query := url.Values{}
+	if opts.Window > 0 {
+		query.Set("window", opts.Window.String())
+	}
+	if opts.Baseline > 0 {
+		query.Set("baseline", opts.Baseline.String())
+	}
+	setList(query, "platforms", opts.Platforms)
+	setInt(query, "top", opts.Top)
+	setInt(query, "min", opts.Min)
+
+	err := c.do(ctx, http.MethodGet, "/api/terms", query, nil, &resp)
+	return resp.Data, err
+}
+
+// Feed RSS、Atom 或 JSON Feed 订阅源的原始内容, name 如 weibo.rss、all.atom、category/tech.json
+func (c *Client) Feed(ctx context.Context, name string) ([]byte, error) {
+	resp, data, err := c.raw(ctx, http.MethodGet, "/feed/"+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, decode(resp, data, nil)
+	}
+	return data, nil
+}
+
+func setString(query url.Values, key, v string) {
+	if v != "" {
+		query.Set(key, v)
+	}
+}
+
+func setInt(query url.Values, key string, v int) {
+	if v > 0 {
+		query.Set(key, strconv.Itoa(v))
+	}
+}
+
+func setList(query url.Values, key string, v []string) {
+	if len(v) > 0 {
+		query.Set(key, strings.Join(v, ","))
+	}
+}
@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// WatchRules 全部关注规则
+func (c *Client) WatchRules(ctx context.Context) ([]globals.WatchRule, error) {
+	var resp globals.WatchRuleListResp
+	err := c.do(ctx, http.MethodGet, "/api/watch-rules", nil, nil, &resp)
+	return resp.Data, err
+}
+
+func (c *Client) WatchRule(ctx context.Context, id string) (globals.WatchRule, error) {
+	var resp globals.WatchRuleResp
+	err := c.do(ctx, http.MethodGet, "/api/watch-rules/"+url.PathEscape(id), nil, nil, &resp)
+	return resp.Data, err
+}
+
+// AddWatchRule 新增关注规则, 返回带 Id 的规则; 需要管理权限
+func (c *Client) AddWatchRule(ctx context.Context, rule globals.WatchRule) (globals.WatchRule, error) {
+	var resp globals.WatchRuleResp
+	err := c.do(ctx, http.MethodPost, "/api/watch-rules", nil, rule, &resp)
+	return resp.Data, err
+}
+
+// DeleteWatchRule 需要管理权限
+func (c *Client) DeleteWatchRule(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/watch-rules/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// AlertsOptions 按规则、平台和时间过滤, Since 格式同 HistoryOptions
+type AlertsOptions struct {
+	Rule     string
+	Platform string
+	Since    string
+	Limit    int
+}
+
+// Alerts 按时间倒序列出告警
+func (c *Client) Alerts(ctx context.Context, opts AlertsOptions) ([]globals.Alert, error) {
+	var resp globals.AlertListResp
+
+	query := url.Values{}
+	setString(query, "rule", opts.Rule)
+	setString(query, "platform", opts.Platform)
+	setString(query, "since", opts.Since)
+	setInt(query, "limit", opts.Limit)
+
+	err := c.do(ctx, http.MethodGet, "/api/alerts", query, nil, &resp)
+	return resp.Data, err
+}
+
+// Digests 最近的摘要, period 为 daily、weekly 或空表示全部
+func (c *Client) Digests(ctx context.Context, period string, limit int) ([]globals.Digest, error) {
+	var resp globals.DigestListResp
+
+	query := url.Values{}
+	setString(query, "period", period)
+	setInt(query, "limit", limit)
+
+	err := c.do(ctx, http.MethodGet, "/api/digests", query, nil, &resp)
+	return resp.Data, err
+}
+
+func (c *Client) Digest(ctx context.Context, id string) (globals.Digest, error) {
+	var resp globals.DigestResp
+	err := c.do(ctx, http.MethodGet, "/api/digests/"+url.PathEscape(id), nil, nil, &resp)
+	return resp.Data, err
+}
+
+// GenerateDigest 立即生成一期摘要, push 为 true 时同时推送到通知渠道; 需要管理权限
+func (c *Client) GenerateDigest(ctx context.Context, period string, push bool) (globals.Digest, error) {
+	var resp globals.DigestResp
+
+	query := url.Values{}
+	setString(query, "period", period)
+	if push {
+		query.Set("push", "true")
+	}
+
+	err := c.do(ctx, http.MethodPost, "/api/digests", query, nil, &resp)
+	return resp.Data, err
+}
+
+func (c *Client) Webhooks(ctx context.Context) ([]globals.Webhook, error) {
+	var resp globals.WebhookListResp
+	err := c.do(ctx, http.MethodGet, "/api/webhooks", nil, nil, &resp)
+	return resp.Data, err
+}
+
+func (c *Client) Webhook(ctx context.Context, id string) (globals.Webhook, error) {
+	var resp globals.WebhookResp
+	err := c.do(ctx, http.MethodGet, "/api/webhooks/"+url.PathEscape(id), nil, nil, &resp)
+	return resp.Data, err
+}
+
+// AddWebhook 新增回调地址, 返回的 Secret 只在此时可见; 需要管理权限
+func (c *Client) AddWebhook(ctx context.Context, hook globals.Webhook) (globals.Webhook, error) {
+	var resp globals.WebhookResp
+	err := c.do(ctx, http.MethodPost, "/api/webhooks", nil, hook, &resp)
+	return resp.Data, err
+}
+
+// DeleteWebhook 需要管理权限
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/webhooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// WebhookDeliveries 按时间倒序列出投递记录
+func (c *Client) WebhookDeliveries(ctx context.Context, id string, limit int) ([]globals.Delivery, error) {
+	var resp globals.DeliveryListResp
+
+	query := url.Values{}
+	setInt(query, "limit", limit)
+
+	err := c.do(ctx, http.MethodGet, "/api/webhooks/"+url.PathEscape(id)+"/deliveries", query, nil, &resp)
+	return resp.Data, err
+}
+
+// ReplayDelivery 重新投递, 返回新的投递记录; 需要管理权限
+func (c *Client) ReplayDelivery(ctx context.Context, id, delivery string) (globals.Delivery, error) {
+	var resp globals.DeliveryResp
+	err := c.do(ctx, http.MethodPost, "/api/webhooks/"+url.PathEscape(id)+"/deliveries/"+url.PathEscape(delivery)+"/replay", nil, nil, &resp)
+	return resp.Data, err
+}
+
+// AdminRefresh 强制刷新榜单, platforms 为空时刷新全部; 部分失败时同时返回各榜单结果和错误
+func (c *Client) AdminRefresh(ctx context.Context, platforms ...string) ([]globals.AdminResult, error) {
+	var resp globals.AdminResultResp
+
+	query := url.Values{}
+	setList(query, "platform", platforms)
+
+	err := c.do(ctx, http.MethodPost, "/api/admin/refresh", query, nil, &resp)
+	return resp.Data, err
+}
+
+// AdminReload 重新加载配置文件, 部分失败时同时返回各项结果和错误
+func (c *Client) AdminReload(ctx context.Context) ([]globals.AdminResult, error) {
+	var resp globals.AdminResultResp
+	err := c.do(ctx, http.MethodPost, "/api/admin/reload", nil, nil, &resp)
+	return resp.Data, err
+}
+
+func (c *Client) ApiKeys(ctx context.Context) ([]globals.ApiKey, error) {
+	var resp globals.ApiKeyListResp
+	err := c.do(ctx, http.MethodGet, "/api/admin/keys", nil, nil, &resp)
+	return resp.Data, err
+}
+
+// CreateApiKey role 为 read 或 admin, 返回的 Key 只在此时可见
+func (c *Client) CreateApiKey(ctx context.Context, name, role string) (globals.ApiKey, error) {
+	var resp globals.ApiKeyResp
+	body := map[string]string{"name": name, "role": role}
+	err := c.do(ctx, http.MethodPost, "/api/admin/keys", nil, body, &resp)
+	return resp.Data, err
+}
+
+func (c *Client) RevokeApiKey(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/admin/keys/"+url.PathEscape(id), nil, nil, nil)
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// SSE 推送模式
+const (
+	StreamDiff     = "diff"
+	StreamSnapshot = "snapshot"
+)
+
+// StreamOptions Platforms 为空时订阅全部榜单, Mode 为空时为 diff
+type StreamOptions struct {
+	Platforms []string
+	Mode      string
+	// 从该事件之后继续, 为空时服务端先推送各榜单当前快照
+	LastEventId string
+}
+
+// StreamEvent 一条推送, Event 为 snapshot 时 Snapshot 非空, 为 diff 时 Diff 非空
+type StreamEvent struct {
+	Id       string
+	Event    string
+	Snapshot *globals.Snapshot
+	Diff     *globals.BoardDiff
+}
+
+// Stream 订阅 /api/stream 并对每条推送调用 fn, 直到 ctx 取消或 fn 返回错误
+// 连接断开时按 WithRetries 的间隔带 Last-Event-ID 重连, 服务端据此补发断开期间的事件
+func (c *Client) Stream(ctx context.Context, opts StreamOptions, fn func(StreamEvent) error) error {
+	query := url.Values{}
+	setList(query, "platforms", opts.Platforms)
+	setString(query, "mode", opts.Mode)
+	lastId := opts.LastEventId
+
+	for attempt := 0; ; attempt++ {
+		received, fatal, err := c.stream(ctx, query, &lastId, fn)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if fatal {
+			return err
+		}
+		// 收到过事件说明连接正常过, 重新计算退避
+		if received {
+			attempt = 0
+		}
+		if attempt >= c.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.backoff * time.Duration(1<<attempt)):
+		}
+	}
+}
+
+// stream 建立一次连接并读取到断开, received 表示是否收到过事件
+// fatal 为 true 时不再重连: 接口返回了错误, 或 fn 返回了错误
+func (c *Client) stream(ctx context.Context, query url.Values, lastId *string, fn func(StreamEvent) error) (received, fatal bool, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.url("/api/stream", query), nil)
+	if err != nil {
+		return false, true, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastId != "" {
+		req.Header.Set("Last-Event-ID", *lastId)
+	}
+
+	// 长连接不能使用带超时的 http.Client
+	hc := *c.http
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, false, err
+	}
+	defer resp.Body.Close()
+
+	// 参数错误或认证失败时返回的是 JSON
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return false, false, err
+		}
+		return false, true, decode(resp, data, nil)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	var id, event string
+	var data strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			return received, false, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return received, false, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			// 空行结束一条事件
+			if data.Len() == 0 {
+				continue
+			}
+			e, err := parseStreamEvent(id, event, data.String())
+			data.Reset()
+			event = ""
+			if err != nil {
+				return received, true, err
+			}
+			received = true
+			if err := fn(e); err != nil {
+				return received, true, err
+			}
+			if id != "" {
+				*lastId = id
+			}
+		case strings.HasPrefix(line, ":"):
+			// 心跳注释
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "id":
+				id = value
+			case "event":
+				event = value
+			case "data":
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(value)
+			}
+		}
+	}
+}
+
+func parseStreamEvent(id, event, data string) (StreamEvent, error) {
+	e := StreamEvent{Id: id, Event: event}
+	switch event {
+	case StreamSnapshot:
+		e.Snapshot = &globals.Snapshot{}
+		return e, json.Unmarshal([]byte(data), e.Snapshot)
+	case StreamDiff:
+		e.Diff = &globals.BoardDiff{}
+		return e, json.Unmarshal([]byte(data), e.Diff)
+	}
+	return e, nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Message WebSocket 服务端消息, Data 按 Type 用 Decode 解析
+type Message struct {
+	Type     string          `json:"type"`
+	Seq      uint64          `json:"seq,omitempty"`
+	Platform string          `json:"platform,omitempty"`
+	Err      string          `json:"err,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+// Decode 解析 Data, v 随 Type 为 *globals.Snapshot、*globals.BoardDiff、*globals.Alert、*globals.ProviderStatus 或 *globals.WsRequest(ack)
+func (m Message) Decode(v interface{}) error {
+	if len(m.Data) == 0 {
+		return errors.New("message has no data")
+	}
+	return json.Unmarshal(m.Data, v)
+}
+
+// Conn 一个 WebSocket 连接, Read 只能在一个 goroutine 中调用, 其他方法并发安全
+type Conn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+// Dial 连接 /api/ws, 之后用 Subscribe 订阅并循环调用 Read
+// 服务端的 ping 在 Read 中自动回复; 连接断开后需要重新 Dial 并订阅
+func (c *Client) Dial(ctx context.Context) (*Conn, error) {
+	u := *c.base
+	u.Path += "/api/ws"
+	u.Scheme = strings.Replace(u.Scheme, "http", "ws", 1)
+
+	header := http.Header{}
+	if c.apiKey != "" {
+		header.Set("X-API-Key", c.apiKey)
+	}
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		// 握手失败时服务端返回的是 JSON 错误
+		if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+			data, _ := io.ReadAll(resp.Body)
+			return nil, decode(resp, data, nil)
+		}
+		return nil, err
+	}
+	return &Conn{conn: conn}, nil
+}
+
+func (cn *Conn) send(req globals.WsRequest) error {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+	return cn.conn.WriteJSON(req)
+}
+
+// Subscribe 增加订阅, 服务端回复 ack 后推送新增榜单的当前快照; 名称无效时回复 error
+func (cn *Conn) Subscribe(platforms, categories, keywords []string) error {
+	return cn.send(globals.WsRequest{Action: globals.WsActionSubscribe, Platforms: platforms, Categories: categories, Keywords: keywords})
+}
+
+func (cn *Conn) Unsubscribe(platforms, categories, keywords []string) error {
+	return cn.send(globals.WsRequest{Action: globals.WsActionUnsubscribe, Platforms: platforms, Categories: categories, Keywords: keywords})
+}
+
+// Ping 应用层心跳, 服务端回复 pong 消息
+func (cn *Conn) Ping() error {
+	return cn.send(globals.WsRequest{Action: globals.WsActionPing})
+}
+
+// Read 阻塞读取下一条消息
+func (cn *Conn) Read() (Message, error) {
+	var msg Message
+	err := cn.conn.ReadJSON(&msg)
+	return msg, err
+}
+
+// Close 正常关闭连接
+func (cn *Conn) Close() error {
+	cn.mu.Lock()
+	cn.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	cn.mu.Unlock()
+	return cn.conn.Close()
+}
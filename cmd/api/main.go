@@ -1,26 +1,314 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/turbo-uid/hots/alerts"
+	"github.com/turbo-uid/hots/apikeys"
+	"github.com/turbo-uid/hots/caches"
+	"github.com/turbo-uid/hots/digests"
+	"github.com/turbo-uid/hots/events"
 	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+	"github.com/turbo-uid/hots/notifiers"
+	"github.com/turbo-uid/hots/providers"
 	"github.com/turbo-uid/hots/routers"
+	"github.com/turbo-uid/hots/routers/api"
+	"github.com/turbo-uid/hots/search"
+	"github.com/turbo-uid/hots/sinks"
+	"github.com/turbo-uid/hots/snapshots"
 	"github.com/turbo-uid/hots/startups"
+	"github.com/turbo-uid/hots/translate"
+	"github.com/turbo-uid/hots/upstream"
+	"github.com/turbo-uid/hots/webhooks"
 
 	"github.com/gin-gonic/gin"
 	"github.com/patrickmn/go-cache"
+	"github.com/redis/go-redis/v9"
 )
 
+// 各实例约定的 Postgres advisory lock 编号
+const leaderLockId = 0x686f7473
+
 func main() {
 
 	globals.GoLogger = startups.StartupLog()
 
 	gin.SetMode(gin.ReleaseMode)
 
-	globals.GoCache = cache.New(5*time.Minute, 10*time.Minute)
+	// 默认使用进程内缓存, 多实例部署时设置 CACHE_BACKEND=redis 和 REDIS_URL 共享缓存
+	switch backend := os.Getenv("CACHE_BACKEND"); backend {
+	case "", "memory":
+		globals.GoCache = caches.NewMemory(cache.New(5*time.Minute, 10*time.Minute))
+	case "redis":
+		opts, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+		if err != nil {
+			globals.GoLogger.Fatalf("invalid REDIS_URL: %s", err.Error())
+		}
+		globals.GoCache = caches.NewRedis(redis.NewClient(opts), "hots:")
+	default:
+		globals.GoLogger.Fatalf("unknown CACHE_BACKEND: %s", backend)
+	}
+
+	// UPSTREAM_MOCK 指向 cmd/mockupstream 时所有上游请求改发到该服务
+	var upstreamBase http.RoundTripper
+	if mock := os.Getenv("UPSTREAM_MOCK"); mock != "" {
+		redirect, err := upstream.NewRedirect(mock, nil)
+		if err != nil {
+			globals.GoLogger.Fatalf("invalid UPSTREAM_MOCK: %s", err.Error())
+		}
+		upstreamBase = redirect
+		upstream.Use(redirect)
+	}
+
+	// UPSTREAM_MODE=record 时把上游响应录制到 UPSTREAM_FIXTURES, replay 时只从中回放, 不访问网络
+	if mode := os.Getenv("UPSTREAM_MODE"); mode != "" {
+		dir := os.Getenv("UPSTREAM_FIXTURES")
+		if dir == "" {
+			dir = "testdata/fixtures"
+		}
+		recorder, err := upstream.NewRecorder(mode, dir, upstreamBase)
+		if err != nil {
+			globals.GoLogger.Fatalf("invalid UPSTREAM_MODE: %s", err.Error())
+		}
+		upstream.Use(recorder)
+	}
+
+	// 设置 PROVIDERS_DIR 后加载目录下 YAML 定义的榜单, 需在构建搜索索引、初始化路由前注册
+	if dir := os.Getenv("PROVIDERS_DIR"); dir != "" {
+		list, err := providers.LoadDir(dir)
+		if err != nil {
+			globals.GoLogger.Fatalf("load providers: %s", err.Error())
+		}
+		// 重新加载时替换已有榜单的定义, 新增榜单需要重启才有路由
+		current := map[string]*atomic.Pointer[providers.Provider]{}
+		for _, p := range list {
+			ptr := &atomic.Pointer[providers.Provider]{}
+			ptr.Store(p)
+			current[p.Name] = ptr
+			fetch := func() globals.GblResp { return ptr.Load().Fetch() }
+			if err := api.RegisterBoard(api.Board{Name: p.Name, Flag: p.Flag, Category: p.Category, Fetch: fetch}); err != nil {
+				globals.GoLogger.Fatalf("register provider: %s", err.Error())
+			}
+			globals.GoLogger.Infof("REGISTER PROVIDER %s", p.Name)
+		}
+		api.RegisterReloader("providers", func() error {
+			list, err := providers.LoadDir(dir)
+			if err != nil {
+				return err
+			}
+			var added []string
+			for _, p := range list {
+				ptr, found := current[p.Name]
+				if !found || ptr.Load().Flag != p.Flag {
+					added = append(added, p.Name)
+					continue
+				}
+				ptr.Store(p)
+			}
+			if len(added) > 0 {
+				return fmt.Errorf("new providers need a restart: %s", strings.Join(added, ", "))
+			}
+			return nil
+		})
+	}
+
+	// 快照默认只保存在内存, 设置 SNAPSHOT_DIR 后落盘
+	snapshotStore, err := snapshots.NewStore(os.Getenv("SNAPSHOT_DIR"), snapshots.DefaultRetention())
+	if err != nil {
+		globals.GoLogger.Fatalf("init snapshot store: %s", err.Error())
+	}
+	globals.GoSnapshots = snapshotStore
+
+	// 设置 SINK=postgres 后快照和条目同时写入 DATABASE_URL, 搜索索引先用其中的历史条目构建
+	switch sink := os.Getenv("SINK"); sink {
+	case "", "none":
+	case "postgres":
+		pg, err := sinks.OpenPostgres(context.Background(), os.Getenv("DATABASE_URL"))
+		if err != nil {
+			globals.GoLogger.Fatalf("open postgres sink: %s", err.Error())
+		}
+		if err := pg.Items(context.Background(), search.Default.AddRecord); err != nil {
+			globals.GoLogger.Errorf("LOAD SEARCH INDEX FROM POSTGRES ERR %s", err.Error())
+		}
+		go sinks.Default.Run(pg)
+	default:
+		globals.GoLogger.Fatalf("unknown SINK: %s", sink)
+	}
+	search.Default.Build(snapshotStore)
+
+	// 标题翻译, 设置 TRANSLATE_FILE 后支持 ?lang=
+	if file := os.Getenv("TRANSLATE_FILE"); file != "" {
+		if err := translate.Default.Load(file); err != nil {
+			globals.GoLogger.Fatalf("load translate config: %s", err.Error())
+		}
+		go translate.Default.Run()
+		api.RegisterReloader("translate", func() error { return translate.Default.Load(file) })
+	}
+
+	// 群机器人, 需在加载关注规则前注册
+	var bots []*notifiers.Bot
+	if file := os.Getenv("NOTIFIERS_FILE"); file != "" {
+		if bots, err = notifiers.Load(file); err != nil {
+			globals.GoLogger.Fatalf("load notifiers: %s", err.Error())
+		}
+		for _, b := range bots {
+			alerts.Default.RegisterNotifier(b)
+		}
+	}
+
+	// 关注规则默认只保存在内存, 设置 WATCH_RULES_FILE 后落盘
+	if file := os.Getenv("WATCH_RULES_FILE"); file != "" {
+		if err := alerts.Default.Load(file); err != nil {
+			globals.GoLogger.Fatalf("load watch rules: %s", err.Error())
+		}
+		api.RegisterReloader("watch_rules", func() error { return alerts.Default.Load(file) })
+	}
+
+	// Webhook 默认不能投递到内网地址, WEBHOOK_ALLOW_NETS 放行指定的 IP 或网段
+	for _, v := range strings.Split(os.Getenv("WEBHOOK_ALLOW_NETS"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(v); err != nil && net.ParseIP(v) == nil {
+			globals.GoLogger.Fatalf("invalid WEBHOOK_ALLOW_NETS: %s", v)
+		}
+		globals.WebhookAllowNets = append(globals.WebhookAllowNets, v)
+	}
+
+	// Webhook 默认只保存在内存, 设置 WEBHOOKS_FILE 后落盘
+	if file := os.Getenv("WEBHOOKS_FILE"); file != "" {
+		if err := webhooks.Default.Load(file); err != nil {
+			globals.GoLogger.Fatalf("load webhooks: %s", err.Error())
+		}
+		api.RegisterReloader("webhooks", func() error { return webhooks.Default.Load(file) })
+	}
+	go webhooks.Default.Run(events.Default)
+
+	// 摘要默认只保存在内存, 设置 DIGEST_DIR 后落盘
+	if dir := os.Getenv("DIGEST_DIR"); dir != "" {
+		if err := digests.Default.Load(dir); err != nil {
+			globals.GoLogger.Fatalf("load digests: %s", err.Error())
+		}
+	}
+	for _, name := range strings.Split(os.Getenv("DIGEST_NOTIFIERS"), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		found := false
+		for _, b := range bots {
+			if b.Name() == name {
+				digests.Default.Senders = append(digests.Default.Senders, b)
+				found = true
+			}
+		}
+		if !found {
+			globals.GoLogger.Fatalf("unknown DIGEST_NOTIFIERS: %s", name)
+		}
+	}
+	if addr, to := os.Getenv("SMTP_ADDR"), os.Getenv("DIGEST_MAIL_TO"); addr != "" && to != "" {
+		digests.Default.Mailer = &digests.Mailer{
+			Addr: addr,
+			User: os.Getenv("SMTP_USER"),
+			Pass: os.Getenv("SMTP_PASS"),
+			From: os.Getenv("SMTP_FROM"),
+			To:   strings.Split(to, ","),
+		}
+	}
+
+	// 定时生成摘要, 设为 off 关闭
+	var schedules []digests.Schedule
+	for _, v := range []struct{ period, env, def string }{
+		{globals.DigestDaily, "DIGEST_DAILY_AT", "08:00"},
+		{globals.DigestWeekly, "DIGEST_WEEKLY_AT", "Mon 08:00"},
+	} {
+		s := os.Getenv(v.env)
+		if s == "" {
+			s = v.def
+		}
+		if s == "off" {
+			continue
+		}
+		schedule, err := digests.ParseSchedule(v.period, s)
+		if err != nil {
+			globals.GoLogger.Fatalf("invalid %s: %s", v.env, err.Error())
+		}
+		schedules = append(schedules, schedule)
+	}
+	go digests.Default.Run(schedules)
+
+	// 管理接口使用 ADMIN_TOKEN 或 admin 权限的 API Key; API_KEY_REQUIRED=true 时其他接口也需要 API Key
+	globals.AdminToken = os.Getenv("ADMIN_TOKEN")
+	globals.ApiKeyRequired = os.Getenv("API_KEY_REQUIRED") == "true"
+	// API Key 默认只保存在内存, 设置 API_KEYS_FILE 后落盘
+	if file := os.Getenv("API_KEYS_FILE"); file != "" {
+		if err := apikeys.Default.Load(file); err != nil {
+			globals.GoLogger.Fatalf("load api keys: %s", err.Error())
+		}
+		api.RegisterReloader("api_keys", func() error { return apikeys.Default.Load(file) })
+	}
+
+	// 多实例部署时选举 leader, 只有 leader 请求上游和推送通知, 从实例读取共享缓存
+	leaderTTL := 15 * time.Second
+	if s := os.Getenv("LEADER_TTL"); s != "" {
+		if leaderTTL, err = time.ParseDuration(s); err != nil {
+			globals.GoLogger.Fatalf("invalid LEADER_TTL: %s", err.Error())
+		}
+	}
+	var elector leader.Elector
+	switch election := os.Getenv("LEADER_ELECTION"); election {
+	case "", "none":
+	case "file":
+		file := os.Getenv("LEADER_LOCK_FILE")
+		if file == "" {
+			file = "hots.leader.lock"
+		}
+		elector = leader.NewFileLock(file)
+	case "redis":
+		opts, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+		if err != nil {
+			globals.GoLogger.Fatalf("invalid REDIS_URL: %s", err.Error())
+		}
+		hostname, _ := os.Hostname()
+		elector = leader.NewRedis(redis.NewClient(opts), "hots:leader", fmt.Sprintf("%s:%d", hostname, os.Getpid()), leaderTTL)
+	case "postgres":
+		elector = leader.NewPostgres(os.Getenv("DATABASE_URL"), leaderLockId)
+	default:
+		globals.GoLogger.Fatalf("unknown LEADER_ELECTION: %s", election)
+	}
+	if elector != nil {
+		if _, ok := globals.GoCache.(*caches.Memory); ok {
+			globals.GoLogger.Fatalf("LEADER_ELECTION requires CACHE_BACKEND=redis")
+		}
+		go leader.Run(context.Background(), elector, leaderTTL/3)
+		// API Key 只能在 leader 上修改, 从实例从共享缓存同步
+		go apikeys.Default.SyncLoop(leaderTTL / 3)
+	} else {
+		leader.Standalone()
+	}
+
+	// 定时刷新榜单, 默认每分钟一次; SSE、WebSocket 推送和从实例同步都依赖定时刷新, REFRESH_INTERVAL=off 关闭
+	refreshInterval := time.Minute
+	if s := os.Getenv("REFRESH_INTERVAL"); s == "off" {
+		refreshInterval = 0
+	} else if s != "" {
+		if refreshInterval, err = time.ParseDuration(s); err != nil || refreshInterval <= 0 {
+			globals.GoLogger.Fatalf("invalid REFRESH_INTERVAL: %s, use a positive duration or off", s)
+		}
+	}
+	if refreshInterval > 0 {
+		go api.RefreshLoop(refreshInterval)
+	} else if elector != nil {
+		// 从实例只读取 leader 定时刷新写入的共享缓存
+		globals.GoLogger.Fatalf("LEADER_ELECTION requires REFRESH_INTERVAL, it cannot be off")
+	}
 
 	routersInit := routers.InitRouter()
 	readTimeout := 10 * time.Second
@@ -0,0 +1,158 @@
+// golden 用录制的上游响应检查各榜单的解析结果, 回放时不访问网络, 可在 CI 中运行
+//
+//	go run ./cmd/golden                  回放 fixture, 与 golden 文件对比, 不一致时退出码为 1
+//	go run ./cmd/golden -update          回放 fixture 并重写 golden 文件
+//	go run ./cmd/golden -record          请求真实上游, 重新录制 fixture 和 golden 文件
+//	go run ./cmd/golden -record -mock http://127.0.0.1:9090   改为从 cmd/mockupstream 录制
+//	go run ./cmd/golden -boards weibo    只检查部分榜单
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/providers"
+	"github.com/turbo-uid/hots/routers/api"
+	"github.com/turbo-uid/hots/upstream"
+)
+
+func main() {
+	fixtures := flag.String("fixtures", "testdata/fixtures", "fixture 目录")
+	golden := flag.String("golden", "testdata/golden", "golden 文件目录")
+	record := flag.Bool("record", false, "请求真实上游并重新录制")
+	update := flag.Bool("update", false, "用回放结果重写 golden 文件")
+	boards := flag.String("boards", "", "逗号分隔的榜单, 默认全部")
+	strict := flag.Bool("strict", false, "缺少 fixture 时视为失败")
+	mock := flag.String("mock", "", "录制时改发到该 mock 服务, 如 cmd/mockupstream 的地址")
+	providersDir := flag.String("providers", os.Getenv("PROVIDERS_DIR"), "同时检查该目录下 YAML 定义的榜单")
+	flag.Parse()
+
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetLevel(logrus.WarnLevel)
+
+	if *providersDir != "" {
+		list, err := providers.LoadDir(*providersDir)
+		if err != nil {
+			fatal("load providers: %s", err.Error())
+		}
+		for _, p := range list {
+			if err := api.RegisterBoard(api.Board{Name: p.Name, Flag: p.Flag, Category: p.Category, Fetch: p.Fetch}); err != nil {
+				fatal("register provider: %s", err.Error())
+			}
+		}
+	}
+
+	list, unknown := api.ParsePlatforms(*boards)
+	if unknown != "" {
+		fatal("unknown board: %s", unknown)
+	}
+
+	mode := upstream.ModeReplay
+	if *record {
+		mode = upstream.ModeRecord
+	}
+	// fixture 仍按原始上游地址保存, 回放时与真实录制的没有区别
+	var base http.RoundTripper
+	if *mock != "" {
+		redirect, err := upstream.NewRedirect(*mock, nil)
+		if err != nil {
+			fatal("%s", err.Error())
+		}
+		base = redirect
+	}
+	recorder, err := upstream.NewRecorder(mode, *fixtures, base)
+	if err != nil {
+		fatal("%s", err.Error())
+	}
+	upstream.Use(recorder)
+
+	var passed, failed, skipped int
+	for _, b := range list {
+		resultResp := b.Fetch()
+		if missing := recorder.TakeMissing(); len(missing) > 0 {
+			fmt.Printf("SKIP %s: no fixture for %s\n", b.Name, strings.Join(missing, ", "))
+			skipped++
+			continue
+		}
+
+		got, err := encode(resultResp)
+		if err != nil {
+			fatal("%s: %s", b.Name, err.Error())
+		}
+
+		file := filepath.Join(*golden, b.Flag+".json")
+		if *record || *update {
+			if err := os.MkdirAll(*golden, 0755); err != nil {
+				fatal("%s", err.Error())
+			}
+			if err := os.WriteFile(file, got, 0644); err != nil {
+				fatal("%s", err.Error())
+			}
+			fmt.Printf("WROTE %s: %d items\n", file, len(resultResp.Data))
+			passed++
+			continue
+		}
+
+		want, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Printf("FAIL %s: %s\n", b.Name, err.Error())
+			failed++
+			continue
+		}
+		if line, w, g := firstDiff(want, got); line > 0 {
+			fmt.Printf("FAIL %s: %s line %d\n  want: %s\n  got:  %s\n", b.Name, file, line, w, g)
+			failed++
+			continue
+		}
+
+		fmt.Printf("ok   %s: %d items\n", b.Name, len(resultResp.Data))
+		passed++
+	}
+
+	fmt.Printf("%d passed, %d failed, %d skipped\n", passed, failed, skipped)
+	if failed > 0 || (*strict && skipped > 0) {
+		os.Exit(1)
+	}
+}
+
+// encode 输出与接口一致的 JSON, 缩进便于对比
+func encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(v)
+	return buf.Bytes(), err
+}
+
+// firstDiff 第一处不同的行号和两边的内容, 相同时行号为 0
+func firstDiff(want, got []byte) (int, string, string) {
+	w := strings.Split(string(want), "\n")
+	g := strings.Split(string(got), "\n")
+	for i := 0; i < len(w) || i < len(g); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl {
+			return i + 1, strings.TrimSpace(wl), strings.TrimSpace(gl)
+		}
+	}
+	return 0, "", ""
+}
+
+func fatal(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
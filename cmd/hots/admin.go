@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/turbo-uid/hots/client"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// newAdminFlags 管理命令共用的 -server、-token 标志, 令牌为 ADMIN_TOKEN 或 admin 权限的 API Key
+func newAdminFlags(name string) (*flag.FlagSet, func() (*client.Client, error)) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	server := fs.String("server", getenv("HOTS_SERVER", "http://127.0.0.1:8081"), "服务端地址, 默认 HOTS_SERVER")
+	token := fs.String("token", os.Getenv("HOTS_ADMIN_TOKEN"), "管理令牌, 默认 HOTS_ADMIN_TOKEN")
+	return fs, func() (*client.Client, error) {
+		if *token == "" {
+			return nil, errors.New("admin token is required, set -token or HOTS_ADMIN_TOKEN")
+		}
+		// 刷新全部榜单可能较慢
+		return client.New(*server, client.WithAPIKey(*token), client.WithHTTPClient(&http.Client{Timeout: 5 * time.Minute}))
+	}
+}
+
+func runAdmin(args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected admin command: refresh, reload or keys")
+	}
+	switch args[0] {
+	case "refresh":
+		return runAdminRefresh(args[1:])
+	case "reload":
+		return runAdminReload(args[1:])
+	case "keys":
+		return runAdminKeys(args[1:])
+	}
+	return fmt.Errorf("unknown admin command: %s", args[0])
+}
+
+// printResults 输出各项结果, 出错时仍然输出成功的部分
+func printResults(results []globals.AdminResult, err error) error {
+	if len(results) > 0 {
+		rows := [][]string{{"NAME", "OK", "ITEMS", "ERR"}}
+		for _, r := range results {
+			count := ""
+			if r.Count > 0 {
+				count = fmt.Sprint(r.Count)
+			}
+			rows = append(rows, []string{r.Name, fmt.Sprint(r.Ok), count, r.Err})
+		}
+		printTable(os.Stdout, rows)
+	}
+	return err
+}
+
+func runAdminRefresh(args []string) error {
+	fs, newClient := newAdminFlags("admin refresh")
+	positional := parseArgs(fs, args)
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	// 逗号分隔和空格分隔都可以
+	var platforms []string
+	for _, v := range positional {
+		platforms = append(platforms, strings.Split(v, ",")...)
+	}
+	return printResults(c.AdminRefresh(context.Background(), platforms...))
+}
+
+func runAdminReload(args []string) error {
+	fs, newClient := newAdminFlags("admin reload")
+	parseArgs(fs, args)
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	results, err := c.AdminReload(context.Background())
+	if err == nil && len(results) == 0 {
+		fmt.Println("no reloadable config enabled")
+	}
+	return printResults(results, err)
+}
+
+func runAdminKeys(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	fs, newClient := newAdminFlags("admin keys " + action)
+	name := fs.String("name", "", "create: Key 的名称, 如使用方的服务名")
+	role := fs.String("role", globals.ApiKeyRoleRead, "create: 权限, read 或 admin")
+	positional := parseArgs(fs, args)
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch action {
+	case "list":
+		keys, err := c.ApiKeys(ctx)
+		if err != nil {
+			return err
+		}
+		rows := [][]string{{"ID", "NAME", "ROLE", "PREFIX", "CREATED_AT"}}
+		for _, k := range keys {
+			rows = append(rows, []string{k.Id, k.Name, k.Role, k.Prefix, k.CreatedAt.Local().Format(time.DateTime)})
+		}
+		printTable(os.Stdout, rows)
+		return nil
+	case "create":
+		key, err := c.CreateApiKey(ctx, *name, *role)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("id:   %s\nname: %s\nrole: %s\nkey:  %s\n", key.Id, key.Name, key.Role, key.Key)
+		fmt.Fprintln(os.Stderr, "the key is only shown once, store it now")
+		return nil
+	case "revoke":
+		if len(positional) != 1 {
+			return errors.New("expected exactly one key id")
+		}
+		return c.RevokeApiKey(ctx, positional[0])
+	}
+	return fmt.Errorf("unknown keys command: %s", action)
+}
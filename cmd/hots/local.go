@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/turbo-uid/hots/formats"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/routers/api"
+	"github.com/turbo-uid/hots/snapshots"
+	"github.com/turbo-uid/hots/utils"
+)
+
+func runBoards(args []string) error {
+	fs := flag.NewFlagSet("boards", flag.ExitOnError)
+	parseArgs(fs, args)
+	if err := setupLocal(); err != nil {
+		return err
+	}
+
+	rows := [][]string{{"NAME", "FLAG", "CATEGORY"}}
+	for _, b := range api.Boards {
+		rows = append(rows, []string{b.Name, b.Flag, b.Category})
+	}
+	printTable(os.Stdout, rows)
+	return nil
+}
+
+// fetchBoard 直接调用榜单代码请求上游, 不经过缓存; 条目ID与服务端一致
+func fetchBoard(b api.Board) (globals.GblResp, error) {
+	resp := b.Fetch()
+	if resp.Code != 0 {
+		return resp, fmt.Errorf("fetch %s: %s", b.Name, resp.Err)
+	}
+	for k := range resp.Data {
+		resp.Data[k].Id = utils.GetItemId(b.Flag, resp.Data[k].Id, resp.Data[k].Title)
+	}
+	return resp, nil
+}
+
+func limitItems(data []globals.GblRespData, top int) []globals.GblRespData {
+	if top > 0 && len(data) > top {
+		return data[:top]
+	}
+	return data
+}
+
+func runFetch(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ExitOnError)
+	output := fs.String("o", "table", "输出格式: table, json, csv, ndjson, markdown")
+	top := fs.Int("top", 0, "只输出前 n 条, 0 为全部")
+	positional := parseArgs(fs, args)
+	if err := setupLocal(); err != nil {
+		return err
+	}
+
+	b, err := findBoard(positional)
+	if err != nil {
+		return err
+	}
+	resp, err := fetchBoard(b)
+	if err != nil {
+		return err
+	}
+	return printItems(limitItems(resp.Data, *top), *output)
+}
+
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := fs.Duration("interval", time.Minute, "请求间隔")
+	top := fs.Int("top", 0, "只关注前 n 条, 0 为全部")
+	positional := parseArgs(fs, args)
+	if err := setupLocal(); err != nil {
+		return err
+	}
+
+	b, err := findBoard(positional)
+	if err != nil {
+		return err
+	}
+	if *interval < time.Second {
+		return errors.New("interval must be at least 1s")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var prev globals.Snapshot
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		resp, err := fetchBoard(b)
+		if err != nil {
+			// 上游偶尔失败时继续等待下一次
+			fmt.Fprintf(os.Stderr, "%s %s\n", now.Format(time.TimeOnly), err.Error())
+		} else {
+			snap := globals.Snapshot{Id: snapshots.NewId(now), Platform: b.Flag, FetchedAt: now, Data: limitItems(resp.Data, *top)}
+			if prev.Id == "" {
+				fmt.Printf("%s %s %d items\n", now.Format(time.TimeOnly), b.Name, len(snap.Data))
+				printTable(os.Stdout, itemRows(snap.Data))
+			} else {
+				printChanges(now, snapshots.Diff(prev, snap))
+			}
+			prev = snap
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// printChanges watch 和 diff 的文本输出, 热度变化只在 diff 中列出
+func printChanges(t time.Time, d globals.BoardDiff) {
+	prefix := t.Format(time.TimeOnly)
+	if d.Empty() {
+		fmt.Printf("%s no changes\n", prefix)
+		return
+	}
+	for _, e := range d.Entered {
+		fmt.Printf("%s + #%-3d %s\n", prefix, e.NewPos, e.Title)
+	}
+	for _, e := range d.Left {
+		fmt.Printf("%s - #%-3d %s\n", prefix, e.OldPos, e.Title)
+	}
+	for _, e := range d.MovedUp {
+		fmt.Printf("%s ↑ #%-3d %s (was #%d)\n", prefix, e.NewPos, e.Title, e.OldPos)
+	}
+	for _, e := range d.MovedDown {
+		fmt.Printf("%s ↓ #%-3d %s (was #%d)\n", prefix, e.NewPos, e.Title, e.OldPos)
+	}
+}
+
+// openStore 只读打开本地快照目录, 目录不存在时报错而不是创建
+func openStore(dir string) (*snapshots.Store, error) {
+	if dir == "" {
+		return nil, errors.New("snapshot dir is required, set -store or SNAPSHOT_DIR")
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	return snapshots.NewStore(dir, snapshots.DefaultRetention())
+}
+
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	store := fs.String("store", os.Getenv("SNAPSHOT_DIR"), "快照目录, 默认 SNAPSHOT_DIR")
+	from := fs.String("from", "", "起始时间: Unix 时间戳、RFC3339 或 24h 这样的相对时长")
+	to := fs.String("to", "", "结束时间, 格式同 -from")
+	limit := fs.Int("limit", 20, "最多输出最近的 n 份快照")
+	output := fs.String("o", "table", "输出格式: table, json, csv, ndjson, markdown; 后三种展开为条目")
+	positional := parseArgs(fs, args)
+	if err := setupLocal(); err != nil {
+		return err
+	}
+
+	b, err := findBoard(positional)
+	if err != nil {
+		return err
+	}
+	s, err := openStore(*store)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var fromTime, toTime time.Time
+	if *from != "" {
+		if fromTime, err = utils.ParseTime(*from, now); err != nil {
+			return fmt.Errorf("invalid -from: %w", err)
+		}
+	}
+	if *to != "" {
+		if toTime, err = utils.ParseTime(*to, now); err != nil {
+			return fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+
+	list := s.List(b.Flag, fromTime, toTime)
+	if *limit > 0 && len(list) > *limit {
+		list = list[len(list)-*limit:]
+	}
+
+	switch *output {
+	case "", "table":
+		rows := [][]string{{"ID", "FETCHED_AT", "ITEMS", "TOP"}}
+		for _, snap := range list {
+			first := ""
+			if len(snap.Data) > 0 {
+				first = truncate(snap.Data[0].Title, titleWidth)
+			}
+			rows = append(rows, []string{snap.Id, snap.FetchedAt.Local().Format(time.DateTime), fmt.Sprint(len(snap.Data)), first})
+		}
+		printTable(os.Stdout, rows)
+		return nil
+	case formats.JSON:
+		return printJSON(list)
+	}
+
+	format := formats.Parse(*output)
+	if format == "" {
+		return fmt.Errorf("unsupported output: %s", *output)
+	}
+	content, err := formats.SnapshotsTable(list).Render(format)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(content)
+	return err
+}
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	store := fs.String("store", os.Getenv("SNAPSHOT_DIR"), "快照目录, 默认 SNAPSHOT_DIR")
+	since := fs.String("since", "", "对比的起点: 快照ID、Unix 时间戳、RFC3339 或 1h 这样的相对时长")
+	to := fs.String("to", "", "对比的终点, 格式同 -since, 默认最新一份快照")
+	output := fs.String("o", "table", "输出格式: table, json")
+	positional := parseArgs(fs, args)
+	if err := setupLocal(); err != nil {
+		return err
+	}
+
+	b, err := findBoard(positional)
+	if err != nil {
+		return err
+	}
+	if *since == "" {
+		return errors.New("-since is required")
+	}
+	s, err := openStore(*store)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var toSnap globals.Snapshot
+	var found bool
+	if *to != "" {
+		toSnap, found = snapshots.Find(s, b.Flag, *to, now)
+	} else {
+		toSnap, found = s.Latest(b.Flag)
+	}
+	if !found {
+		return errors.New("no snapshot found for -to")
+	}
+	fromSnap, found := snapshots.Find(s, b.Flag, *since, now)
+	if !found {
+		return errors.New("no snapshot found for -since")
+	}
+
+	d := snapshots.Diff(fromSnap, toSnap)
+	switch *output {
+	case "", "table":
+		fmt.Printf("%s %s -> %s\n", b.Name, d.From, d.To)
+		printChanges(toSnap.FetchedAt.Local(), d)
+		for _, e := range d.HeatChanged {
+			fmt.Printf("%s ~ #%-3d %s %s -> %s (%+d)\n", toSnap.FetchedAt.Local().Format(time.TimeOnly), e.NewPos, e.Title, e.OldHotVal, e.NewHotVal, e.HotDelta)
+		}
+		return nil
+	case formats.JSON:
+		return printJSON(d)
+	}
+	return fmt.Errorf("unsupported output: %s", *output)
+}
@@ -0,0 +1,131 @@
+// hots 命令行工具: 不启动服务直接请求榜单, 查看本地快照的历史和变化, 以及调用服务端的管理接口
+//
+//	hots boards                                  列出全部榜单
+//	hots fetch weibo -o table|json|csv|ndjson|markdown -top 10
+//	hots watch weibo -interval 1m                定时请求, 输出新上榜、下榜和排名变化
+//	hots history weibo -store data -from 24h     本地快照目录中的历史
+//	hots diff weibo -since 1h -to <快照ID>        对比两份本地快照
+//	hots admin refresh weibo,baidu               以下命令请求 -server 指定的服务端
+//	hots admin reload
+//	hots admin keys list|create|revoke
+//
+// 和服务端一样读取 PROVIDERS_DIR 加载自定义榜单, 设置 UPSTREAM_MOCK 时请求 cmd/mockupstream。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/providers"
+	"github.com/turbo-uid/hots/routers/api"
+	"github.com/turbo-uid/hots/upstream"
+)
+
+const usage = `usage: hots <command> [flags]
+
+commands:
+  boards                     list boards
+  fetch <board>              fetch a board directly from upstream
+  watch <board>              fetch periodically and print changes
+  history <board>            list snapshots in the local store
+  diff <board>               compare two snapshots in the local store
+  admin refresh [boards]     force the server to refresh boards
+  admin reload               reload server config files
+  admin keys [list|create|revoke]
+                             manage api keys
+
+run "hots <command> -h" for flags`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetLevel(logrus.WarnLevel)
+
+	cmd, args := os.Args[1], os.Args[2:]
+	var err error
+	switch cmd {
+	case "boards":
+		err = runBoards(args)
+	case "fetch":
+		err = runFetch(args)
+	case "watch":
+		err = runWatch(args)
+	case "history":
+		err = runHistory(args)
+	case "diff":
+		err = runDiff(args)
+	case "admin":
+		err = runAdmin(args)
+	case "-h", "-help", "--help", "help":
+		fmt.Println(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n%s\n", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "hots: "+err.Error())
+		os.Exit(1)
+	}
+}
+
+// parseArgs 允许标志写在位置参数之后, 如 hots fetch weibo -o json
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// setupLocal 直接请求上游前的准备: 上游改发到 mock, 注册自定义榜单
+func setupLocal() error {
+	if mock := os.Getenv("UPSTREAM_MOCK"); mock != "" {
+		redirect, err := upstream.NewRedirect(mock, nil)
+		if err != nil {
+			return fmt.Errorf("invalid UPSTREAM_MOCK: %w", err)
+		}
+		upstream.Use(redirect)
+	}
+
+	if dir := os.Getenv("PROVIDERS_DIR"); dir != "" {
+		list, err := providers.LoadDir(dir)
+		if err != nil {
+			return fmt.Errorf("load providers: %w", err)
+		}
+		for _, p := range list {
+			if err := api.RegisterBoard(api.Board{Name: p.Name, Flag: p.Flag, Category: p.Category, Fetch: p.Fetch}); err != nil {
+				return fmt.Errorf("register provider: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func findBoard(args []string) (api.Board, error) {
+	if len(args) != 1 {
+		return api.Board{}, fmt.Errorf("expected exactly one board")
+	}
+	b, ok := api.FindBoard(args[0])
+	if !ok {
+		return api.Board{}, fmt.Errorf("unknown board: %s", args[0])
+	}
+	return b, nil
+}
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/turbo-uid/hots/formats"
+	"github.com/turbo-uid/hots/globals"
+	"golang.org/x/text/width"
+)
+
+// 表格中标题的最大显示宽度
+const titleWidth = 48
+
+// displayWidth 终端中的显示宽度, 中文等全角字符占两列
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// truncate 按显示宽度截断, 超出时以 … 结尾
+func truncate(s string, max int) string {
+	if displayWidth(s) <= max {
+		return s
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		w := displayWidth(string(r))
+		if n+w > max-1 {
+			break
+		}
+		b.WriteRune(r)
+		n += w
+	}
+	return b.String() + "…"
+}
+
+// printTable 按显示宽度对齐输出, 第一行为表头
+func printTable(w io.Writer, rows [][]string) {
+	widths := map[int]int{}
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := displayWidth(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	}
+}
+
+func itemRows(data []globals.GblRespData) [][]string {
+	rows := [][]string{{"POS", "TITLE", "HOT", "LABEL"}}
+	for _, v := range data {
+		pos := fmt.Sprint(v.Pos)
+		if v.IsTop == 1 {
+			pos = "TOP"
+		}
+		rows = append(rows, []string{pos, truncate(v.Title, titleWidth), v.HotVal, v.Lab})
+	}
+	return rows
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printItems 输出榜单条目, table 之外的格式与接口的 ?format= 一致
+func printItems(data []globals.GblRespData, output string) error {
+	switch output {
+	case "", "table":
+		printTable(os.Stdout, itemRows(data))
+		return nil
+	case formats.JSON:
+		return printJSON(data)
+	}
+
+	format := formats.Parse(output)
+	if format == "" {
+		return fmt.Errorf("unsupported output: %s", output)
+	}
+	content, err := formats.ItemsTable(data).Render(format)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(content)
+	return err
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"reflect"
+
+	"github.com/turbo-uid/hots/routers/api"
+)
+
+// htmlItem 页面类上游的一个条目, Index 为空表示置顶
+type htmlItem struct {
+	Index string
+	Title string
+	Desc  string
+	Url   string
+	Img   string
+	Hot   string
+	Label string
+}
+
+func (g *generator) htmlItems(n int, top bool) []htmlItem {
+	items := make([]htmlItem, n)
+	for i := range items {
+		pos := i + 1
+		// 置顶条目不占排名
+		if top {
+			pos = i
+		}
+		items[i] = htmlItem{
+			Index: fmt.Sprint(pos),
+			Title: g.title(),
+			Desc:  g.sentence(),
+			Url:   g.link(),
+			Img:   g.image(),
+			Hot:   fmt.Sprint(g.hot()),
+			Label: g.pick(labels),
+		}
+	}
+	if top && n > 0 {
+		items[0].Index = ""
+	}
+	return items
+}
+
+// 页面结构参照各站点当前的 class 名称, 只保留解析用到的部分
+var (
+	baiduPage = template.Must(template.New("baidu").Parse(`<!DOCTYPE html><html><head><meta charset="utf-8"><title>百度热搜</title></head><body><div id="sanRoot"><main><div class="container-bg_lQ801"><div style="margin-bottom:20px">
+{{range .}}<div class="category-wrap_iQLoo horizontal_1eKyQ"><a class="img-wrapper_29V76" href="{{.Url}}"><div class="index_1Ew5p c-index-bg{{.Index}}">{{.Index}}</div><img src="{{.Img}}" alt=""></a><div class="trend_2RttY hide-icon"><div class="hot-index_1Bl1a"> {{.Hot}} </div><div class="text_1lUwZ">热搜指数</div></div><div class="content_1YWBm"><a href="{{.Url}}" class="title_dIF3B "><div class="c-single-text-ellipsis">  {{.Title}} </div><div class="c-text hot-tag_1G080">{{.Label}}</div></a><div class="hot-desc_1m_jR small_Uvkd3 ">{{.Desc}}<a href="{{.Url}}" class="look-more_3oNWC">查看更多&gt;</a></div></div></div>
+{{end}}</div></div></main></div></body></html>`))
+
+	ithomePage = template.Must(template.New("ithome").Parse(`<!DOCTYPE html><html><head><meta charset="utf-8"><title>IT之家排行榜</title></head><body>
+{{range .}}<div class="rank-box">{{range .}}<div class="placeholder"><a href="{{.Url}}"><div class="plc-image"><img data-original="{{.Img}}" src=""></div><div class="plc-con"><p class="plc-title">{{.Title}}</p><span class="review-num">{{.Hot}}评</span></div><span class="rank-num">{{.Index}}</span></a></div>
+{{end}}</div>
+{{end}}</body></html>`))
+
+	cheshiPage = template.Must(template.New("cheshi").Parse(`<!DOCTYPE html><html><head><meta charset="utf-8"><title>网上车市</title></head><body><div class="fall_box">
+{{range .}}<div class="fall_list"><div class="list_img"><a href="{{.Url}}"><img data-original="{{.Img}}" src=""></a></div><div class="list_txt"><h3><a href="{{.Url}}">{{.Title}}</a></h3><p class="txt">
+	{{.Desc}}
+</p></div></div>
+{{end}}</div></body></html>`))
+
+	zhihuPage = template.Must(template.New("zhihu").Parse(`<!doctype html><html lang="zh"><head><meta charset="utf-8"><title>知乎热榜</title></head><body><div id="root"><main><div class="Card">
+{{range .Items}}<div class="HotList-item"><div class="HotList-itemPre"><div class="HotList-itemIndex {{if not .Index}}HotList-itemIndexHot{{end}}">{{.Index}}</div></div><div class="HotList-itemBody"><div class="HotList-itemTitle">{{.Title}}</div><div class="HotList-itemExcerpt">{{.Desc}}</div><div class="HotList-itemMetrics">{{.Hot}}</div></div><div class="HotList-itemImgContainer"><img src="{{.Img}}" alt=""></div></div>
+{{end}}</div></main></div><script id="js-initialData" type="text/json">{{.Data}}</script></body></html>`))
+)
+
+func renderBaidu(g *generator) ([]byte, error) {
+	var buf bytes.Buffer
+	err := baiduPage.Execute(&buf, g.htmlItems(g.items+1, true))
+	return buf.Bytes(), err
+}
+
+// IT之家把日榜、周榜、月榜放在同一页, 各 10 条
+func renderItHome(g *generator) ([]byte, error) {
+	var buf bytes.Buffer
+	err := ithomePage.Execute(&buf, [][]htmlItem{g.htmlItems(10, false), g.htmlItems(10, false), g.htmlItems(10, false)})
+	return buf.Bytes(), err
+}
+
+func renderCheShi(g *generator) ([]byte, error) {
+	var buf bytes.Buffer
+	err := cheshiPage.Execute(&buf, g.htmlItems(g.items, false))
+	return buf.Bytes(), err
+}
+
+// 知乎热榜页同时包含列表 (v1 解析) 和 js-initialData 中的 JSON (v2 解析), 两者内容一致
+func renderZhihu(g *generator) ([]byte, error) {
+	var shell api.ZhihuShellResponse
+	g.fill(reflect.ValueOf(&shell).Elem(), "", 0)
+
+	var items []htmlItem
+	for _, v := range shell.InitialState.Topstory.HotList {
+		items = append(items, htmlItem{
+			Index: fmt.Sprint(len(items) + 1),
+			Title: v.Target.TitleArea.Text,
+			Desc:  v.Target.ExcerptArea.Text,
+			Img:   v.Target.ImageArea.Text,
+			Hot:   v.Target.MetricsArea.Text,
+		})
+	}
+
+	data, err := json.Marshal(shell)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = zhihuPage.Execute(&buf, map[string]any{"Items": items, "Data": template.HTML(data)})
+	return buf.Bytes(), err
+}
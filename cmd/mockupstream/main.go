@@ -0,0 +1,317 @@
+// mockupstream 模拟各榜单的上游接口, 返回录制的或随机生成的响应, 并可模拟上游故障, 用于离线开发和 CI
+//
+//	go run ./cmd/mockupstream -addr :9090
+//	UPSTREAM_MOCK=http://127.0.0.1:9090 go run ./cmd/api
+//
+// 服务端通过 X-Upstream-Host 请求头识别原始上游, 由 upstream.Redirect 设置; 手动调试时也可以用 ?_host= 指定。
+// 故障模式:
+//
+//	slow       延迟 -slow 指定的时间后再响应
+//	5xx        返回 503
+//	malformed  响应体截断一半, JSON 无法解析
+//	layout     JSON 字段名、页面 class 名整体改变, 模拟上游改版
+//
+// 可以用 -failure 对所有请求生效, 用 -fail-rate 按概率随机注入, 也可以运行时修改:
+//
+//	curl -X POST 'localhost:9090/_mock/failure?route=weibo&mode=5xx'   某个上游返回 503
+//	curl -X POST 'localhost:9090/_mock/failure?route=weibo'            恢复正常
+//	curl localhost:9090/_mock/routes                                  查看所有上游和当前故障
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/upstream"
+)
+
+// 故障模式
+const (
+	FailureSlow      = "slow"
+	FailureStatus    = "5xx"
+	FailureMalformed = "malformed"
+	FailureLayout    = "layout"
+)
+
+var failureModes = []string{FailureSlow, FailureStatus, FailureMalformed, FailureLayout}
+
+type server struct {
+	seed     int64
+	items    int
+	slow     time.Duration
+	failRate float64
+	fixtures *upstream.Recorder
+
+	mu       sync.RWMutex
+	failures map[string]string // 上游名称或主机名 -> 故障模式, * 表示全部
+
+	count atomic.Int64
+}
+
+func main() {
+	addr := flag.String("addr", ":9090", "监听地址")
+	fixtures := flag.String("fixtures", "", "fixture 目录, 有录制的响应时优先返回, 如 testdata/fixtures")
+	seed := flag.Int64("seed", 0, "随机种子, 非 0 时同一上游每次返回相同内容")
+	items := flag.Int("items", 30, "随机生成的条目数")
+	failure := flag.String("failure", "", "所有请求使用的故障模式: slow, 5xx, malformed, layout")
+	failRate := flag.Float64("fail-rate", 0, "随机注入故障的概率, 0-1")
+	slow := flag.Duration("slow", 15*time.Second, "slow 模式的延迟")
+	flag.Parse()
+
+	s := &server{seed: *seed, items: *items, slow: *slow, failRate: *failRate, failures: map[string]string{}}
+	if *failure != "" {
+		if !validFailure(*failure) {
+			logrus.Fatalf("unknown failure mode: %s", *failure)
+		}
+		s.failures["*"] = *failure
+	}
+	if *fixtures != "" {
+		recorder, err := upstream.NewRecorder(upstream.ModeReplay, *fixtures, nil)
+		if err != nil {
+			logrus.Fatalf("load fixtures: %s", err.Error())
+		}
+		s.fixtures = recorder
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_mock/routes", s.handleRoutes)
+	mux.HandleFunc("/_mock/failure", s.handleFailure)
+	mux.HandleFunc("/", s.handleUpstream)
+
+	logrus.Infof("mock upstream listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		logrus.Fatalf("listen: %s", err.Error())
+	}
+}
+
+func validFailure(mode string) bool {
+	for _, m := range failureModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// failureFor 请求使用的故障模式: 请求头 X-Mock-Failure > 按上游名称 > 按主机名 > 全部 > 随机注入
+func (s *server) failureFor(req *http.Request, r route) string {
+	if mode := req.Header.Get("X-Mock-Failure"); mode != "" {
+		return mode
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, key := range []string{r.Name, r.Host, "*"} {
+		if mode, ok := s.failures[key]; ok {
+			return mode
+		}
+	}
+	if s.failRate > 0 && rand.Float64() < s.failRate {
+		return failureModes[rand.Intn(len(failureModes))]
+	}
+	return ""
+}
+
+// generator 固定种子时按上游名称派生, 同一上游每次生成相同内容
+func (s *server) generator(r route) *generator {
+	if s.seed == 0 {
+		return newGenerator(time.Now().UnixNano()+s.count.Add(1), s.items)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(r.Name))
+	return newGenerator(s.seed^int64(h.Sum64()), s.items)
+}
+
+func (s *server) handleUpstream(w http.ResponseWriter, req *http.Request) {
+	host := req.Header.Get(upstream.HeaderHost)
+	if host == "" {
+		host = req.URL.Query().Get("_host")
+	}
+	r, ok := findRoute(req.Method, host, req.URL.Path)
+	if !ok {
+		logrus.Warnf("no route for %s %s%s", req.Method, host, req.URL.Path)
+		http.Error(w, "no mock for this upstream", http.StatusNotFound)
+		return
+	}
+
+	failure := s.failureFor(req, r)
+	logrus.Infof("%s %s%s failure=%s", req.Method, host, req.URL.Path, failure)
+
+	switch failure {
+	case FailureSlow:
+		select {
+		case <-time.After(s.slow):
+		case <-req.Context().Done():
+			return
+		}
+	case FailureStatus:
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, contentType, err := s.body(req, host, r)
+	if err != nil {
+		logrus.Errorf("generate %s: %s", r.Name, err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch failure {
+	case FailureMalformed:
+		body = body[:len(body)/2]
+	case FailureLayout:
+		body = changeLayout(body, r.Html)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
+
+// body 优先返回录制的响应, 没有时随机生成
+func (s *server) body(req *http.Request, host string, r route) ([]byte, string, error) {
+	contentType := "application/json; charset=utf-8"
+	if r.Html {
+		contentType = "text/html; charset=utf-8"
+	}
+
+	if s.fixtures != nil {
+		u := &url.URL{Scheme: "https", Host: host, Path: req.URL.Path, RawQuery: req.URL.RawQuery}
+		origin := &http.Request{Method: req.Method, URL: u, Header: http.Header{}}
+		if resp, err := s.fixtures.RoundTrip(origin); err == nil {
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if ct := resp.Header.Get("Content-Type"); ct != "" {
+				contentType = ct
+			}
+			return body, contentType, err
+		}
+	}
+
+	body, err := r.generate(s.generator(r))
+	return body, contentType, err
+}
+
+var attrRex = regexp.MustCompile(`(class|id)="([^"]*)"`)
+
+// changeLayout 模拟上游改版: 页面的 class 和 id 加前缀, JSON 的字段名加前缀, 原有的选择器和结构体都无法匹配
+func changeLayout(body []byte, html bool) []byte {
+	if html {
+		return attrRex.ReplaceAllFunc(body, func(m []byte) []byte {
+			sub := attrRex.FindSubmatch(m)
+			names := strings.Fields(string(sub[2]))
+			for i, name := range names {
+				names[i] = "v2-" + name
+			}
+			return []byte(string(sub[1]) + `="` + strings.Join(names, " ") + `"`)
+		})
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	v = renameKeys(v)
+	// 顶层为数组的接口改为对象包装
+	if list, ok := v.([]any); ok {
+		v = map[string]any{"v2_list": list}
+	}
+	content, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return content
+}
+
+func renameKeys(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, val := range t {
+			m["v2_"+k] = renameKeys(val)
+		}
+		return m
+	case []any:
+		list := make([]any, len(t))
+		for i, val := range t {
+			list[i] = renameKeys(val)
+		}
+		return list
+	}
+	return v
+}
+
+func (s *server) handleRoutes(w http.ResponseWriter, req *http.Request) {
+	type item struct {
+		route
+		Failure string `json:"failure,omitempty"`
+	}
+	s.mu.RLock()
+	list := make([]item, 0, len(routes))
+	for _, r := range routes {
+		failure := s.failures[r.Name]
+		if failure == "" {
+			failure = s.failures[r.Host]
+		}
+		if failure == "" {
+			failure = s.failures["*"]
+		}
+		list = append(list, item{r, failure})
+	}
+	s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, list)
+}
+
+// handleFailure POST ?route=weibo&mode=5xx 设置故障, mode 为空时恢复; route 可以是上游名称、主机名或 *, 默认为 *
+func (s *server) handleFailure(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost && req.Method != http.MethodPut {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"err": "Use POST"})
+		return
+	}
+	key := req.URL.Query().Get("route")
+	if key == "" {
+		key = "*"
+	}
+	mode := req.URL.Query().Get("mode")
+	if mode != "" && !validFailure(mode) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"err": fmt.Sprintf("Unknown mode: %s", mode)})
+		return
+	}
+
+	s.mu.Lock()
+	if mode == "" {
+		delete(s.failures, key)
+	} else {
+		s.failures[key] = mode
+	}
+	failures := make(map[string]string, len(s.failures))
+	for k, v := range s.failures {
+		failures[k] = v
+	}
+	s.mu.Unlock()
+
+	logrus.Infof("failure %s=%s", key, mode)
+	writeJSON(w, http.StatusOK, failures)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/caches"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+	"github.com/turbo-uid/hots/routers/api"
+	"github.com/turbo-uid/hots/upstream"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// TestFailureModes 上游返回 503 或改版时, 所有榜单都返回之前的榜单并标记上游不可用, 不写入缓存
+func TestFailureModes(t *testing.T) {
+	logrus.SetOutput(io.Discard)
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+	leader.Standalone()
+
+	s := &server{seed: 20260101, items: 10, failures: map[string]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(s.handleUpstream))
+	t.Cleanup(srv.Close)
+	redirect, err := upstream.NewRedirect(srv.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream.Use(redirect)
+	t.Cleanup(func() { upstream.Use(http.DefaultTransport) })
+
+	stale := globals.GblResp{Succ: "ok", Data: []globals.GblRespData{{Title: "之前的榜单", Pos: 1}}}
+	for _, mode := range []string{FailureLayout, FailureStatus} {
+		t.Run(mode, func(t *testing.T) {
+			s.failures["*"] = mode
+			globals.GoCache = caches.NewMemory(cache.New(time.Hour, time.Hour))
+
+			for _, b := range api.Boards {
+				globals.GoCache.Set(utils.GetHotStaleKey(b.Flag), stale, time.Hour)
+
+				resultResp := api.GetHot(b.Flag, b.Fetch)
+				if !resultResp.Stale || len(resultResp.Data) != 1 || resultResp.Data[0].Title != "之前的榜单" {
+					t.Errorf("%s: got code %d, err %q, %d items, want the stale board", b.Name, resultResp.Code, resultResp.Err, len(resultResp.Data))
+				}
+				var cached globals.GblResp
+				if globals.GoCache.Get(utils.GetHotCacheKey(b.Flag), &cached) {
+					t.Errorf("%s: failed response cached", b.Name)
+				}
+			}
+
+			statuses := api.ProviderStatuses()
+			if len(statuses) != len(api.Boards) {
+				t.Fatalf("%d provider statuses, want %d", len(statuses), len(api.Boards))
+			}
+			for _, v := range statuses {
+				if v.Ok {
+					t.Errorf("%s reported healthy", v.Platform)
+				}
+			}
+		})
+	}
+
+	// 恢复后重新请求上游
+	delete(s.failures, "*")
+	globals.GoCache = caches.NewMemory(cache.New(time.Hour, time.Hour))
+	for _, b := range api.Boards {
+		if resultResp := api.GetHot(b.Flag, b.Fetch); resultResp.Code != 0 || resultResp.Stale || len(resultResp.Data) == 0 {
+			t.Errorf("%s after recovery: code %d, err %q, %d items", b.Name, resultResp.Code, resultResp.Err, len(resultResp.Data))
+		}
+	}
+	for _, v := range api.ProviderStatuses() {
+		if !v.Ok {
+			t.Errorf("%s still down after recovery: %s", v.Platform, v.Err)
+		}
+	}
+}
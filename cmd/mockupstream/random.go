@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	titlePrefixes = []string{"多地", "官方", "网友", "专家解读", "央视关注", "全国首个", "年轻人", "最新调查", "国产", "高校"}
+	titleTopics   = []string{"寒潮预警", "新能源汽车", "高考志愿", "AI 大模型", "国产芯片", "春运抢票", "电影票房", "养老金调整", "城市地铁", "夜间经济", "开源项目", "手机新品"}
+	titleSuffixes = []string{"发布最新消息", "引发热议", "迎来新变化", "冲上热搜", "官方回应", "数据出炉", "背后的原因", "你怎么看", "全面升级", "正式落地"}
+	sentences     = []string{
+		"相关部门表示将持续跟进, 及时公布最新进展。",
+		"多位业内人士认为, 这一变化将在未来一段时间内持续发酵。",
+		"记者走访发现, 不少市民对此表示关注。",
+		"数据显示, 相关话题阅读量在一小时内快速上涨。",
+		"目前事件仍在进一步调查中。",
+	}
+	labels  = []string{"热", "新", "沸", "爆", ""}
+	authors = []string{"小明同学", "科技观察", "前端早读课", "汽车之家编辑部", "观影指南"}
+)
+
+// generator 按字段名生成随机内容, 使各榜单的解析代码能得到看起来合理的结果
+type generator struct {
+	r     *rand.Rand
+	items int
+}
+
+func newGenerator(seed int64, items int) *generator {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &generator{r: rand.New(rand.NewSource(seed)), items: items}
+}
+
+func (g *generator) pick(list []string) string {
+	return list[g.r.Intn(len(list))]
+}
+
+func (g *generator) title() string {
+	return g.pick(titlePrefixes) + g.pick(titleTopics) + g.pick(titleSuffixes)
+}
+
+func (g *generator) sentence() string {
+	return g.pick(sentences)
+}
+
+func (g *generator) link() string {
+	return fmt.Sprintf("https://example.com/mock/%d", g.r.Intn(1e8))
+}
+
+func (g *generator) image() string {
+	return fmt.Sprintf("https://example.com/mock/img/%d.png", g.r.Intn(1e8))
+}
+
+func (g *generator) hot() int {
+	return 1000 + g.r.Intn(5e6)
+}
+
+// fill 按结构体字段的 json 名称填充随机值, path 为小写的字段路径, idx 为所在列表中的下标
+func (g *generator) fill(v reflect.Value, path string, idx int) {
+	name := path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		name = path[i+1:]
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			key := strings.Split(field.Tag.Get("json"), ",")[0]
+			if key == "-" {
+				continue
+			}
+			if key == "" {
+				key = field.Name
+			}
+			g.fill(v.Field(i), strings.TrimPrefix(path+"."+strings.ToLower(key), "."), idx)
+		}
+	case reflect.Slice:
+		n := g.sliceLen(name, v.Type().Elem().Kind())
+		s := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			g.fill(s.Index(i), path, i)
+		}
+		v.Set(s)
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		g.fill(p.Elem(), path, idx)
+		v.Set(p)
+	case reflect.String:
+		v.SetString(g.stringFor(path, name))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(g.intFor(name, idx)))
+	case reflect.Float32, reflect.Float64:
+		if strings.Contains(name, "rate") {
+			v.SetFloat(g.r.Float64() * 3)
+		} else {
+			v.SetFloat(float64(1e5 + g.r.Int63n(5e9)))
+		}
+	case reflect.Bool:
+		v.SetBool(name == "success" || name == "succ")
+	}
+}
+
+func (g *generator) sliceLen(name string, elem reflect.Kind) int {
+	switch {
+	// 置顶列表、外层包装列表只有一项
+	case strings.HasPrefix(name, "top"), strings.Contains(name, "_top_"), name == "hotgovs", name == "idlist":
+		return 1
+	case elem != reflect.Struct:
+		return 1 + g.r.Intn(3)
+	}
+	return g.items
+}
+
+func (g *generator) intFor(name string, idx int) int {
+	switch name {
+	case "pos", "position", "realpos", "rank", "ranking", "irank", "index":
+		return idx + 1
+	case "code", "ret", "status", "status_code", "err_no", "errno", "returncode", "resultcode", "is_top", "istop", "type", "trend":
+		return 0
+	case "ok":
+		return 1
+	case "label":
+		// 抖音的标签类型
+		return []int{0, 1, 3, 5, 8}[g.r.Intn(5)]
+	}
+	if strings.HasSuffix(name, "id") {
+		return 1e5 + g.r.Intn(1e9)
+	}
+	return g.hot()
+}
+
+func (g *generator) stringFor(path, name string) string {
+	switch {
+	// tail_icon 是豆瓣的文字角标, 不是图片
+	case strings.Contains(name, "label"), strings.HasSuffix(name, "_desc"), strings.HasSuffix(name, "_type"), strings.Contains(path, "tail_icon.text"):
+		return g.pick(labels)
+	case name == "tags":
+		return g.pick(titleTopics)
+	case strings.Contains(name, "color"):
+		return "#FF5A5F"
+	case strings.Contains(name, "time"), strings.Contains(name, "date"):
+		return time.Now().AddDate(0, 0, -g.r.Intn(30)).Format("2006-01-02")
+	case isImage(name), (name == "url" || name == "text") && isImage(parent(path)):
+		return g.image()
+	case containsAny(name, "url", "link", "href"):
+		return g.link()
+	case strings.HasSuffix(name, "id"), name == "ids_hash":
+		return fmt.Sprint(1e5 + g.r.Intn(1e9))
+	case strings.Contains(path, "metrics"):
+		return fmt.Sprintf("%d 万热度", 1+g.r.Intn(3000))
+	case containsAny(name, "author", "nick"):
+		return g.pick(authors)
+	case containsAny(name, "title", "word", "name", "query"):
+		return g.title()
+	case containsAny(name, "hot", "score", "count", "num", "read", "view", "value", "clicks", "praise", "interaction"):
+		return fmt.Sprint(g.hot())
+	case strings.Contains(path, "title"):
+		return g.title()
+	}
+	return g.sentence()
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func isImage(name string) bool {
+	return containsAny(name, "img", "image", "pic", "icon", "cover", "avatar") && !strings.Contains(name, "topic")
+}
+
+// parent 字段路径的上一级名称
+func parent(path string) string {
+	segs := strings.Split(path, ".")
+	if len(segs) < 2 {
+		return ""
+	}
+	return segs[len(segs)-2]
+}
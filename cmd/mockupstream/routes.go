@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/turbo-uid/hots/routers/api"
+)
+
+// route 一个上游接口, 按 主机名+路径 匹配, 查询参数不参与匹配
+type route struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	Host   string `json:"host"`
+	Path   string `json:"path"`
+	Html   bool   `json:"html"`
+
+	// shell 为 JSON 接口的响应结构, 随机内容按其字段生成; render 用于页面类上游
+	shell  any
+	render func(g *generator) ([]byte, error)
+}
+
+// routes 所有内置榜单请求的上游, 新增榜单时在此补充
+var routes = []route{
+	{Name: "bili", Method: "GET", Host: "app.bilibili.com", Path: "/x/v2/search/trending/ranking", shell: api.BiliShellResponse{}},
+	{Name: "weibo", Method: "GET", Host: "weibo.com", Path: "/ajax/side/hotSearch", shell: api.WeiboShellResponse{}},
+	{Name: "douyin", Method: "GET", Host: "aweme-lq.snssdk.com", Path: "/aweme/v1/hot/search/list/", shell: api.DouyinShellResponse{}},
+	{Name: "toutiao", Method: "GET", Host: "www.toutiao.com", Path: "/hot-event/hot-board/", shell: api.ToutiaoShellResponse{}},
+	{Name: "douban", Method: "GET", Host: "m.douban.com", Path: "/rexxar/api/v2/search/hots", shell: api.DoubanShellResponse{}},
+	{Name: "thepaper", Method: "GET", Host: "cache.thepaper.cn", Path: "/contentapi/wwwIndex/rightSidebar", shell: api.ThepaperShellResponse{}},
+	{Name: "xhs", Method: "GET", Host: "edith.xiaohongshu.com", Path: "/api/sns/v1/search/hot_list", shell: api.XhsShellResponse{}},
+	{Name: "wy163", Method: "GET", Host: "gw.m.163.com", Path: "/search/api/v2/hot-search", shell: api.Wy163ShellResponse{}},
+	{Name: "qq", Method: "GET", Host: "r.inews.qq.com", Path: "/gw/event/hot_ranking_list", shell: api.QqShellResponse{}},
+	{Name: "baidu", Method: "GET", Host: "top.baidu.com", Path: "/board", Html: true, render: renderBaidu},
+	{Name: "zhihu", Method: "GET", Host: "www.zhihu.com", Path: "/billboard", Html: true, render: renderZhihu},
+	{Name: "36kr", Method: "POST", Host: "gateway.36kr.com", Path: "/api/mis/nav/home/nav/rank/hot", shell: api.To36krShellResponse{}},
+	// csdn 与 csdn-content 只有查询参数不同
+	{Name: "csdn", Method: "GET", Host: "blog.csdn.net", Path: "/phoenix/web/blog/hot-rank", shell: api.CsdnShellResponse{}},
+	{Name: "hellogithub", Method: "GET", Host: "abroad.hellogithub.com", Path: "/v1/", shell: api.HelloGithubShellResponse{}},
+	{Name: "ithome", Method: "GET", Host: "m.ithome.com", Path: "/rankm/", Html: true, render: renderItHome},
+	// juejin 与 juejin-aibox 只有 category_id 不同
+	{Name: "juejin", Method: "GET", Host: "api.juejin.cn", Path: "/content_api/v1/content/article_rank", shell: api.JueJinShellResponse{}},
+	{Name: "carhome", Method: "GET", Host: "content.api.autohome.com.cn", Path: "/pc/rank/list", shell: api.CarHomeShellResponse{}},
+	{Name: "dongchedi", Method: "GET", Host: "www.dongchedi.com", Path: "/motor/pc/content/pgc_content_rank", shell: api.DongCheDiShellResponse{}},
+	{Name: "cheshi", Method: "GET", Host: "news.cheshi.com", Path: "/djbd/", Html: true, render: renderCheShi},
+	{Name: "qctt", Method: "GET", Host: "www.qctt.cn", Path: "/channelDataList", shell: []api.QcttData{}},
+	{Name: "endata", Method: "POST", Host: "ys.endata.cn", Path: "/enlib-api/api/home/getrank_mainland.do", shell: api.EnDataShellResponse{}},
+	{Name: "endata-s", Method: "POST", Host: "ys.endata.cn", Path: "/enlib-api/api/home/getrank_singleday.do", shell: api.EnDataShellResponse{}},
+	{Name: "toolify", Method: "GET", Host: "www.toolify.ai", Path: "/self-api/v1/top/month-top", shell: api.ToolifyShellResponse{}},
+}
+
+// findRoute 查找上游接口, 方法不同时视为找不到
+func findRoute(method, host, path string) (route, bool) {
+	for _, r := range routes {
+		if r.Host == host && r.Path == path && r.Method == method {
+			return r, true
+		}
+	}
+	return route{}, false
+}
+
+// generate 生成随机响应体
+func (r route) generate(g *generator) ([]byte, error) {
+	if r.render != nil {
+		return r.render(g)
+	}
+	v := reflect.New(reflect.TypeOf(r.shell)).Elem()
+	g.fill(v, "", 0)
+	return json.Marshal(v.Interface())
+}
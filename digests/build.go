@@ -0,0 +1,223 @@
+package digests
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// itemStat 一个条目在周期内的统计
+type itemStat struct {
+	item     globals.DigestItem
+	firstPos int
+	score    float64
+}
+
+// points 在榜积分, 排名越高、在榜越久积分越多
+func points(pos int) float64 {
+	if pos <= 0 || pos >= 50 {
+		return 1
+	}
+	return float64(51 - pos)
+}
+
+// weights 每份快照代表的分钟数, 即到下一份快照的间隔, 最后一份沿用前一个间隔
+// 较早的快照经过抽稀, 按时长加权后近期密集的快照不会占优
+func weights(list []globals.Snapshot) ([]float64, float64) {
+	result := make([]float64, len(list))
+	var total float64
+	for k := range list {
+		w := 1.0
+		if k+1 < len(list) {
+			w = list[k+1].FetchedAt.Sub(list[k].FetchedAt).Minutes()
+		} else if k > 0 {
+			w = result[k-1]
+		}
+		if w <= 0 {
+			w = 1
+		}
+		result[k] = w
+		total += w
+	}
+	return result, total
+}
+
+// boardStats 按首次出现顺序返回榜单内各条目的统计, 积分按快照代表的时长加权
+func boardStats(flag string, list []globals.Snapshot, weight []float64) []*itemStat {
+	var result []*itemStat
+	stats := map[string]*itemStat{}
+	for k, snap := range list {
+		for _, v := range snap.Data {
+			st, found := stats[v.Id]
+			if !found {
+				st = &itemStat{firstPos: v.Pos}
+				st.item.Platform = flag
+				st.item.Id = v.Id
+				st.item.FirstSeen = snap.FetchedAt
+				st.item.BestPos = v.Pos
+				stats[v.Id] = st
+				result = append(result, st)
+			}
+			st.item.Title = v.Title
+			st.item.ToUrl = v.ToUrl
+			st.item.HotVal = v.HotVal
+			st.item.Appearances++
+			st.item.LastSeen = snap.FetchedAt
+			if v.Pos > 0 && (st.item.BestPos <= 0 || v.Pos < st.item.BestPos) {
+				st.item.BestPos = v.Pos
+			}
+			st.score += points(v.Pos) * weight[k]
+		}
+	}
+	for _, st := range result {
+		st.item.Minutes = int64(st.item.LastSeen.Sub(st.item.FirstSeen).Minutes())
+		if st.firstPos > 0 && st.item.BestPos > 0 {
+			st.item.RankGain = st.firstPos - st.item.BestPos
+		}
+	}
+	return result
+}
+
+// topBy 按 less 排序后取前 top 条, 排序稳定
+func topBy(list []*itemStat, top int, less func(a, b *itemStat) bool) []globals.DigestItem {
+	sorted := append([]*itemStat(nil), list...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
+
+	var result []globals.DigestItem
+	for _, st := range sorted {
+		if len(result) >= top {
+			break
+		}
+		result = append(result, st.item)
+	}
+	return result
+}
+
+// Build 用 [from, to] 内的快照生成摘要
+func Build(store globals.SnapshotStore, period string, from, to time.Time, top int) globals.Digest {
+	digest := globals.Digest{
+		Id:        period + "-" + to.Format("20060102T1504"),
+		Period:    period,
+		From:      from,
+		To:        to,
+		CreatedAt: time.Now(),
+	}
+
+	var all []*itemStat
+	for _, flag := range store.Platforms() {
+		list := store.List(flag, from, to)
+		if len(list) == 0 {
+			continue
+		}
+
+		weight, total := weights(list)
+		stats := boardStats(flag, list, weight)
+		digest.Boards = append(digest.Boards, globals.DigestBoard{
+			Platform:  flag,
+			Snapshots: len(list),
+			Items: topBy(stats, top, func(a, b *itemStat) bool {
+				return a.score > b.score
+			}),
+		})
+
+		// 跨平台比较时按总时长归一, 刷新频繁的榜单不占优势
+		for _, st := range stats {
+			st.score /= total
+		}
+		all = append(all, stats...)
+
+		switch flag {
+		case globals.EnDataMFlag, globals.EnDataSFlag:
+			digest.BoxOffice = append(digest.BoxOffice, boxOffice(store, flag, list[len(list)-1], from, top)...)
+		case globals.ToolifyFlag:
+			digest.AITools = aiTools(list[len(list)-1], top)
+		}
+	}
+
+	digest.Overall = topBy(all, top, func(a, b *itemStat) bool {
+		return a.score > b.score
+	})
+	digest.Longest = topBy(all, top, func(a, b *itemStat) bool {
+		return a.item.Minutes > b.item.Minutes
+	})
+
+	var movers []*itemStat
+	for _, st := range all {
+		if st.item.RankGain > 0 {
+			movers = append(movers, st)
+		}
+	}
+	digest.Movers = topBy(movers, top, func(a, b *itemStat) bool {
+		return a.item.RankGain > b.item.RankGain
+	})
+
+	return digest
+}
+
+// boxOffice 周期内首次上榜的影片
+func boxOffice(store globals.SnapshotStore, flag string, latest globals.Snapshot, from time.Time, top int) []globals.DigestItem {
+	var result []globals.DigestItem
+	for _, v := range latest.Data {
+		first, found := store.FirstSeen(flag, v.Id)
+		if !found || first.Before(from) {
+			continue
+		}
+
+		item := digestItem(flag, latest, v)
+		item.FirstSeen = first
+		item.Note = fmt.Sprintf("票房 %v", extra(v, globals.ExtraBoxOffice))
+		if date := extra(v, globals.ExtraReleaseDate); date != "" {
+			item.Note += ", 上映 " + date
+		}
+		result = append(result, item)
+		if len(result) >= top {
+			break
+		}
+	}
+	return result
+}
+
+// aiTools 最新一份 toolify 榜单按月访问增长排序
+func aiTools(latest globals.Snapshot, top int) []globals.DigestItem {
+	data := append([]globals.GblRespData(nil), latest.Data...)
+	sort.SliceStable(data, func(i, j int) bool {
+		return utils.ParseCount(extra(data[i], globals.ExtraGrowth)) > utils.ParseCount(extra(data[j], globals.ExtraGrowth))
+	})
+
+	var result []globals.DigestItem
+	for _, v := range data {
+		if len(result) >= top {
+			break
+		}
+		item := digestItem(globals.ToolifyFlag, latest, v)
+		item.Note = fmt.Sprintf("月访问增长 %s (%s)", extra(v, globals.ExtraGrowth), extra(v, globals.ExtraGrowthRate))
+		result = append(result, item)
+	}
+	return result
+}
+
+func digestItem(flag string, snap globals.Snapshot, v globals.GblRespData) globals.DigestItem {
+	return globals.DigestItem{
+		Platform:    flag,
+		Id:          v.Id,
+		Title:       v.Title,
+		ToUrl:       v.ToUrl,
+		HotVal:      v.HotVal,
+		BestPos:     v.Pos,
+		Appearances: 1,
+		FirstSeen:   snap.FetchedAt,
+		LastSeen:    snap.FetchedAt,
+	}
+}
+
+func extra(v globals.GblRespData, key string) string {
+	if val, ok := v.Extra[key]; ok && val != nil {
+		return fmt.Sprint(val)
+	}
+	return ""
+}
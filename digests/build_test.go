@@ -0,0 +1,83 @@
+package digests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/snapshots"
+)
+
+func item(id string, pos int) globals.GblRespData {
+	return globals.GblRespData{Id: id, Title: "标题" + id, Pos: pos}
+}
+
+func ids(list []globals.DigestItem) []string {
+	var result []string
+	for _, v := range list {
+		result = append(result, v.Id)
+	}
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if a[k] != b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBuild(t *testing.T) {
+	store, err := snapshots.NewStore("", snapshots.DefaultRetention())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	for _, snap := range []globals.Snapshot{
+		// 周期之前的快照不计入
+		{Platform: globals.WeiboFlag, FetchedAt: base.Add(-2 * time.Hour), Data: []globals.GblRespData{item("D", 1)}},
+		{Platform: globals.WeiboFlag, FetchedAt: base, Data: []globals.GblRespData{item("A", 1), item("C", 2), item("B", 5)}},
+		{Platform: globals.WeiboFlag, FetchedAt: base.Add(10 * time.Minute), Data: []globals.GblRespData{item("A", 1), item("B", 2)}},
+		{Platform: globals.WeiboFlag, FetchedAt: base.Add(20 * time.Minute), Data: []globals.GblRespData{item("A", 1), item("B", 2)}},
+		{Platform: globals.ZhihuFlag, FetchedAt: base.Add(5 * time.Minute), Data: []globals.GblRespData{item("X", 10)}},
+	} {
+		if err := store.Save(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	to := base.Add(30 * time.Minute)
+	d := Build(store, globals.DigestDaily, base.Add(-time.Hour), to, 3)
+	if d.Id != "daily-"+to.Format("20060102T1504") || d.Period != globals.DigestDaily {
+		t.Errorf("id = %s, period = %s", d.Id, d.Period)
+	}
+
+	// 跨平台按时长归一后比较: A 50, B 48, X 41, C 16
+	if got := ids(d.Overall); !equal(got, []string{"A", "B", "X"}) {
+		t.Errorf("overall = %v", got)
+	}
+	if got := ids(d.Longest); !equal(got, []string{"A", "B", "C"}) || d.Longest[0].Minutes != 20 {
+		t.Errorf("longest = %v, %+v", got, d.Longest)
+	}
+	if got := ids(d.Movers); !equal(got, []string{"B"}) || d.Movers[0].RankGain != 3 || d.Movers[0].BestPos != 2 {
+		t.Errorf("movers = %+v", d.Movers)
+	}
+
+	if len(d.Boards) != 2 {
+		t.Fatalf("boards = %+v", d.Boards)
+	}
+	for _, b := range d.Boards {
+		if b.Platform == globals.WeiboFlag && (b.Snapshots != 3 || !equal(ids(b.Items), []string{"A", "B", "C"})) {
+			t.Errorf("weibo board = %d snapshots, items %v", b.Snapshots, ids(b.Items))
+		}
+	}
+	a := d.Overall[0]
+	if a.Appearances != 3 || !a.FirstSeen.Equal(base) || !a.LastSeen.Equal(base.Add(20*time.Minute)) || a.Platform != globals.WeiboFlag {
+		t.Errorf("A = %+v", a)
+	}
+}
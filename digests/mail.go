@@ -0,0 +1,44 @@
+package digests
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// Mailer 通过 SMTP 发送 HTML 邮件, User 为空时不认证
+type Mailer struct {
+	Addr string
+	User string
+	Pass string
+	From string
+	To   []string
+}
+
+func (m *Mailer) Send(subject, htmlBody string) error {
+	var auth smtp.Auth
+	if m.User != "" {
+		host, _, err := net.SplitHostPort(m.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", m.User, m.Pass, host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.From)
+	for _, v := range m.To {
+		fmt.Fprintf(&msg, "To: %s\r\n", v)
+	}
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.BEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(htmlBody)
+
+	return smtp.SendMail(m.Addr, auth, m.From, m.To, msg.Bytes())
+}
@@ -0,0 +1,119 @@
+package digests
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+var periodNames = map[string]string{
+	globals.DigestDaily:  "每日热点摘要",
+	globals.DigestWeekly: "每周热点摘要",
+}
+
+// Title 如 "每日热点摘要 2025-01-02"
+func Title(d globals.Digest) string {
+	name := periodNames[d.Period]
+	if name == "" {
+		name = "热点摘要"
+	}
+	return name + " " + d.To.Format("2006-01-02")
+}
+
+// section 摘要的一个部分, detail 为条目后的补充说明
+type section struct {
+	title  string
+	items  []globals.DigestItem
+	detail func(globals.DigestItem) string
+}
+
+func onBoard(v globals.DigestItem) string {
+	if v.Minutes >= 60 {
+		return fmt.Sprintf("在榜 %.1f 小时", float64(v.Minutes)/60)
+	}
+	return fmt.Sprintf("在榜 %d 分钟", v.Minutes)
+}
+
+func sections(d globals.Digest) []section {
+	result := []section{
+		{"综合排名", d.Overall, func(v globals.DigestItem) string {
+			return fmt.Sprintf("最高第 %d 名, %s", v.BestPos, onBoard(v))
+		}},
+		{"在榜最久", d.Longest, onBoard},
+		{"排名上升最多", d.Movers, func(v globals.DigestItem) string {
+			return fmt.Sprintf("上升 %d 名, 最高第 %d 名", v.RankGain, v.BestPos)
+		}},
+		{"新上映影片", d.BoxOffice, func(v globals.DigestItem) string { return v.Note }},
+		{"AI 工具增长榜", d.AITools, func(v globals.DigestItem) string { return v.Note }},
+	}
+	for _, b := range d.Boards {
+		result = append(result, section{b.Platform, b.Items, func(v globals.DigestItem) string {
+			return fmt.Sprintf("最高第 %d 名, %s", v.BestPos, onBoard(v))
+		}})
+	}
+	return result
+}
+
+var markdownEscaper = strings.NewReplacer("[", "【", "]", "】", "\n", " ")
+
+// Markdown 各部分为二级标题, 条目为有序列表
+func Markdown(d globals.Digest) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n%s ~ %s\n", Title(d), d.From.Format("2006-01-02 15:04"), d.To.Format("2006-01-02 15:04"))
+	for _, s := range sections(d) {
+		if len(s.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", s.title)
+		for k, v := range s.items {
+			title := markdownEscaper.Replace(v.Title)
+			if v.ToUrl != "" {
+				title = "[" + title + "](" + v.ToUrl + ")"
+			}
+			fmt.Fprintf(&b, "%d. 【%s】%s", k+1, v.Platform, title)
+			if v.HotVal != "" {
+				fmt.Fprintf(&b, " 🔥%s", v.HotVal)
+			}
+			if detail := s.detail(v); detail != "" {
+				fmt.Fprintf(&b, " — %s", detail)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// HTML 完整页面, 可直接作为邮件正文
+func HTML(d globals.Digest) string {
+	var b strings.Builder
+
+	title := html.EscapeString(Title(d))
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>%s</title></head><body>\n", title)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<p>%s ~ %s</p>\n", title, d.From.Format("2006-01-02 15:04"), d.To.Format("2006-01-02 15:04"))
+	for _, s := range sections(d) {
+		if len(s.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "<h2>%s</h2>\n<ol>\n", html.EscapeString(s.title))
+		for _, v := range s.items {
+			title := html.EscapeString(v.Title)
+			if v.ToUrl != "" {
+				title = `<a href="` + html.EscapeString(v.ToUrl) + `">` + title + "</a>"
+			}
+			fmt.Fprintf(&b, "<li>[%s] %s", html.EscapeString(v.Platform), title)
+			if v.HotVal != "" {
+				fmt.Fprintf(&b, " 🔥%s", html.EscapeString(v.HotVal))
+			}
+			if detail := s.detail(v); detail != "" {
+				fmt.Fprintf(&b, " <small>%s</small>", html.EscapeString(detail))
+			}
+			b.WriteString("</li>\n")
+		}
+		b.WriteString("</ol>\n")
+	}
+	b.WriteString("</body></html>\n")
+	return b.String()
+}
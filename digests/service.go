@@ -0,0 +1,282 @@
+package digests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+	"github.com/turbo-uid/hots/notifiers"
+)
+
+// Default 进程内的摘要服务
+var Default = &Service{}
+
+var periodLengths = map[string]time.Duration{
+	globals.DigestDaily:  24 * time.Hour,
+	globals.DigestWeekly: 7 * 24 * time.Hour,
+}
+
+// Sender 推送摘要的群机器人
+type Sender interface {
+	Name() string
+	Send(msg notifiers.Message) error
+}
+
+// Service 生成、保存并推送摘要; Senders、Mailer 需在 Run 之前设置
+type Service struct {
+	mu   sync.Mutex
+	dir  string
+	list []globals.Digest
+
+	Senders []Sender
+	Mailer  *Mailer
+}
+
+// Load 读取 dir 下已保存的摘要, 之后生成的摘要也写入 dir
+func (s *Service) Load(dir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dir = dir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		var d globals.Digest
+		if err := json.Unmarshal(content, &d); err != nil {
+			return fmt.Errorf("parse %s: %w", name, err)
+		}
+		s.list = append(s.list, d)
+	}
+	sort.Slice(s.list, func(i, j int) bool {
+		return s.list[i].CreatedAt.Before(s.list[j].CreatedAt)
+	})
+	return nil
+}
+
+// Generate 生成截至 to 的一期摘要并保存, 同一期重复生成时覆盖
+func (s *Service) Generate(period string, to time.Time) (globals.Digest, error) {
+	length, ok := periodLengths[period]
+	if !ok {
+		return globals.Digest{}, fmt.Errorf("unknown period: %s", period)
+	}
+	if globals.GoSnapshots == nil {
+		return globals.Digest{}, errors.New("snapshots are disabled")
+	}
+
+	d := Build(globals.GoSnapshots, period, to.Add(-length), to, globals.DigestTop)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, v := range s.list {
+		if v.Id == d.Id {
+			s.list = append(s.list[:k:k], s.list[k+1:]...)
+			break
+		}
+	}
+	s.list = append(s.list, d)
+	if len(s.list) > globals.DigestKeep {
+		for _, v := range s.list[:len(s.list)-globals.DigestKeep] {
+			s.remove(v.Id)
+		}
+		s.list = s.list[len(s.list)-globals.DigestKeep:]
+	}
+
+	if s.dir != "" {
+		content, err := json.Marshal(d)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(s.dir, d.Id+".json"), content, 0644)
+		}
+		if err != nil {
+			globals.GoLogger.Errorf("SAVE DIGEST %s ERR %s", d.Id, err.Error())
+		}
+	}
+	return d, nil
+}
+
+// remove 删除已淘汰摘要的文件, 调用方需持有锁
+func (s *Service) remove(id string) {
+	if s.dir != "" {
+		os.Remove(filepath.Join(s.dir, id+".json"))
+	}
+}
+
+// List 按时间倒序列出摘要, period 为空时不过滤, limit<=0 不限制数量
+func (s *Service) List(period string, limit int) []globals.Digest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []globals.Digest
+	for k := len(s.list) - 1; k >= 0; k-- {
+		if period != "" && s.list[k].Period != period {
+			continue
+		}
+		result = append(result, s.list[k])
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+func (s *Service) Get(id string) (globals.Digest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.list {
+		if v.Id == id {
+			return v, true
+		}
+	}
+	return globals.Digest{}, false
+}
+
+// Push 综合排名推送到群机器人, 完整摘要发送邮件
+func (s *Service) Push(d globals.Digest) error {
+	var errs []error
+
+	msg := notifiers.Message{Title: Title(d), Text: "综合排名"}
+	for k, v := range d.Overall {
+		msg.Items = append(msg.Items, globals.GblRespData{
+			Id:       v.Id,
+			Title:    v.Title,
+			HotVal:   v.HotVal,
+			Pos:      k + 1,
+			ToUrl:    v.ToUrl,
+			Platform: v.Platform,
+		})
+	}
+	for _, sender := range s.Senders {
+		if err := sender.Send(msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sender.Name(), err))
+		}
+	}
+
+	if s.Mailer != nil {
+		if err := s.Mailer.Send(Title(d), HTML(d)); err != nil {
+			errs = append(errs, fmt.Errorf("smtp: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Schedule 生成时间, Weekly 为 true 时每周 Weekday 生成, 否则每天生成
+type Schedule struct {
+	Period  string
+	Weekly  bool
+	Weekday time.Weekday
+	Hour    int
+	Minute  int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseSchedule 每日摘要为 "08:00", 每周摘要为 "Mon 08:00"
+func ParseSchedule(period, str string) (Schedule, error) {
+	result := Schedule{Period: period}
+
+	fields := strings.Fields(str)
+	if period == globals.DigestWeekly {
+		if len(fields) != 2 {
+			return result, fmt.Errorf("invalid weekly schedule: %s", str)
+		}
+		day, ok := weekdays[strings.ToLower(fields[0])[:min(3, len(fields[0]))]]
+		if !ok {
+			return result, fmt.Errorf("invalid weekday: %s", fields[0])
+		}
+		result.Weekly = true
+		result.Weekday = day
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return result, fmt.Errorf("invalid schedule: %s", str)
+	}
+
+	t, err := time.Parse("15:04", fields[0])
+	if err != nil {
+		return result, fmt.Errorf("invalid time: %s", fields[0])
+	}
+	result.Hour = t.Hour()
+	result.Minute = t.Minute()
+	return result, nil
+}
+
+// Next now 之后的下一次生成时间, 按本地时区
+func (s Schedule) Next(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, s.Minute, 0, 0, now.Location())
+	if s.Weekly {
+		next = next.AddDate(0, 0, (int(s.Weekday)-int(next.Weekday())+7)%7)
+	}
+	for !next.After(now) {
+		if s.Weekly {
+			next = next.AddDate(0, 0, 7)
+		} else {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+// Run 按计划生成并推送摘要, 一般在启动时以 goroutine 运行
+func (s *Service) Run(schedules []Schedule) {
+	if len(schedules) == 0 {
+		return
+	}
+
+	for {
+		now := time.Now()
+		var next time.Time
+		var due []Schedule
+		for _, v := range schedules {
+			at := v.Next(now)
+			switch {
+			case next.IsZero() || at.Before(next):
+				next = at
+				due = []Schedule{v}
+			case at.Equal(next):
+				due = append(due, v)
+			}
+		}
+
+		time.Sleep(time.Until(next))
+
+		// 多实例部署时只由 leader 生成和推送
+		if !leader.IsLeader() {
+			continue
+		}
+
+		for _, v := range due {
+			d, err := s.Generate(v.Period, next)
+			if err != nil {
+				globals.GoLogger.Errorf("GENERATE DIGEST %s ERR %s", v.Period, err.Error())
+				continue
+			}
+			globals.GoLogger.Infof("GENERATE DIGEST %s", d.Id)
+
+			if err := s.Push(d); err != nil {
+				globals.GoLogger.Errorf("PUSH DIGEST %s ERR %s", d.Id, err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package digests
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"mime"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/notifiers"
+)
+
+func TestParseSchedule(t *testing.T) {
+	cases := []struct {
+		period, str string
+		want        Schedule
+	}{
+		{globals.DigestDaily, "08:00", Schedule{Period: globals.DigestDaily, Hour: 8}},
+		{globals.DigestDaily, " 23:59 ", Schedule{Period: globals.DigestDaily, Hour: 23, Minute: 59}},
+		{globals.DigestWeekly, "Mon 08:30", Schedule{Period: globals.DigestWeekly, Weekly: true, Weekday: time.Monday, Hour: 8, Minute: 30}},
+		{globals.DigestWeekly, "sunday 00:00", Schedule{Period: globals.DigestWeekly, Weekly: true, Weekday: time.Sunday}},
+	}
+	for _, tc := range cases {
+		got, err := ParseSchedule(tc.period, tc.str)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseSchedule(%s, %q) = %+v, %v", tc.period, tc.str, got, err)
+		}
+	}
+
+	for _, tc := range []struct{ period, str string }{
+		{globals.DigestDaily, ""},
+		{globals.DigestDaily, "8"},
+		{globals.DigestDaily, "24:00"},
+		{globals.DigestDaily, "Mon 08:00"},
+		{globals.DigestWeekly, "08:00"},
+		{globals.DigestWeekly, "Xyz 08:00"},
+		{globals.DigestWeekly, "Mon 8am"},
+	} {
+		if got, err := ParseSchedule(tc.period, tc.str); err == nil {
+			t.Errorf("ParseSchedule(%s, %q) = %+v, want error", tc.period, tc.str, got)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	at := func(day, hour, minute int) time.Time {
+		// 2025-01-06 是周一
+		return time.Date(2025, 1, day, hour, minute, 0, 0, loc)
+	}
+	daily := Schedule{Period: globals.DigestDaily, Hour: 8}
+	weekly := Schedule{Period: globals.DigestWeekly, Weekly: true, Weekday: time.Monday, Hour: 8}
+
+	cases := []struct {
+		s         Schedule
+		now, want time.Time
+	}{
+		{daily, at(6, 7, 59), at(6, 8, 0)},
+		// 正好到点时下一次在明天
+		{daily, at(6, 8, 0), at(7, 8, 0)},
+		{daily, at(6, 23, 0), at(7, 8, 0)},
+		// 跨月
+		{daily, at(31, 9, 0), time.Date(2025, 2, 1, 8, 0, 0, 0, loc)},
+		{weekly, at(6, 7, 0), at(6, 8, 0)},
+		{weekly, at(6, 8, 0), at(13, 8, 0)},
+		{weekly, at(7, 8, 0), at(13, 8, 0)},
+		{weekly, at(12, 23, 59), at(13, 8, 0)},
+	}
+	for _, tc := range cases {
+		if got := tc.s.Next(tc.now); !got.Equal(tc.want) {
+			t.Errorf("%+v.Next(%s) = %s, want %s", tc.s, tc.now, got, tc.want)
+		}
+	}
+}
+
+// fakeSMTP 接受一封邮件的最小 SMTP 服务
+type fakeSMTP struct {
+	mu   sync.Mutex
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.Fields(line + " ")[0])
+
+		f.mu.Lock()
+		switch cmd {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250-localhost")
+			tp.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			f.auth = line
+			tp.PrintfLine("235 Authentication successful")
+		case "MAIL":
+			f.from = line
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			f.to = append(f.to, line)
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, _ := tp.ReadDotBytes()
+			f.data = string(data)
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			f.mu.Unlock()
+			return
+		default:
+			tp.PrintfLine("250 OK")
+		}
+		f.mu.Unlock()
+	}
+}
+
+func newFakeSMTP(t *testing.T) (*fakeSMTP, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	f := &fakeSMTP{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			f.serve(conn)
+		}
+	}()
+	return f, ln.Addr().String()
+}
+
+type fakeSender struct {
+	msgs []notifiers.Message
+	err  error
+}
+
+func (s *fakeSender) Name() string { return "fake" }
+
+func (s *fakeSender) Send(msg notifiers.Message) error {
+	s.msgs = append(s.msgs, msg)
+	return s.err
+}
+
+func TestPush(t *testing.T) {
+	f, addr := newFakeSMTP(t)
+	sender := &fakeSender{}
+	s := &Service{
+		Senders: []Sender{sender},
+		Mailer:  &Mailer{Addr: addr, User: "bot", Pass: "secret", From: "hots@example.com", To: []string{"a@example.com", "b@example.com"}},
+	}
+
+	d := globals.Digest{
+		Period: globals.DigestDaily,
+		To:     time.Date(2025, 1, 6, 8, 0, 0, 0, time.Local),
+		Overall: []globals.DigestItem{
+			{Platform: globals.WeiboFlag, Id: "A", Title: "苹果 <发布会>", ToUrl: "https://example.com/a", BestPos: 1, Minutes: 90},
+			{Platform: globals.ZhihuFlag, Id: "B", Title: ".以点开头的标题", BestPos: 2},
+		},
+	}
+	if err := s.Push(d); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sender.msgs) != 1 || sender.msgs[0].Title != "每日热点摘要 2025-01-06" || len(sender.msgs[0].Items) != 2 || sender.msgs[0].Items[1].Pos != 2 {
+		t.Errorf("bot messages = %+v", sender.msgs)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	plain, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(f.auth, "AUTH PLAIN "))
+	if string(plain) != "\x00bot\x00secret" {
+		t.Errorf("auth = %q", plain)
+	}
+	if !strings.HasPrefix(f.from, "MAIL FROM:<hots@example.com>") {
+		t.Errorf("from = %q", f.from)
+	}
+	if len(f.to) != 2 || !strings.Contains(f.to[1], "b@example.com") {
+		t.Errorf("rcpt = %q", f.to)
+	}
+
+	// ReadDotBytes 已将换行转换为 \n
+	header, body, _ := strings.Cut(f.data, "\n\n")
+	msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(header + "\n\n"))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Get("Subject"))
+	if err != nil || subject != "每日热点摘要 2025-01-06" {
+		t.Errorf("subject = %q, %v", subject, err)
+	}
+	if msg.Get("Content-Type") != "text/html; charset=utf-8" || len(msg.Values("To")) != 2 {
+		t.Errorf("header = %v", msg)
+	}
+	if !strings.Contains(body, `<a href="https://example.com/a">苹果 &lt;发布会&gt;</a>`) || !strings.Contains(body, "在榜 1.5 小时") || !strings.Contains(body, ".以点开头的标题") {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestPushErrors(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := ln.Addr().String()
+	ln.Close()
+
+	// 机器人失败不影响邮件, 错误合并返回
+	s := &Service{
+		Senders: []Sender{&fakeSender{err: errors.New("rate limited")}},
+		Mailer:  &Mailer{Addr: addr, From: "hots@example.com", To: []string{"a@example.com"}},
+	}
+	err := s.Push(globals.Digest{Period: globals.DigestWeekly})
+	if err == nil || !strings.Contains(err.Error(), "fake: rate limited") || !strings.Contains(err.Error(), "smtp: ") {
+		t.Errorf("err = %v", err)
+	}
+}
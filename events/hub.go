@@ -0,0 +1,142 @@
+package events
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Default 进程内的事件中心, 刷新流程向其发布, 推送接口从中订阅
+var Default = NewHub(512)
+
+// Hub 简单的发布订阅, 保留最近 backlog 条事件用于断线续传
+// 序号只在进程内递增, 对外的事件ID带上创建时的 epoch, 重启或切换到其他实例后旧的ID不会被误认为有效
+type Hub struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	backlog int
+	recent  []globals.Event
+	subs    map[*Subscription]struct{}
+}
+
+// Subscription 订阅者, 消费过慢时新事件会被丢弃并计入 Dropped
+type Subscription struct {
+	C       chan globals.Event
+	filter  func(globals.Event) bool
+	dropped uint64
+}
+
+// Dropped 因缓冲区已满被丢弃的事件数
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func NewHub(backlog int) *Hub {
+	// 随机值, 同时启动的多个实例也不会相同
+	epoch := strconv.FormatUint(rand.Uint64(), 36)
+	return &Hub{epoch: epoch, backlog: backlog, subs: map[*Subscription]struct{}{}}
+}
+
+// EventId 对外的事件ID, 格式为 <epoch>-<seq>
+func (h *Hub) EventId(seq uint64) string {
+	return h.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// ParseEventId 解析 EventId 生成的ID, 格式不对或 epoch 不是本实例时返回 false, 调用方需要全量同步
+func (h *Hub) ParseEventId(id string) (uint64, bool) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != h.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// Publish 分配序号后广播, 返回带序号的事件
+func (h *Hub) Publish(e globals.Event) globals.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	e.Seq = h.seq
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	h.recent = append(h.recent, e)
+	if len(h.recent) > h.backlog {
+		h.recent = h.recent[len(h.recent)-h.backlog:]
+	}
+
+	for s := range h.subs {
+		if s.filter != nil && !s.filter(e) {
+			continue
+		}
+		select {
+		case s.C <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+
+	return e
+}
+
+// Subscribe 订阅事件, filter 为空时接收全部
+func (h *Hub) Subscribe(size int, filter func(globals.Event) bool) *Subscription {
+	s := &Subscription{C: make(chan globals.Event, size), filter: filter}
+
+	h.mu.Lock()
+	h.subs[s] = struct{}{}
+	h.mu.Unlock()
+
+	return s
+}
+
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	delete(h.subs, s)
+	h.mu.Unlock()
+}
+
+// Since 返回序号大于 seq 的事件; 第二个返回值为 false 表示 seq 已超出保留范围, 调用方需要全量同步
+func (h *Hub) Since(seq uint64, filter func(globals.Event) bool) ([]globals.Event, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if seq > h.seq {
+		return nil, false
+	}
+	if len(h.recent) > 0 && seq+1 < h.recent[0].Seq {
+		return nil, false
+	}
+
+	var result []globals.Event
+	for _, e := range h.recent {
+		if e.Seq <= seq {
+			continue
+		}
+		if filter != nil && !filter(e) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result, true
+}
+
+// Seq 当前最新的事件序号
+func (h *Hub) Seq() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.seq
+}
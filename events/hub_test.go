@@ -0,0 +1,27 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+func TestEventIdEpoch(t *testing.T) {
+	h := NewHub(8)
+	e := h.Publish(globals.Event{Type: globals.EventBoardUpdated})
+
+	seq, ok := h.ParseEventId(h.EventId(e.Seq))
+	if !ok || seq != e.Seq {
+		t.Fatalf("ParseEventId(EventId(%d)) = %d, %v", e.Seq, seq, ok)
+	}
+
+	// 重启后的新实例不认旧的ID, 即使序号在范围内
+	other := NewHub(8)
+	other.Publish(globals.Event{Type: globals.EventBoardUpdated})
+	other.Publish(globals.Event{Type: globals.EventBoardUpdated})
+	for _, id := range []string{h.EventId(e.Seq), "1", "", "x-1"} {
+		if _, ok := other.ParseEventId(id); ok {
+			t.Errorf("ParseEventId(%q) accepted", id)
+		}
+	}
+}
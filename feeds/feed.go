@@ -0,0 +1,305 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// Feed 与输出格式无关的订阅源
+type Feed struct {
+	Title   string
+	Link    string // 榜单页面
+	FeedUrl string // 订阅源自身地址
+	Desc    string
+	Updated time.Time
+	Items   []Item
+}
+
+// Item 订阅源中的一条, Id 为稳定的条目ID
+type Item struct {
+	Id        string
+	Title     string
+	Link      string
+	Desc      string
+	Image     string
+	Author    string
+	Category  string
+	Published time.Time
+}
+
+// 输出格式
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+// ContentType 各格式对应的 Content-Type, 不支持的格式返回空
+func ContentType(format string) string {
+	switch format {
+	case FormatRSS:
+		return "application/rss+xml; charset=utf-8"
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case FormatJSON:
+		return "application/feed+json; charset=utf-8"
+	}
+	return ""
+}
+
+// Render 按格式输出
+func (f Feed) Render(format string) ([]byte, error) {
+	switch format {
+	case FormatAtom:
+		return f.Atom()
+	case FormatJSON:
+		return f.JSON()
+	}
+	return f.RSS()
+}
+
+type rssRoot struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Desc          string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title     string        `xml:"title"`
+	Link      string        `xml:"link,omitempty"`
+	Desc      string        `xml:"description,omitempty"`
+	Author    string        `xml:"author,omitempty"`
+	Category  string        `xml:"category,omitempty"`
+	Guid      rssGuid       `xml:"guid"`
+	PubDate   string        `xml:"pubDate"`
+	Enclosure *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGuid struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	Url    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// RSS 2.0
+func (f Feed) RSS() ([]byte, error) {
+	channel := rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Desc:          f.Desc,
+		LastBuildDate: f.Updated.Format(time.RFC1123Z),
+		Self:          atomLink{Href: f.FeedUrl, Rel: "self", Type: ContentType(FormatRSS)},
+	}
+
+	for _, v := range f.Items {
+		item := rssItem{
+			Title:    v.Title,
+			Link:     v.Link,
+			Desc:     v.Desc,
+			Author:   v.Author,
+			Category: v.Category,
+			Guid:     rssGuid{IsPermaLink: "false", Value: v.Id},
+			PubDate:  v.Published.Format(time.RFC1123Z),
+		}
+		if v.Image != "" {
+			item.Enclosure = &rssEnclosure{Url: v.Image, Length: "0", Type: imageType(v.Image)}
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	return marshalXML(rssRoot{Version: "2.0", Atom: "http://www.w3.org/2005/Atom", Channel: channel})
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Id      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Id        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published"`
+	Links     []atomLink  `xml:"link"`
+	Summary   string      `xml:"summary,omitempty"`
+	Author    *atomAuthor `xml:"author"`
+	Category  *atomCat    `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCat struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom 1.0, 条目 id 使用 tag URI 保证全局唯一且稳定
+func (f Feed) Atom() ([]byte, error) {
+	feed := atomFeed{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		Id:      f.FeedUrl,
+		Title:   f.Title,
+		Updated: f.Updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate"},
+			{Href: f.FeedUrl, Rel: "self", Type: ContentType(FormatAtom)},
+		},
+	}
+
+	for _, v := range f.Items {
+		entry := atomEntry{
+			Id:        tagUri(v.Id),
+			Title:     v.Title,
+			Updated:   v.Published.Format(time.RFC3339),
+			Published: v.Published.Format(time.RFC3339),
+			Summary:   v.Desc,
+		}
+		if v.Link != "" {
+			entry.Links = append(entry.Links, atomLink{Href: v.Link, Rel: "alternate"})
+		}
+		if v.Image != "" {
+			entry.Links = append(entry.Links, atomLink{Href: v.Image, Rel: "enclosure", Type: imageType(v.Image), Length: "0"})
+		}
+		if v.Author != "" {
+			entry.Author = &atomAuthor{Name: v.Author}
+		}
+		if v.Category != "" {
+			entry.Category = &atomCat{Term: v.Category}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return marshalXML(feed)
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageUrl string         `json:"home_page_url,omitempty"`
+	FeedUrl     string         `json:"feed_url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	Id            string               `json:"id"`
+	Url           string               `json:"url,omitempty"`
+	Title         string               `json:"title"`
+	ContentText   string               `json:"content_text"`
+	Image         string               `json:"image,omitempty"`
+	DatePublished string               `json:"date_published"`
+	Authors       []jsonFeedAuthor     `json:"authors,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedAttachment struct {
+	Url      string `json:"url"`
+	MimeType string `json:"mime_type"`
+}
+
+// JSON Feed 1.1
+func (f Feed) JSON() ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageUrl: f.Link,
+		FeedUrl:     f.FeedUrl,
+		Description: f.Desc,
+		Items:       []jsonFeedItem{},
+	}
+
+	for _, v := range f.Items {
+		item := jsonFeedItem{
+			Id:            v.Id,
+			Url:           v.Link,
+			Title:         v.Title,
+			ContentText:   v.Desc,
+			Image:         v.Image,
+			DatePublished: v.Published.Format(time.RFC3339),
+		}
+		if item.ContentText == "" {
+			item.ContentText = v.Title
+		}
+		if v.Author != "" {
+			item.Authors = []jsonFeedAuthor{{Name: v.Author}}
+		}
+		if v.Category != "" {
+			item.Tags = []string{v.Category}
+		}
+		if v.Image != "" {
+			item.Attachments = []jsonFeedAttachment{{Url: v.Image, MimeType: imageType(v.Image)}}
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// imageType 按扩展名猜测图片类型, 无法判断时为 image/jpeg
+func imageType(u string) string {
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	if t := mime.TypeByExtension(path.Ext(u)); strings.HasPrefix(t, "image/") {
+		return t
+	}
+	return "image/jpeg"
+}
+
+// tagUri 形如 tag:hots,2025:weibo:t1234, 只依赖条目ID
+func tagUri(id string) string {
+	return "tag:hots,2025:" + id
+}
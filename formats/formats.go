@@ -0,0 +1,273 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// 支持的输出格式, JSON 之外的格式由本包生成
+const (
+	JSON     = "json"
+	CSV      = "csv"
+	NDJSON   = "ndjson"
+	Markdown = "markdown"
+	HTML     = "html"
+)
+
+var contentTypes = map[string]string{
+	JSON:     "application/json; charset=utf-8",
+	CSV:      "text/csv; charset=utf-8",
+	NDJSON:   "application/x-ndjson; charset=utf-8",
+	Markdown: "text/markdown; charset=utf-8",
+	HTML:     "text/html; charset=utf-8",
+}
+
+// ContentType 格式对应的 Content-Type, 不支持的格式返回空
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Parse 规范化 ?format= 参数, 如 md -> markdown; 不支持时返回空
+func Parse(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "md":
+		return Markdown
+	case "jsonl":
+		return NDJSON
+	}
+	if _, ok := contentTypes[s]; ok {
+		return s
+	}
+	return ""
+}
+
+// Negotiate 按 Accept 头的顺序选择格式, 默认 JSON
+// 浏览器直接打开接口时 Accept 同时带有 text/html 和 application/xhtml+xml, 这种情况仍然返回 JSON
+func Negotiate(accept string) string {
+	browser := strings.Contains(accept, "application/xhtml+xml")
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		switch mediaType {
+		case "application/json":
+			return JSON
+		case "text/csv":
+			return CSV
+		case "application/x-ndjson", "application/ndjson", "application/jsonl":
+			return NDJSON
+		case "text/markdown", "text/x-markdown":
+			return Markdown
+		case "text/html":
+			if !browser {
+				return HTML
+			}
+		}
+	}
+	return JSON
+}
+
+// ItemColumns 条目的列, 与 GblRespData 的字段顺序和 json 名一致
+var ItemColumns = itemColumns()
+
+func itemColumns() []string {
+	var result []string
+	t := reflect.TypeOf(globals.GblRespData{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.SplitN(t.Field(i).Tag.Get("json"), ",", 2)[0]
+		if name != "" && name != "-" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// ItemRow 条目按 ItemColumns 转为一行文本, omitempty 的空字段输出为空, 列表以 | 连接, extra 输出为 JSON
+func ItemRow(v globals.GblRespData) []string {
+	var result []string
+	rv := reflect.ValueOf(v)
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")
+		if tag[0] == "" || tag[0] == "-" {
+			continue
+		}
+		if len(tag) > 1 && tag[1] == "omitempty" && rv.Field(i).IsZero() {
+			result = append(result, "")
+			continue
+		}
+		result = append(result, cellText(rv.Field(i)))
+	}
+	return result
+}
+
+func cellText(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Slice:
+		var list []string
+		for i := 0; i < v.Len(); i++ {
+			list = append(list, fmt.Sprint(v.Index(i).Interface()))
+		}
+		return strings.Join(list, "|")
+	case reflect.Map:
+		out, _ := json.Marshal(v.Interface())
+		return string(out)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// Table 表格数据, Records 为 NDJSON 每行输出的对象, 与 Rows 一一对应
+type Table struct {
+	Columns []string
+	Rows    [][]string
+	Records []interface{}
+}
+
+// Render 输出 CSV、NDJSON、Markdown 或 HTML 表格
+func (t Table) Render(format string) ([]byte, error) {
+	switch format {
+	case CSV:
+		return t.csv()
+	case NDJSON:
+		return t.ndjson()
+	case Markdown:
+		return t.markdown(), nil
+	case HTML:
+		return t.html(), nil
+	}
+	return nil, fmt.Errorf("unsupported format: %s", format)
+}
+
+// csv 带 UTF-8 BOM, Excel 打开中文不乱码; 逗号、引号、换行由 encoding/csv 转义
+func (t Table) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.Columns); err != nil {
+		return nil, err
+	}
+	for _, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = csvCell(v)
+		}
+		if err := w.Write(cells); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvCell 以 = + - @ 开头的单元格加 ' 前缀, 避免抓取的标题在表格软件中被当作公式执行
+func csvCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func (t Table) ndjson() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, v := range t.Records {
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+var markdownEscaper = strings.NewReplacer("|", "\\|", "<", "&lt;", "\r\n", "<br>", "\n", "<br>", "\r", "")
+
+func (t Table) markdown() []byte {
+	var buf bytes.Buffer
+
+	writeRow := func(cells []string) {
+		buf.WriteString("|")
+		for _, v := range cells {
+			buf.WriteString(" ")
+			buf.WriteString(markdownEscaper.Replace(v))
+			buf.WriteString(" |")
+		}
+		buf.WriteString("\n")
+	}
+
+	writeRow(t.Columns)
+	buf.WriteString("|")
+	for range t.Columns {
+		buf.WriteString(" --- |")
+	}
+	buf.WriteString("\n")
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	return buf.Bytes()
+}
+
+func (t Table) html() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>hots</title></head><body>\n<table border=\"1\">\n<thead><tr>")
+	for _, v := range t.Columns {
+		buf.WriteString("<th>" + html.EscapeString(v) + "</th>")
+	}
+	buf.WriteString("</tr></thead>\n<tbody>\n")
+	for _, row := range t.Rows {
+		buf.WriteString("<tr>")
+		for _, v := range row {
+			buf.WriteString("<td>" + html.EscapeString(v) + "</td>")
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</tbody>\n</table>\n</body></html>\n")
+	return buf.Bytes()
+}
+
+// ItemsTable 条目列表
+func ItemsTable(data []globals.GblRespData) Table {
+	t := Table{Columns: ItemColumns}
+	for _, v := range data {
+		t.Rows = append(t.Rows, ItemRow(v))
+		t.Records = append(t.Records, v)
+	}
+	return t
+}
+
+// snapshotRecord NDJSON 中每行是一个条目并带上所属快照
+type snapshotRecord struct {
+	SnapshotId string `json:"snapshot_id"`
+	FetchedAt  string `json:"fetched_at"`
+	globals.GblRespData
+}
+
+// SnapshotsTable 历史快照展开为条目, 前两列为快照ID和抓取时间
+func SnapshotsTable(list []globals.Snapshot) Table {
+	t := Table{Columns: append([]string{"snapshot_id", "fetched_at"}, ItemColumns...)}
+	for _, s := range list {
+		fetchedAt := s.FetchedAt.Format(time.RFC3339)
+		for _, v := range s.Data {
+			t.Rows = append(t.Rows, append([]string{s.Id, fetchedAt}, ItemRow(v)...))
+			t.Records = append(t.Records, snapshotRecord{SnapshotId: s.Id, FetchedAt: fetchedAt, GblRespData: v})
+		}
+	}
+	return t
+}
@@ -0,0 +1,75 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+func TestCSV(t *testing.T) {
+	table := Table{
+		Columns: []string{"title", "desc"},
+		Rows: [][]string{
+			{"a,b", `say "hi"`},
+			{"热搜 第一", "多行\n描述"},
+			{"=HYPERLINK(\"http://x\")", "+1"},
+			{"-2", "@SUM(A1)"},
+			{"1-2", "a=b"},
+		},
+	}
+	out, err := table.Render(CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out, []byte("\ufeff")) {
+		t.Fatalf("missing BOM: %q", out[:8])
+	}
+	if !strings.Contains(string(out), `"a,b","say ""hi"""`) {
+		t.Errorf("comma/quote not escaped:\n%s", out)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(out, []byte("\ufeff")))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"title", "desc"},
+		{"a,b", `say "hi"`},
+		{"热搜 第一", "多行\n描述"},
+		// 公式开头的单元格加 ' 前缀
+		{"'=HYPERLINK(\"http://x\")", "'+1"},
+		{"'-2", "'@SUM(A1)"},
+		{"1-2", "a=b"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %q", records)
+	}
+	for i := range want {
+		if strings.Join(records[i], "\x00") != strings.Join(want[i], "\x00") {
+			t.Errorf("row %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestItemsTable(t *testing.T) {
+	data := []globals.GblRespData{{Id: "1", Title: "=cmd", Pos: 1}}
+	out, err := ItemsTable(data).Render(CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(out, []byte("\ufeff")))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0][1] != "title" || records[1][1] != "'=cmd" {
+		t.Errorf("records = %q", records)
+	}
+	// 其他格式不加前缀
+	md, _ := ItemsTable(data).Render(Markdown)
+	if strings.Contains(string(md), "'=cmd") {
+		t.Errorf("markdown = %s", md)
+	}
+}
@@ -0,0 +1,58 @@
+package globals
+
+import "time"
+
+// API Key 的权限
+const (
+	ApiKeyRoleRead  = "read"
+	ApiKeyRoleAdmin = "admin"
+)
+
+// ApiKey 访问接口的密钥, 只保存哈希; 明文 Key 只在创建时返回
+type ApiKey struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role" enum:"read,admin"`
+	Prefix    string    `json:"prefix"`
+	Hash      string    `json:"hash,omitempty"`
+	Key       string    `json:"key,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdminResult 管理操作中单个榜单或配置项的结果
+type AdminResult struct {
+	Name  string `json:"name"`
+	Ok    bool   `json:"ok"`
+	Err   string `json:"err,omitempty"`
+	Count int    `json:"count,omitempty"`
+}
+
+type ApiKeyResp struct {
+	Succ string `json:"succ"`
+	Err  string `json:"err"`
+	Code int    `json:"code"`
+	Data ApiKey `json:"data"`
+}
+
+type ApiKeyListResp struct {
+	Succ string   `json:"succ"`
+	Err  string   `json:"err"`
+	Code int      `json:"code"`
+	Data []ApiKey `json:"data"`
+}
+
+type AdminResultResp struct {
+	Succ string        `json:"succ"`
+	Err  string        `json:"err"`
+	Code int           `json:"code"`
+	Data []AdminResult `json:"data"`
+}
+
+// AdminToken 管理接口的令牌, 为空且没有 admin 权限的 API Key 时管理接口不可用
+var AdminToken string
+
+// ApiKeyRequired 为 true 时所有接口都需要 API Key
+var ApiKeyRequired bool
+
+// 手动刷新时同时请求上游的榜单数量
+var AdminRefreshConcurrency int = 4
@@ -0,0 +1,61 @@
+package globals
+
+import "time"
+
+// 告警原因
+const (
+	AlertReasonMatched     = "matched"      // 新出现的匹配条目
+	AlertReasonRankCrossed = "rank_crossed" // 已在榜的匹配条目排名进入 MinRank
+)
+
+// WatchRule 关注规则, Keywords 任一命中或 Regex 匹配标题、描述即视为命中
+// Platforms 为空时检查全部榜单, MinRank、MinHeat 为 0 时不限制
+type WatchRule struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Keywords  []string  `json:"keywords,omitempty"`
+	Regex     string    `json:"regex,omitempty"`
+	Platforms []string  `json:"platforms,omitempty"`
+	MinRank   int       `json:"min_rank,omitempty"`
+	MinHeat   int64     `json:"min_heat,omitempty"`
+	Notifiers []string  `json:"notifiers,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Alert 规则命中的一次告警
+type Alert struct {
+	Id       string      `json:"id"`
+	RuleId   string      `json:"rule_id"`
+	RuleName string      `json:"rule_name"`
+	Platform string      `json:"platform"`
+	Reason   string      `json:"reason" enum:"matched,rank_crossed"`
+	Item     GblRespData `json:"item"`
+	FiredAt  time.Time   `json:"fired_at"`
+}
+
+type WatchRuleResp struct {
+	Succ string    `json:"succ"`
+	Err  string    `json:"err"`
+	Code int       `json:"code"`
+	Data WatchRule `json:"data"`
+}
+
+type WatchRuleListResp struct {
+	Succ string      `json:"succ"`
+	Err  string      `json:"err"`
+	Code int         `json:"code"`
+	Data []WatchRule `json:"data"`
+}
+
+type AlertListResp struct {
+	Succ string  `json:"succ"`
+	Err  string  `json:"err"`
+	Code int     `json:"code"`
+	Data []Alert `json:"data"`
+}
+
+// 同一规则同一条目在该时间内只告警一次
+var AlertDedupWindow time.Duration = 6 * time.Hour
+
+// 内存中保留的告警数量
+var AlertKeep int = 1000
@@ -0,0 +1,13 @@
+package globals
+
+import "time"
+
+// Cache 榜单等数据的缓存, 单实例用进程内缓存, 多实例部署时用 Redis 共享
+type Cache interface {
+	// Get 读取未过期的值到 v(指针), 未命中时返回 false
+	Get(key string, v interface{}) bool
+	// Set 写入 v, ttl 后过期
+	Set(key string, v interface{}, ttl time.Duration)
+	// Lock 获取刷新锁, ttl 后自动释放; 已被其他请求或实例持有时 ok 为 false
+	Lock(key string, ttl time.Duration) (unlock func(), ok bool)
+}
@@ -0,0 +1,68 @@
+package globals
+
+import "time"
+
+// 摘要周期
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestItem 摘要中的一个条目, 统计自周期内的全部快照
+type DigestItem struct {
+	Platform    string    `json:"platform"`
+	Id          string    `json:"id"`
+	Title       string    `json:"title"`
+	ToUrl       string    `json:"to_url"`
+	HotVal      string    `json:"hot_val"`
+	BestPos     int       `json:"best_pos"`
+	Appearances int       `json:"appearances"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	Minutes     int64     `json:"minutes"`
+	RankGain    int       `json:"rank_gain,omitempty"`
+	Note        string    `json:"note,omitempty"`
+}
+
+// DigestBoard 单个榜单的前 N 条
+type DigestBoard struct {
+	Platform  string       `json:"platform"`
+	Snapshots int          `json:"snapshots"`
+	Items     []DigestItem `json:"items"`
+}
+
+// Digest 一期摘要
+// Overall 跨平台综合排名, Longest 在榜最久, Movers 排名上升最多, BoxOffice 新上映影片, AITools 增长最快的 AI 工具
+type Digest struct {
+	Id        string        `json:"id"`
+	Period    string        `json:"period" enum:"daily,weekly"`
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	CreatedAt time.Time     `json:"created_at"`
+	Boards    []DigestBoard `json:"boards"`
+	Overall   []DigestItem  `json:"overall"`
+	Longest   []DigestItem  `json:"longest"`
+	Movers    []DigestItem  `json:"movers"`
+	BoxOffice []DigestItem  `json:"box_office"`
+	AITools   []DigestItem  `json:"ai_tools"`
+}
+
+type DigestResp struct {
+	Succ string `json:"succ"`
+	Err  string `json:"err"`
+	Code int    `json:"code"`
+	Data Digest `json:"data"`
+}
+
+type DigestListResp struct {
+	Succ string   `json:"succ"`
+	Err  string   `json:"err"`
+	Code int      `json:"code"`
+	Data []Digest `json:"data"`
+}
+
+// 摘要各部分的条目数
+var DigestTop int = 10
+
+// 保留的摘要期数
+var DigestKeep int = 100
@@ -0,0 +1,33 @@
+package globals
+
+import "time"
+
+// 事件类型
+const (
+	EventBoardUpdated   = "board_updated"
+	EventProviderStatus = "provider_status"
+	EventAlertFired     = "alert_fired"
+)
+
+// Event 服务内部广播的事件, SSE 等推送接口按需取用其中的字段
+type Event struct {
+	Seq      uint64          `json:"seq"`
+	Type     string          `json:"type" enum:"board_updated,provider_status,alert_fired"`
+	Platform string          `json:"platform,omitempty"`
+	Time     time.Time       `json:"time"`
+	Snapshot *Snapshot       `json:"snapshot,omitempty"`
+	Diff     *BoardDiff      `json:"diff,omitempty"`
+	Status   *ProviderStatus `json:"status,omitempty"`
+	Alert    *Alert          `json:"alert,omitempty"`
+}
+
+// ProviderStatus 上游可用状态, 仅在状态变化时发布
+type ProviderStatus struct {
+	Platform string    `json:"platform"`
+	Ok       bool      `json:"ok"`
+	Err      string    `json:"err,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+// 推送接口的心跳间隔
+var StreamHeartbeat time.Duration = 15 * time.Second
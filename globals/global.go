@@ -3,19 +3,29 @@ package globals
 import (
 	"time"
 
-	"github.com/patrickmn/go-cache"
 	"github.com/sirupsen/logrus"
 )
 
-var GoCache *cache.Cache
+var GoCache Cache
 
 var HotCacheExpired time.Duration = 2 * time.Minute
 
+// 上游失败时返回的旧榜单保留时间
+var HotStaleExpired time.Duration = 24 * time.Hour
+
+// 快照按时间保留, 覆盖周摘要的 7 天再加 1 天余量
+// 最近 SnapshotFull 内每次刷新的快照都保留, 更早的每 SnapshotInterval 保留一份, 控制内存占用
+var SnapshotKeep time.Duration = 8 * 24 * time.Hour
+var SnapshotFull time.Duration = 12 * time.Hour
+var SnapshotInterval time.Duration = time.Hour
+
 var GoLogger *logrus.Logger
 
 // var GoLogFields = logrus.Fields{}
 
 type GblRespData struct {
+	// 条目ID, 同一平台下多次刷新保持不变, 格式见 utils.GetItemId
+	Id     string `json:"id"`
 	Title  string `json:"title"`
 	Desc   string `json:"desc"`
 	HotVal string `json:"hot_val"`
@@ -24,15 +34,75 @@ type GblRespData struct {
 	ToUrl  string `json:"to_url"`
 	Lab    string `json:"label"`
 	IsTop  int    `json:"is_top"`
+	// 以下为可选字段, 上游没有提供时不输出
+	Author      string   `json:"author,omitempty"`
+	PublishedAt string   `json:"published_at,omitempty"` // RFC3339
+	Views       int64    `json:"views,omitempty"`
+	Likes       int64    `json:"likes,omitempty"`
+	Comments    int64    `json:"comments,omitempty"`
+	Badges      []string `json:"badges,omitempty"`
+	// 聚合榜单中标明条目来源
+	Platform string `json:"platform,omitempty"`
+	// 各平台特有的字段, key 见下方 Extra* 常量
+	Extra GblExtra `json:"extra,omitempty"`
+}
+
+// GblExtra 平台特有属性, 避免为个别平台单独定义输出结构
+type GblExtra map[string]interface{}
+
+// Set 在 v 非零值时写入, 返回自身便于链式调用
+func (e *GblExtra) Set(k string, v interface{}) *GblExtra {
+	switch val := v.(type) {
+	case nil:
+		return e
+	case string:
+		if val == "" {
+			return e
+		}
+	case []string:
+		if len(val) == 0 {
+			return e
+		}
+	}
+	if *e == nil {
+		*e = GblExtra{}
+	}
+	(*e)[k] = v
+	return e
 }
 
+// Extra 常用 key
+const (
+	ExtraTags          = "tags"
+	ExtraGrowth        = "growth"
+	ExtraGrowthRate    = "growth_rate"
+	ExtraMonthlyVisits = "monthly_visits"
+	ExtraReleaseDate   = "release_date"
+	ExtraBoxOffice     = "box_office"
+	ExtraAbstract      = "abstract"
+	ExtraGroupId       = "group_id"
+	// ?lang= 翻译后的原标题
+	ExtraOriginalTitle = "original_title"
+)
+
 type GblResp struct {
 	Succ string        `json:"succ"`
 	Err  string        `json:"err"`
 	Code int           `json:"code"`
 	Data []GblRespData `json:"data"`
+	// 上游失败时返回的是之前缓存的旧榜单
+	Stale bool `json:"stale,omitempty"`
 }
 
+// 榜单分类
+const (
+	CategoryNews  = "news"  // 娱乐榜
+	CategoryTech  = "tech"  // 技术榜
+	CategoryCar   = "car"   // 汽车榜
+	CategoryMovie = "movie" // 票房榜
+	CategoryAI    = "ai"    // ai榜
+)
+
 // 娱乐榜
 var (
 	BiliFlag     string = "bilibili"
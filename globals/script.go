@@ -0,0 +1,15 @@
+package globals
+
+import "time"
+
+// 自定义榜单后处理脚本的执行时间上限
+var ScriptTimeout time.Duration = 2 * time.Second
+
+// 脚本执行期间可分配的元素数量上限, 见 expr vm.VM.MemoryBudget
+var ScriptMemoryBudget uint = 1e6
+
+// 脚本语法树的节点数量上限
+var ScriptMaxNodes uint = 10000
+
+// 脚本中 map、filter 等循环体的执行次数上限
+var ScriptMaxSteps int = 1e6
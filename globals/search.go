@@ -0,0 +1,27 @@
+package globals
+
+import "time"
+
+// SearchHit 一个搜索结果, 同一平台同一条目ID的多次上榜合并为一条
+// Hits 为时间范围内上榜的快照数, Title 等为最后一次上榜时的内容
+type SearchHit struct {
+	Platform  string    `json:"platform"`
+	Id        string    `json:"id"`
+	Title     string    `json:"title"`
+	Desc      string    `json:"desc"`
+	ToUrl     string    `json:"to_url"`
+	HotVal    string    `json:"hot_val"`
+	BestPos   int       `json:"best_pos"`
+	Hits      int       `json:"hits"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Score     float64   `json:"score"`
+}
+
+type SearchResp struct {
+	Succ  string      `json:"succ"`
+	Err   string      `json:"err"`
+	Code  int         `json:"code"`
+	Total int         `json:"total"`
+	Data  []SearchHit `json:"data"`
+}
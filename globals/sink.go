@@ -0,0 +1,15 @@
+package globals
+
+import "time"
+
+// 每批写入的快照数量, 积压达到该数量时立即写入
+var SinkBatchSize int = 50
+
+// 未攒满一批时的写入间隔
+var SinkFlushInterval time.Duration = 5 * time.Second
+
+// 单批写入的超时时间
+var SinkWriteTimeout time.Duration = 30 * time.Second
+
+// 写入失败时最多积压的快照数量
+var SinkPendingKeep int = 1000
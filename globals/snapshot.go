@@ -0,0 +1,86 @@
+package globals
+
+import "time"
+
+// Snapshot 某个榜单在某一时刻的完整数据
+type Snapshot struct {
+	Id        string        `json:"id"`
+	Platform  string        `json:"platform"`
+	FetchedAt time.Time     `json:"fetched_at"`
+	Data      []GblRespData `json:"data"`
+}
+
+// SnapshotStore 快照存储, 由 main 初始化到 GoSnapshots
+type SnapshotStore interface {
+	// Save 保存一份快照
+	Save(s Snapshot) error
+	// Get 按快照ID读取
+	Get(platform, id string) (Snapshot, bool)
+	// Latest 最新一份快照
+	Latest(platform string) (Snapshot, bool)
+	// At 不晚于 t 的最后一份快照
+	At(platform string, t time.Time) (Snapshot, bool)
+	// Platforms 有快照的榜单标识
+	Platforms() []string
+	// List 按时间升序返回 [from, to] 内的快照, 零值表示不限制
+	List(platform string, from, to time.Time) []Snapshot
+	// FirstSeen 条目第一次出现在快照中的时间
+	FirstSeen(platform, itemId string) (time.Time, bool)
+}
+
+var GoSnapshots SnapshotStore
+
+// ItemRecord 持久化存储中一个条目的累计记录, Item 为最近一次上榜的内容
+type ItemRecord struct {
+	Platform    string
+	Item        GblRespData
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	BestPos     int
+	Appearances int
+}
+
+// DiffEntry 榜单对比中的一条记录, 不存在的一侧排名为 0
+type DiffEntry struct {
+	Id        string `json:"id"`
+	Title     string `json:"title"`
+	ToUrl     string `json:"to_url"`
+	OldPos    int    `json:"old_pos"`
+	NewPos    int    `json:"new_pos"`
+	OldHotVal string `json:"old_hot_val"`
+	NewHotVal string `json:"new_hot_val"`
+	HotDelta  int64  `json:"hot_delta"`
+}
+
+// BoardDiff 两份快照之间的变化
+type BoardDiff struct {
+	Platform    string      `json:"platform"`
+	From        string      `json:"from"`
+	To          string      `json:"to"`
+	FromTime    time.Time   `json:"from_time"`
+	ToTime      time.Time   `json:"to_time"`
+	Entered     []DiffEntry `json:"entered"`
+	Left        []DiffEntry `json:"left"`
+	MovedUp     []DiffEntry `json:"moved_up"`
+	MovedDown   []DiffEntry `json:"moved_down"`
+	HeatChanged []DiffEntry `json:"heat_changed"`
+}
+
+// Empty 是否没有任何变化
+func (d BoardDiff) Empty() bool {
+	return len(d.Entered) == 0 && len(d.Left) == 0 && len(d.MovedUp) == 0 && len(d.MovedDown) == 0 && len(d.HeatChanged) == 0
+}
+
+type DiffResp struct {
+	Succ string     `json:"succ"`
+	Err  string     `json:"err"`
+	Code int        `json:"code"`
+	Data *BoardDiff `json:"data"`
+}
+
+type SnapshotListResp struct {
+	Succ string     `json:"succ"`
+	Err  string     `json:"err"`
+	Code int        `json:"code"`
+	Data []Snapshot `json:"data"`
+}
@@ -0,0 +1,30 @@
+package globals
+
+import "time"
+
+// Term 一个热词, Count 为时间窗口内包含该词的条目数, Baseline 为对比时段内的条目数
+// Weight 为 1~100 的相对权重, 可直接用于词云
+type Term struct {
+	Term     string  `json:"term"`
+	Count    int     `json:"count"`
+	Baseline int     `json:"baseline"`
+	Score    float64 `json:"score"`
+	Weight   int     `json:"weight"`
+}
+
+// TermsData 全部平台合并的热词以及各平台的热词, Baseline 为实际使用的对比时段
+type TermsData struct {
+	Window    string            `json:"window"`
+	Baseline  string            `json:"baseline"`
+	From      time.Time         `json:"from"`
+	To        time.Time         `json:"to"`
+	Overall   []Term            `json:"overall"`
+	Platforms map[string][]Term `json:"platforms"`
+}
+
+type TermsResp struct {
+	Succ string    `json:"succ"`
+	Err  string    `json:"err"`
+	Code int       `json:"code"`
+	Data TermsData `json:"data"`
+}
@@ -0,0 +1,18 @@
+package globals
+
+import "time"
+
+// 译文缓存时间, 标题不变时不会重复翻译
+var TranslateCacheExpired time.Duration = 30 * 24 * time.Hour
+
+// 每次请求翻译后端的默认条数
+var TranslateBatch int = 50
+
+// 刷新后翻译一个榜单一种语言的超时时间
+var TranslateTimeout time.Duration = 2 * time.Minute
+
+// 请求榜单时同步翻译的超时时间, 超时后未翻译的条目保留原文
+var TranslateRequestTimeout time.Duration = 15 * time.Second
+
+// 翻译失败的文本在该时间内不再请求后端, 避免后端故障时每次请求都等到超时
+var TranslateFailedExpired time.Duration = time.Minute
@@ -0,0 +1,102 @@
+package globals
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook 可订阅的事件类型
+const (
+	WebhookBoardUpdated = "board_updated"
+	WebhookNewEntry     = "new_entry"
+	WebhookAlertFired   = "alert_fired"
+	WebhookProviderDown = "provider_down"
+)
+
+// 投递状态
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook 回调地址, Events、Platforms 为空时接收全部
+// Secret 用于 HMAC-SHA256 签名, 只在创建时返回
+type Webhook struct {
+	Id        string    `json:"id"`
+	Url       string    `json:"url"`
+	Events    []string  `json:"events,omitempty" enum:"board_updated,new_entry,alert_fired,provider_down"`
+	Platforms []string  `json:"platforms,omitempty"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookPayload 投递的请求体, 重放时 Id 不变, 接收方可据此去重
+type WebhookPayload struct {
+	Id       string      `json:"id"`
+	Event    string      `json:"event"`
+	Platform string      `json:"platform,omitempty"`
+	Time     time.Time   `json:"time"`
+	Data     interface{} `json:"data"`
+}
+
+// Delivery 一次投递及其全部尝试
+type Delivery struct {
+	Id        string            `json:"id"`
+	WebhookId string            `json:"webhook_id"`
+	Event     string            `json:"event"`
+	ReplayOf  string            `json:"replay_of,omitempty"`
+	Status    string            `json:"status" enum:"pending,succeeded,failed"`
+	Payload   json.RawMessage   `json:"payload"`
+	Attempts  []DeliveryAttempt `json:"attempts"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// DeliveryAttempt 一次请求的结果, 非 2xx 或请求出错都算失败
+type DeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Err        string    `json:"err,omitempty"`
+	Duration   int64     `json:"duration_ms"`
+}
+
+type WebhookResp struct {
+	Succ string  `json:"succ"`
+	Err  string  `json:"err"`
+	Code int     `json:"code"`
+	Data Webhook `json:"data"`
+}
+
+type WebhookListResp struct {
+	Succ string    `json:"succ"`
+	Err  string    `json:"err"`
+	Code int       `json:"code"`
+	Data []Webhook `json:"data"`
+}
+
+type DeliveryResp struct {
+	Succ string   `json:"succ"`
+	Err  string   `json:"err"`
+	Code int      `json:"code"`
+	Data Delivery `json:"data"`
+}
+
+type DeliveryListResp struct {
+	Succ string     `json:"succ"`
+	Err  string     `json:"err"`
+	Code int        `json:"code"`
+	Data []Delivery `json:"data"`
+}
+
+// 每次投递最多尝试的次数, 第 n 次重试前等待 WebhookBackoff * 2^(n-1)
+var WebhookMaxAttempts int = 5
+var WebhookBackoff time.Duration = 2 * time.Second
+
+// 单次请求超时
+var WebhookTimeout time.Duration = 10 * time.Second
+
+// 内存中保留的投递记录数量
+var DeliveryKeep int = 1000
+
+// 允许投递的内网地址, 逗号分隔的 IP 或 CIDR, 如 10.0.0.0/8; 默认拒绝回环、链路本地和私有地址
+var WebhookAllowNets []string
@@ -0,0 +1,39 @@
+package globals
+
+// WebSocket 客户端发送的操作
+const (
+	WsActionSubscribe   = "subscribe"
+	WsActionUnsubscribe = "unsubscribe"
+	WsActionPing        = "ping"
+)
+
+// WebSocket 服务端推送的消息类型
+const (
+	WsMsgAck            = "ack"
+	WsMsgError          = "error"
+	WsMsgPong           = "pong"
+	WsMsgSnapshot       = "snapshot"
+	WsMsgDiff           = "diff"
+	WsMsgAlert          = "alert"
+	WsMsgProviderStatus = "provider_status"
+)
+
+// 每个连接最多订阅的 平台+分类+关键词 数量
+var WsMaxTopics int = 50
+
+// WsRequest 客户端消息, 如 {"action":"subscribe","platforms":["weibo"],"keywords":["苹果"]}
+type WsRequest struct {
+	Action     string   `json:"action" enum:"subscribe,unsubscribe,ping"`
+	Platforms  []string `json:"platforms,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Keywords   []string `json:"keywords,omitempty"`
+}
+
+// WsMessage 服务端消息, Data 随 Type 不同为 WsRequest(ack)、Snapshot、BoardDiff、Alert 或 ProviderStatus
+type WsMessage struct {
+	Type     string      `json:"type" enum:"ack,error,pong,snapshot,diff,alert,provider_status"`
+	Seq      uint64      `json:"seq,omitempty"`
+	Platform string      `json:"platform,omitempty"`
+	Err      string      `json:"err,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+}
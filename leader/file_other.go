@@ -0,0 +1,23 @@
+//go:build !unix
+
+package leader
+
+import (
+	"context"
+	"errors"
+)
+
+// FileLock 仅支持类 Unix 系统
+type FileLock struct{}
+
+func NewFileLock(path string) *FileLock {
+	return &FileLock{}
+}
+
+func (l *FileLock) Acquire(ctx context.Context) (bool, error) {
+	return false, errors.New("file lock is not supported on this platform")
+}
+
+func (l *FileLock) Release(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,57 @@
+//go:build unix
+
+package leader
+
+import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// FileLock 单机多进程时用文件锁选举, 进程退出后系统自动释放
+type FileLock struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+func (l *FileLock) Acquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		return true, nil
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return false, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if err == syscall.EWOULDBLOCK {
+			return false, nil
+		}
+		return false, err
+	}
+
+	l.file = f
+	return true, nil
+}
+
+func (l *FileLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package leader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hots.leader.lock")
+	ctx := context.Background()
+
+	a, b := NewFileLock(path), NewFileLock(path)
+	if ok, err := a.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("a acquire = %v, %v", ok, err)
+	}
+	// 持有时续期直接成功
+	if ok, err := a.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("a renew = %v, %v", ok, err)
+	}
+	if ok, err := b.Acquire(ctx); ok || err != nil {
+		t.Fatalf("b acquire while a holds = %v, %v", ok, err)
+	}
+
+	if err := a.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := b.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("b acquire after release = %v, %v", ok, err)
+	}
+	b.Release(ctx)
+}
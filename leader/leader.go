@@ -0,0 +1,73 @@
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Elector 领导权租约, 同一时间只有一个实例持有
+type Elector interface {
+	// Acquire 获取或续期租约, 返回当前是否持有
+	Acquire(ctx context.Context) (bool, error)
+	// Release 主动释放租约, 便于其他实例尽快接管
+	Release(ctx context.Context) error
+}
+
+// 选举开始前不是 leader, 避免启动时与现有 leader 同时请求上游
+var isLeader int32
+
+// Standalone 未开启选举时调用, 本实例始终是 leader
+func Standalone() {
+	atomic.StoreInt32(&isLeader, 1)
+}
+
+// IsLeader 当前实例是否负责请求上游、推送通知
+func IsLeader() bool {
+	return atomic.LoadInt32(&isLeader) == 1
+}
+
+func setLeader(v bool) {
+	var n int32
+	if v {
+		n = 1
+	}
+	if atomic.SwapInt32(&isLeader, n) != n {
+		if v {
+			globals.GoLogger.Infof("LEADER ELECTED")
+		} else {
+			globals.GoLogger.Infof("LEADER LOST")
+		}
+	}
+}
+
+// Run 每隔 interval 获取或续期一次租约, 出错时视为失去领导权; ctx 结束时释放租约
+// interval 应明显小于租约有效期, 否则续期前租约可能已过期
+func Run(ctx context.Context, e Elector, interval time.Duration) {
+	setLeader(false)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := e.Acquire(ctx)
+		if err != nil {
+			globals.GoLogger.Errorf("LEADER ACQUIRE ERR %s", err.Error())
+		}
+		setLeader(ok && err == nil)
+
+		select {
+		case <-ctx.Done():
+			setLeader(false)
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			if err := e.Release(releaseCtx); err != nil {
+				globals.GoLogger.Errorf("LEADER RELEASE ERR %s", err.Error())
+			}
+			cancel()
+			return
+		case <-ticker.C:
+		}
+	}
+}
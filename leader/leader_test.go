@@ -0,0 +1,74 @@
+package leader
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// fakeElector 按 grant 决定是否授予租约
+type fakeElector struct {
+	grant    atomic.Bool
+	released atomic.Bool
+}
+
+func (e *fakeElector) Acquire(ctx context.Context) (bool, error) {
+	return e.grant.Load(), nil
+}
+
+func (e *fakeElector) Release(ctx context.Context) error {
+	e.released.Store(true)
+	return nil
+}
+
+func waitLeader(t *testing.T, want bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for IsLeader() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("IsLeader() = %v, want %v", !want, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRun(t *testing.T) {
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+
+	if IsLeader() {
+		t.Fatal("leader before election")
+	}
+
+	e := &fakeElector{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, e, 5*time.Millisecond)
+		close(done)
+	}()
+
+	e.grant.Store(true)
+	waitLeader(t, true)
+	// 续期失败后失去领导权
+	e.grant.Store(false)
+	waitLeader(t, false)
+
+	e.grant.Store(true)
+	waitLeader(t, true)
+	cancel()
+	<-done
+	if IsLeader() || !e.released.Load() {
+		t.Errorf("after stop leader = %v, released = %v", IsLeader(), e.released.Load())
+	}
+
+	Standalone()
+	if !IsLeader() {
+		t.Error("standalone instance is not leader")
+	}
+}
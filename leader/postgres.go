@@ -0,0 +1,75 @@
+package leader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Postgres 会话级 advisory lock, 连接断开后数据库自动释放
+// 未获取到锁时保留连接, 下次直接在同一连接上重试
+type Postgres struct {
+	mu     sync.Mutex
+	dsn    string
+	lockId int64
+	conn   *pgx.Conn
+	held   bool
+}
+
+// NewPostgres lockId 为各实例约定的锁编号
+func NewPostgres(dsn string, lockId int64) *Postgres {
+	return &Postgres{dsn: dsn, lockId: lockId}
+}
+
+func (p *Postgres) Acquire(ctx context.Context) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 连接断开时锁已被释放, 重新连接后再尝试
+	if p.conn != nil {
+		if err := p.conn.Ping(ctx); err != nil {
+			p.close()
+		} else if p.held {
+			return true, nil
+		}
+	}
+
+	if p.conn == nil {
+		conn, err := pgx.Connect(ctx, p.dsn)
+		if err != nil {
+			return false, err
+		}
+		p.conn = conn
+	}
+
+	var ok bool
+	if err := p.conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", p.lockId).Scan(&ok); err != nil {
+		p.close()
+		return false, err
+	}
+	p.held = ok
+	return ok, nil
+}
+
+func (p *Postgres) Release(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+	var err error
+	if p.held {
+		_, err = p.conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", p.lockId)
+	}
+	p.close()
+	return err
+}
+
+// close 调用方需持有锁
+func (p *Postgres) close() {
+	p.conn.Close(context.Background())
+	p.conn = nil
+	p.held = false
+}
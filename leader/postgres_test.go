@@ -0,0 +1,45 @@
+package leader
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+// 需要设置 DATABASE_URL
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	// 与服务使用的锁编号不同, 避免测试库被正在运行的实例占用
+	const lockId = 0x686f7400
+	a, b := NewPostgres(dsn, lockId), NewPostgres(dsn, lockId)
+	t.Cleanup(func() {
+		a.Release(context.Background())
+		b.Release(context.Background())
+	})
+
+	if ok, err := a.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("a acquire = %v, %v", ok, err)
+	}
+	if ok, err := a.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("a renew = %v, %v", ok, err)
+	}
+	if ok, err := b.Acquire(ctx); ok || err != nil {
+		t.Fatalf("b acquire while a holds = %v, %v", ok, err)
+	}
+
+	// 连接断开后锁自动释放, b 在保留的连接上重试即可接管
+	a.mu.Lock()
+	a.close()
+	a.mu.Unlock()
+	if ok, err := b.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("b acquire after a disconnected = %v, %v", ok, err)
+	}
+	if ok, _ := a.Acquire(ctx); ok {
+		t.Error("a reacquired while b holds")
+	}
+}
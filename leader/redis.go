@@ -0,0 +1,52 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis 以带过期时间的键作为租约, 持有者定期续期, 停止续期 ttl 后其他实例接管
+type Redis struct {
+	client redis.UniversalClient
+	key    string
+	id     string
+	ttl    time.Duration
+}
+
+// NewRedis id 为本实例的唯一标识, 如 主机名:进程号
+func NewRedis(client redis.UniversalClient, key, id string, ttl time.Duration) *Redis {
+	return &Redis{client: client, key: key, id: id, ttl: ttl}
+}
+
+// 租约属于自己时续期, 不存在时获取
+var acquireScript = redis.NewScript(`
+local owner = redis.call("GET", KEYS[1])
+if owner == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+if not owner then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+func (r *Redis) Acquire(ctx context.Context) (bool, error) {
+	n, err := acquireScript.Run(ctx, r.client, []string{r.key}, r.id, r.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *Redis) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, r.client, []string{r.key}, r.id).Err()
+}
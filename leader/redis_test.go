@@ -0,0 +1,62 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedis(t *testing.T) {
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+	ctx := context.Background()
+
+	a := NewRedis(client, "hots:leader", "a", 15*time.Second)
+	b := NewRedis(client, "hots:leader", "b", 15*time.Second)
+
+	if ok, err := a.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("a acquire = %v, %v", ok, err)
+	}
+	if ok, err := b.Acquire(ctx); ok || err != nil {
+		t.Fatalf("b acquire while a holds = %v, %v", ok, err)
+	}
+
+	// 续期后重新计算有效期
+	srv.FastForward(10 * time.Second)
+	if ok, err := a.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("a renew = %v, %v", ok, err)
+	}
+	if ttl := srv.TTL("hots:leader"); ttl != 15*time.Second {
+		t.Errorf("ttl after renew = %s, want 15s", ttl)
+	}
+	srv.FastForward(10 * time.Second)
+	if ok, _ := b.Acquire(ctx); ok {
+		t.Fatal("b took over a renewed lease")
+	}
+
+	// a 停止续期, 过期后 b 接管, a 释放不影响 b
+	srv.FastForward(5 * time.Second)
+	if ok, err := b.Acquire(ctx); !ok || err != nil {
+		t.Fatalf("b acquire after expiry = %v, %v", ok, err)
+	}
+	if ok, _ := a.Acquire(ctx); ok {
+		t.Error("a acquired while b holds")
+	}
+	if err := a.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if owner, _ := srv.Get("hots:leader"); owner != "b" {
+		t.Errorf("owner after a release = %q, want b", owner)
+	}
+
+	if err := b.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := a.Acquire(ctx); !ok {
+		t.Error("a acquire after b release failed")
+	}
+}
@@ -0,0 +1,172 @@
+package notifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// 机器人类型
+const (
+	TypeDingTalk = "dingtalk"
+	TypeFeishu   = "feishu"
+	TypeLark     = "lark"
+	TypeWeCom    = "wecom"
+	TypeTelegram = "telegram"
+)
+
+// Config 一个群机器人, Name 供关注规则的 notifiers 引用
+// Token 依类型为钉钉 access_token、飞书 hook ID、企业微信 key 或 Telegram bot token
+// Secret 为钉钉、飞书的加签密钥, BaseUrl 为空时使用官方地址
+type Config struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Token     string `json:"token"`
+	Secret    string `json:"secret,omitempty"`
+	ChatId    string `json:"chat_id,omitempty"`
+	BaseUrl   string `json:"base_url,omitempty"`
+	RateLimit int    `json:"rate_limit,omitempty"`
+}
+
+// Message 推送的内容, 条目按 排名、标题链接、热度 渲染
+type Message struct {
+	Title string
+	Text  string
+	Items []globals.GblRespData
+}
+
+// platform 各平台的请求格式、签名和响应检查
+type platform interface {
+	defaultBaseUrl() string
+	// 每分钟最多发送条数
+	defaultRateLimit() int
+	request(cfg Config, msg Message, now time.Time) (url string, body interface{}, err error)
+	check(content []byte) error
+}
+
+var platforms = map[string]platform{
+	TypeDingTalk: dingTalk{},
+	TypeFeishu:   feishu{base: "https://open.feishu.cn"},
+	TypeLark:     feishu{base: "https://open.larksuite.com"},
+	TypeWeCom:    weCom{},
+	TypeTelegram: telegram{},
+}
+
+// Bot 群机器人, 实现 alerts.Notifier
+type Bot struct {
+	cfg      Config
+	platform platform
+	client   *http.Client
+	limiter  *limiter
+}
+
+func New(cfg Config) (*Bot, error) {
+	p, ok := platforms[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown notifier type: %s", cfg.Type)
+	}
+	if cfg.Name == "" || cfg.Token == "" {
+		return nil, errors.New("name and token are required")
+	}
+	if cfg.Type == TypeTelegram && cfg.ChatId == "" {
+		return nil, errors.New("chat_id is required")
+	}
+	if cfg.BaseUrl == "" {
+		cfg.BaseUrl = p.defaultBaseUrl()
+	}
+	if cfg.RateLimit <= 0 {
+		cfg.RateLimit = p.defaultRateLimit()
+	}
+
+	return &Bot{
+		cfg:      cfg,
+		platform: p,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		limiter:  newLimiter(cfg.RateLimit, time.Minute),
+	}, nil
+}
+
+// Load 从 JSON 文件读取机器人列表
+func Load(file string) ([]*Bot, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Config
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+
+	var result []*Bot
+	for _, cfg := range list {
+		b, err := New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("notifier %s: %w", cfg.Name, err)
+		}
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+func (b *Bot) Name() string {
+	return b.cfg.Name
+}
+
+func (b *Bot) Notify(alert globals.Alert) error {
+	return b.Send(AlertMessage(alert))
+}
+
+// Send 超过频率限制时排队等待, 排队过长直接丢弃
+func (b *Bot) Send(msg Message) error {
+	if !b.limiter.wait() {
+		return errors.New("rate limited")
+	}
+
+	url, body, err := b.platform.request(b.cfg, msg, time.Now())
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, content)
+	}
+	return b.platform.check(content)
+}
+
+// AlertMessage 告警转换为消息
+func AlertMessage(alert globals.Alert) Message {
+	text := "新上榜"
+	if alert.Reason == globals.AlertReasonRankCrossed {
+		text = fmt.Sprintf("排名升至第 %d", alert.Item.Pos)
+	}
+
+	item := alert.Item
+	item.Platform = alert.Platform
+	return Message{
+		Title: "关注「" + alert.RuleName + "」命中",
+		Text:  text,
+		Items: []globals.GblRespData{item},
+	}
+}
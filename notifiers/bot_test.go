@@ -0,0 +1,234 @@
+package notifiers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// captured 假服务收到的请求
+type captured struct {
+	path  string
+	query url.Values
+	body  map[string]interface{}
+}
+
+// fakeServer 记录请求并返回 reply
+func fakeServer(t *testing.T, reply string) (*httptest.Server, *captured) {
+	t.Helper()
+	got := &captured{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+		got.path = r.URL.Path
+		got.query = r.URL.Query()
+		if err := json.Unmarshal(content, &got.body); err != nil {
+			t.Errorf("request body %s: %s", content, err)
+		}
+		io.WriteString(w, reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+var testMessage = Message{
+	Title: "关注「苹果」命中",
+	Text:  "新上榜",
+	Items: []globals.GblRespData{{Title: "苹果发布会", ToUrl: "https://example.com/1", HotVal: "123", Pos: 1, Platform: "weibo"}},
+}
+
+func newTestBot(t *testing.T, cfg Config) *Bot {
+	t.Helper()
+	b, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func hmacBase64(key, data string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestDingTalkSignature(t *testing.T) {
+	srv, got := fakeServer(t, `{"errcode":0,"errmsg":"ok"}`)
+	b := newTestBot(t, Config{Name: "ding", Type: TypeDingTalk, Token: "tok", Secret: "SEC123", BaseUrl: srv.URL})
+
+	if err := b.Send(testMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.path != "/robot/send" || got.query.Get("access_token") != "tok" {
+		t.Errorf("url = %s?%s", got.path, got.query.Encode())
+	}
+	// 钉钉要求毫秒时间戳, 与服务器时间相差 1 小时以内
+	timestamp := got.query.Get("timestamp")
+	ms, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(timestamp) != 13 || time.Since(time.UnixMilli(ms)).Abs() > time.Minute {
+		t.Errorf("timestamp = %q, want current unix milliseconds", timestamp)
+	}
+	if want := hmacBase64("SEC123", timestamp+"\nSEC123"); got.query.Get("sign") != want {
+		t.Errorf("sign = %q, want %q", got.query.Get("sign"), want)
+	}
+
+	if got.body["msgtype"] != "markdown" {
+		t.Errorf("msgtype = %v", got.body["msgtype"])
+	}
+	md, _ := got.body["markdown"].(map[string]interface{})
+	if md["title"] != testMessage.Title || !strings.Contains(md["text"].(string), "[苹果发布会](https://example.com/1)") {
+		t.Errorf("markdown = %v", md)
+	}
+}
+
+func TestDingTalkError(t *testing.T) {
+	srv, _ := fakeServer(t, `{"errcode":310000,"errmsg":"sign not match"}`)
+	b := newTestBot(t, Config{Name: "ding", Type: TypeDingTalk, Token: "tok", BaseUrl: srv.URL})
+
+	if err := b.Send(testMessage); err == nil || !strings.Contains(err.Error(), "310000") {
+		t.Errorf("err = %v, want errcode 310000", err)
+	}
+}
+
+func TestFeishuPayload(t *testing.T) {
+	srv, got := fakeServer(t, `{"code":0,"msg":"success"}`)
+	b := newTestBot(t, Config{Name: "fs", Type: TypeFeishu, Token: "hook-id", Secret: "SEC", BaseUrl: srv.URL})
+
+	if err := b.Send(testMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.path != "/open-apis/bot/v2/hook/hook-id" {
+		t.Errorf("path = %s", got.path)
+	}
+	if got.body["msg_type"] != "interactive" {
+		t.Errorf("msg_type = %v", got.body["msg_type"])
+	}
+	card, _ := got.body["card"].(map[string]interface{})
+	header, _ := card["header"].(map[string]interface{})
+	title, _ := header["title"].(map[string]interface{})
+	if title["content"] != testMessage.Title {
+		t.Errorf("card title = %v", title)
+	}
+	elements, _ := card["elements"].([]interface{})
+	if len(elements) != 1 || !strings.Contains(mustJson(t, elements[0]), "苹果发布会") {
+		t.Errorf("card elements = %v", elements)
+	}
+
+	// 飞书为秒级时间戳, 以 "时间戳\nsecret" 为密钥签名空串
+	timestamp, _ := got.body["timestamp"].(string)
+	if sec, err := strconv.ParseInt(timestamp, 10, 64); err != nil || time.Since(time.Unix(sec, 0)).Abs() > time.Minute {
+		t.Errorf("timestamp = %q, want current unix seconds", timestamp)
+	}
+	if want := hmacBase64(timestamp+"\nSEC", ""); got.body["sign"] != want {
+		t.Errorf("sign = %v, want %s", got.body["sign"], want)
+	}
+}
+
+func TestWeComPayload(t *testing.T) {
+	srv, got := fakeServer(t, `{"errcode":0,"errmsg":"ok"}`)
+	b := newTestBot(t, Config{Name: "wc", Type: TypeWeCom, Token: "key-1", BaseUrl: srv.URL})
+
+	if err := b.Send(testMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.path != "/cgi-bin/webhook/send" || got.query.Get("key") != "key-1" {
+		t.Errorf("url = %s?%s", got.path, got.query.Encode())
+	}
+	md, _ := got.body["markdown"].(map[string]interface{})
+	if got.body["msgtype"] != "markdown" || !strings.HasPrefix(md["content"].(string), "### "+testMessage.Title) {
+		t.Errorf("body = %v", got.body)
+	}
+}
+
+func TestTelegramPayload(t *testing.T) {
+	srv, got := fakeServer(t, `{"ok":true}`)
+	b := newTestBot(t, Config{Name: "tg", Type: TypeTelegram, Token: "123:abc", ChatId: "-100", BaseUrl: srv.URL})
+
+	if err := b.Send(testMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.path != "/bot123:abc/sendMessage" {
+		t.Errorf("path = %s", got.path)
+	}
+	if got.body["chat_id"] != "-100" || got.body["parse_mode"] != "HTML" || got.body["disable_web_page_preview"] != true {
+		t.Errorf("body = %v", got.body)
+	}
+	if text, _ := got.body["text"].(string); !strings.Contains(text, `<a href="https://example.com/1">苹果发布会</a>`) {
+		t.Errorf("text = %q", text)
+	}
+}
+
+func TestTelegramError(t *testing.T) {
+	srv, _ := fakeServer(t, `{"ok":false,"description":"chat not found"}`)
+	b := newTestBot(t, Config{Name: "tg", Type: TypeTelegram, Token: "123:abc", ChatId: "-100", BaseUrl: srv.URL})
+
+	if err := b.Send(testMessage); err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(2, 100*time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if !l.wait() {
+			t.Fatal("wait within limit returned false")
+		}
+	}
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Errorf("first 2 sends waited %s", d)
+	}
+
+	// 第 3 条排到窗口之后
+	if !l.wait() {
+		t.Fatal("third wait returned false")
+	}
+	if d := time.Since(start); d < 90*time.Millisecond {
+		t.Errorf("third send waited only %s", d)
+	}
+
+	// 需要等待超过 2 个窗口时直接丢弃
+	l = newLimiter(1, time.Hour)
+	l.times = []time.Time{time.Now().Add(2 * time.Hour)}
+	start = time.Now()
+	if l.wait() {
+		t.Error("wait beyond 2 windows returned true")
+	}
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Errorf("dropped send waited %s", d)
+	}
+}
+
+func TestBotRateLimited(t *testing.T) {
+	srv, _ := fakeServer(t, `{"errcode":0}`)
+	b := newTestBot(t, Config{Name: "wc", Type: TypeWeCom, Token: "k", BaseUrl: srv.URL, RateLimit: 1})
+	b.limiter.times = []time.Time{time.Now().Add(2 * time.Minute)}
+
+	if err := b.Send(testMessage); err == nil || err.Error() != "rate limited" {
+		t.Errorf("err = %v, want rate limited", err)
+	}
+}
+
+func mustJson(t *testing.T, v interface{}) string {
+	t.Helper()
+	content, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
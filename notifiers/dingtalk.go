@@ -0,0 +1,58 @@
+package notifiers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// dingTalk 钉钉自定义机器人, 每个机器人每分钟最多 20 条
+type dingTalk struct{}
+
+func (dingTalk) defaultBaseUrl() string {
+	return "https://oapi.dingtalk.com"
+}
+
+func (dingTalk) defaultRateLimit() int {
+	return 20
+}
+
+// request 加签: 以 secret 为密钥对 "毫秒时间戳\nsecret" 做 HMAC-SHA256 后 base64
+func (dingTalk) request(cfg Config, msg Message, now time.Time) (string, interface{}, error) {
+	query := url.Values{"access_token": {cfg.Token}}
+	if cfg.Secret != "" {
+		timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+		mac := hmac.New(sha256.New, []byte(cfg.Secret))
+		mac.Write([]byte(timestamp + "\n" + cfg.Secret))
+		query.Set("timestamp", timestamp)
+		query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	}
+
+	body := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": msg.Title,
+			"text":  markdown(msg),
+		},
+	}
+	return cfg.BaseUrl + "/robot/send?" + query.Encode(), body, nil
+}
+
+func (dingTalk) check(content []byte) error {
+	var resp struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(content, &resp); err != nil {
+		return err
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("dingtalk errcode %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
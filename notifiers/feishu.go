@@ -0,0 +1,72 @@
+package notifiers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// feishu 飞书/Lark 自定义机器人, 以消息卡片发送, 每个机器人每分钟最多 100 条
+type feishu struct {
+	base string
+}
+
+func (f feishu) defaultBaseUrl() string {
+	return f.base
+}
+
+func (feishu) defaultRateLimit() int {
+	return 100
+}
+
+// request 加签: 以 "秒级时间戳\nsecret" 为密钥对空串做 HMAC-SHA256 后 base64, 放在请求体中
+func (feishu) request(cfg Config, msg Message, now time.Time) (string, interface{}, error) {
+	content := strings.Join(markdownLines(msg), "\n")
+	if msg.Text != "" {
+		content = msg.Text + "\n" + content
+	}
+
+	body := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"template": "red",
+				"title":    map[string]string{"tag": "plain_text", "content": msg.Title},
+			},
+			"elements": []interface{}{
+				map[string]interface{}{
+					"tag":  "div",
+					"text": map[string]string{"tag": "lark_md", "content": content},
+				},
+			},
+		},
+	}
+	if cfg.Secret != "" {
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(timestamp+"\n"+cfg.Secret))
+		body["timestamp"] = timestamp
+		body["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	return cfg.BaseUrl + "/open-apis/bot/v2/hook/" + url.PathEscape(cfg.Token), body, nil
+}
+
+func (feishu) check(content []byte) error {
+	var resp struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(content, &resp); err != nil {
+		return err
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("feishu code %d: %s", resp.Code, resp.Msg)
+	}
+	return nil
+}
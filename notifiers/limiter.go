@@ -0,0 +1,44 @@
+package notifiers
+
+import (
+	"sync"
+	"time"
+)
+
+// limiter 滑动窗口限流, per 时间内最多 limit 次
+type limiter struct {
+	mu    sync.Mutex
+	limit int
+	per   time.Duration
+	times []time.Time
+}
+
+func newLimiter(limit int, per time.Duration) *limiter {
+	return &limiter{limit: limit, per: per}
+}
+
+// wait 预约一个发送时间并等待, 需要等待超过 2 个窗口时放弃
+func (l *limiter) wait() bool {
+	l.mu.Lock()
+
+	now := time.Now()
+	at := now
+	if len(l.times) >= l.limit {
+		if next := l.times[len(l.times)-l.limit].Add(l.per); next.After(now) {
+			at = next
+		}
+	}
+	if at.Sub(now) > 2*l.per {
+		l.mu.Unlock()
+		return false
+	}
+
+	l.times = append(l.times, at)
+	if len(l.times) > l.limit {
+		l.times = l.times[len(l.times)-l.limit:]
+	}
+	l.mu.Unlock()
+
+	time.Sleep(at.Sub(now))
+	return true
+}
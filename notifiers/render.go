@@ -0,0 +1,73 @@
+package notifiers
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// 标题中的方括号会破坏 Markdown 链接
+var markdownEscaper = strings.NewReplacer("[", "【", "]", "】", "\n", " ")
+
+func itemPos(k int, v globals.GblRespData) int {
+	if v.Pos > 0 {
+		return v.Pos
+	}
+	return k + 1
+}
+
+func itemPlatform(v globals.GblRespData) string {
+	if v.Platform == "" {
+		return ""
+	}
+	return "[" + v.Platform + "] "
+}
+
+// markdownLines 每个条目一行: 1. [weibo] [标题](链接) 🔥热度
+func markdownLines(msg Message) []string {
+	var result []string
+	for k, v := range msg.Items {
+		title := markdownEscaper.Replace(v.Title)
+		if v.ToUrl != "" {
+			title = "[" + title + "](" + v.ToUrl + ")"
+		}
+		line := fmt.Sprintf("%d. %s%s", itemPos(k, v), markdownEscaper.Replace(itemPlatform(v)), title)
+		if v.HotVal != "" {
+			line += " 🔥" + v.HotVal
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// markdown 标题 + 说明 + 条目列表, 钉钉、企业微信共用
+func markdown(msg Message) string {
+	lines := []string{"### " + msg.Title}
+	if msg.Text != "" {
+		lines = append(lines, msg.Text)
+	}
+	lines = append(lines, markdownLines(msg)...)
+	return strings.Join(lines, "\n\n")
+}
+
+// htmlText Telegram 的 HTML 格式
+func htmlText(msg Message) string {
+	lines := []string{"<b>" + html.EscapeString(msg.Title) + "</b>"}
+	if msg.Text != "" {
+		lines = append(lines, html.EscapeString(msg.Text))
+	}
+	for k, v := range msg.Items {
+		title := html.EscapeString(v.Title)
+		if v.ToUrl != "" {
+			title = `<a href="` + html.EscapeString(v.ToUrl) + `">` + title + "</a>"
+		}
+		line := fmt.Sprintf("%d. %s%s", itemPos(k, v), html.EscapeString(itemPlatform(v)), title)
+		if v.HotVal != "" {
+			line += " 🔥" + html.EscapeString(v.HotVal)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,46 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// telegram Bot API sendMessage, 同一个群每分钟最多 20 条
+type telegram struct{}
+
+func (telegram) defaultBaseUrl() string {
+	return "https://api.telegram.org"
+}
+
+func (telegram) defaultRateLimit() int {
+	return 20
+}
+
+func (telegram) request(cfg Config, msg Message, now time.Time) (string, interface{}, error) {
+	body := map[string]interface{}{
+		"chat_id":                  cfg.ChatId,
+		"text":                     htmlText(msg),
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}
+	return cfg.BaseUrl + "/bot" + cfg.Token + "/sendMessage", body, nil
+}
+
+func (telegram) check(content []byte) error {
+	var resp struct {
+		Ok          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(content, &resp); err != nil {
+		return err
+	}
+	if !resp.Ok {
+		if resp.Description == "" {
+			return errors.New("telegram request failed")
+		}
+		return fmt.Errorf("telegram: %s", resp.Description)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// weCom 企业微信群机器人, key 即鉴权, 每个机器人每分钟最多 20 条
+type weCom struct{}
+
+func (weCom) defaultBaseUrl() string {
+	return "https://qyapi.weixin.qq.com"
+}
+
+func (weCom) defaultRateLimit() int {
+	return 20
+}
+
+func (weCom) request(cfg Config, msg Message, now time.Time) (string, interface{}, error) {
+	body := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"content": markdown(msg),
+		},
+	}
+	return cfg.BaseUrl + "/cgi-bin/webhook/send?key=" + url.QueryEscape(cfg.Token), body, nil
+}
+
+func (weCom) check(content []byte) error {
+	var resp struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(content, &resp); err != nil {
+		return err
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("wecom errcode %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema OpenAPI 3.0 的 Schema Object, 只包含用到的字段
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// String 字符串参数, 可以限定取值
+func String(enum ...string) *Schema {
+	return &Schema{Type: "string", Enum: enum}
+}
+
+func Integer() *Schema {
+	return &Schema{Type: "integer"}
+}
+
+func Boolean() *Schema {
+	return &Schema{Type: "boolean"}
+}
+
+// List 逗号分隔的列表参数, 配合 Parameter 的 Style form、Explode false 使用
+func List(enum ...string) *Schema {
+	return &Schema{Type: "array", Items: String(enum...)}
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas 由 Go 类型生成的 components.schemas, 具名结构体只生成一次并以 $ref 引用
+type schemas struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{defs: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// of 按 encoding/json 的规则生成 t 的 Schema
+// 没有 omitempty 的字段总会输出, 标记为 required; 字段的 enum 标签为逗号分隔的取值, 切片时作用于元素
+func (s *schemas) of(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.of(t.Elem())
+		if schema.Ref != "" {
+			// $ref 不能带其他字段, 用 allOf 包一层
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		// nil 切片输出为 null
+		return &Schema{Type: "array", Items: s.of(t.Elem()), Nullable: true}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.define(t)}
+	}
+	// interface{} 等任意值
+	return &Schema{}
+}
+
+// define 登记具名结构体, 不同包的同名类型加上包名区分
+func (s *schemas) define(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := s.defs[name]; taken {
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	s.names[t] = name
+	// 先占位, 自引用的类型不会无限递归
+	s.defs[name] = &Schema{}
+	*s.defs[name] = *s.object(t)
+	return name
+}
+
+func (s *schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.fields(t, schema)
+	return schema
+}
+
+func (s *schemas) fields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// 嵌入的结构体字段提升到外层
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.fields(ft, schema)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		field := s.of(f.Type)
+		if enum := f.Tag.Get("enum"); enum != "" {
+			values := strings.Split(enum, ",")
+			if field.Type == "array" && field.Items != nil {
+				field.Items.Enum = values
+			} else {
+				field.Enum = values
+			}
+		}
+		schema.Properties[name] = field
+
+		if !strings.Contains(opts, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
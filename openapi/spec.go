@@ -0,0 +1,294 @@
+// Package openapi 由 gin 的路由表和响应类型生成 OpenAPI 3 文档
+// 参数、说明等路由表中没有的信息由调用方以 Route 提供, 响应结构由 Go 类型反射生成
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Tags       []Tag                            `json:"tags,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	Name   string `json:"name,omitempty"`
+	In     string `json:"in,omitempty"`
+}
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationId string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Query 查询参数, List 类型的参数按逗号分隔
+func Query(name, desc string, schema *Schema) Parameter {
+	p := Parameter{Name: name, In: "query", Description: desc, Schema: schema}
+	if schema.Type == "array" {
+		explode := false
+		p.Style, p.Explode = "form", &explode
+	}
+	return p
+}
+
+// Required 必填的查询参数
+func Required(name, desc string, schema *Schema) Parameter {
+	p := Query(name, desc, schema)
+	p.Required = true
+	return p
+}
+
+// Path 路径参数, 未在 Route 中声明的路径参数按字符串生成
+func Path(name, desc string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "path", Description: desc, Required: true, Schema: schema}
+}
+
+// 接口的认证要求
+const (
+	AuthNone  = iota
+	AuthKey   // API Key, KeyOptional 时可以不带
+	AuthAdmin // ADMIN_TOKEN 或 admin 权限的 API Key
+)
+
+// Route 一个路由的文档
+type Route struct {
+	Tag         string
+	Summary     string
+	Description string
+	Params      []Parameter
+	// 请求体和 JSON 响应的零值, 如 globals.GblResp{}; Resp 为空时按 GblResp 生成
+	Body interface{}
+	Resp interface{}
+	// JSON 之外的响应类型, 如 text/csv, 内容按字符串描述
+	Alt []string
+	// 升级为 WebSocket 的接口, 响应为 101
+	Upgrade bool
+	Auth    int
+	// 不出现在文档中, 如文档本身
+	Hidden bool
+}
+
+// Config 文档的基本信息
+type Config struct {
+	Info Info
+	Tags []Tag
+	// 默认的响应类型, 用于没有声明 Resp 的路由
+	DefaultResp interface{}
+	// 未开启 API_KEY_REQUIRED 时 API Key 可以不带
+	KeyOptional bool
+	// 只在 SSE、WebSocket 中出现的类型, 同样生成到 components.schemas
+	Extra []interface{}
+}
+
+// 认证方式与 middlewares.RequestKey 一致
+var securitySchemes = map[string]SecurityScheme{
+	"ApiKeyHeader": {Type: "apiKey", Name: "X-API-Key", In: "header"},
+	"ApiKeyQuery":  {Type: "apiKey", Name: "api_key", In: "query"},
+	"BearerAuth":   {Type: "http", Scheme: "bearer"},
+}
+
+var pathParamRex = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// Build routes 为 gin.Engine.Routes(), docs 的 key 为 "GET /api/hot/:platform/history" 这样的 "方法 路径"
+// 没有文档的路由同样生成, 只有路径参数和默认响应, 保证新增的路由不会遗漏
+func Build(cfg Config, routes gin.RoutesInfo, docs map[string]Route) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    cfg.Info,
+		Tags:    cfg.Tags,
+		Paths:   map[string]map[string]*Operation{},
+	}
+	s := newSchemas()
+	ids := map[string]bool{}
+
+	for _, ri := range routes {
+		route := docs[ri.Method+" "+ri.Path]
+		if route.Hidden {
+			continue
+		}
+
+		op := &Operation{
+			Summary:     route.Summary,
+			Description: route.Description,
+			OperationId: operationId(ri, ids),
+			Parameters:  route.Params,
+			Responses:   map[string]Response{},
+			Security:    security(route.Auth, cfg.KeyOptional),
+		}
+		if route.Tag != "" {
+			op.Tags = []string{route.Tag}
+		}
+
+		// 补上未声明的路径参数
+		for _, m := range pathParamRex.FindAllStringSubmatch(ri.Path, -1) {
+			if !hasParam(op.Parameters, m[1], "path") {
+				op.Parameters = append(op.Parameters, Path(m[1], "", String()))
+			}
+		}
+
+		if route.Body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: s.of(reflect.TypeOf(route.Body))}},
+			}
+		}
+
+		switch {
+		case route.Upgrade:
+			op.Responses["101"] = Response{Description: "Switching Protocols"}
+		default:
+			content := map[string]MediaType{}
+			resp := route.Resp
+			if resp == nil && len(route.Alt) == 0 {
+				resp = cfg.DefaultResp
+			}
+			if resp != nil {
+				content["application/json"] = MediaType{Schema: s.of(reflect.TypeOf(resp))}
+			}
+			for _, ct := range route.Alt {
+				content[ct] = MediaType{Schema: String()}
+			}
+			// 业务错误同样是 200, 以 code 区分
+			op.Responses["200"] = Response{Description: "code 为 0 表示成功, 否则 err 为错误信息", Content: content}
+		}
+		if route.Auth != AuthNone {
+			op.Responses["401"] = Response{Description: "API Key 无效"}
+		}
+
+		p := pathParamRex.ReplaceAllString(ri.Path, "{$1}")
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = map[string]*Operation{}
+		}
+		doc.Paths[p][strings.ToLower(ri.Method)] = op
+	}
+
+	for _, v := range cfg.Extra {
+		s.of(reflect.TypeOf(v))
+	}
+	doc.Components.Schemas = s.defs
+	doc.Components.SecuritySchemes = securitySchemes
+	return doc
+}
+
+func hasParam(params []Parameter, name, in string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+func security(auth int, keyOptional bool) []map[string][]string {
+	if auth == AuthNone {
+		return nil
+	}
+	result := []map[string][]string{{"ApiKeyHeader": {}}, {"ApiKeyQuery": {}}, {"BearerAuth": {}}}
+	if auth == AuthKey && keyOptional {
+		// 空对象表示不认证也可以
+		result = append(result, map[string][]string{})
+	}
+	return result
+}
+
+// operationId 优先使用处理函数名, 如 api.BiliHot -> biliHot, 生成客户端代码时作为方法名
+// 闭包或重名时按方法和路径生成, 如 GET /api/hot/:platform/diff -> getApiHotPlatformDiff
+func operationId(ri gin.RouteInfo, ids map[string]bool) string {
+	name := ri.Handler[strings.LastIndex(ri.Handler, ".")+1:]
+	id := ""
+	if isIdent(name) && !strings.Contains(ri.Handler, ".func") {
+		id = string(unicode.ToLower(rune(name[0]))) + name[1:]
+	}
+	if id == "" || ids[id] {
+		var b strings.Builder
+		b.WriteString(strings.ToLower(ri.Method))
+		for _, part := range strings.FieldsFunc(ri.Path, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+		id = b.String()
+	}
+	for n := 2; ids[id]; n++ {
+		id = fmt.Sprintf("%s%d", strings.TrimRight(id, "0123456789"), n)
+	}
+	ids[id] = true
+	return id
+}
+
+func isIdent(s string) bool {
+	if s == "" || !unicode.IsLetter(rune(s[0])) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// Handler 输出 JSON 文档, 每次请求时重新生成, 翻译语言等重新加载后文档随之更新
+func Handler(build func() *Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, build())
+	}
+}
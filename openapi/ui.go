@@ -0,0 +1,36 @@
+package openapi
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Swagger UI 从 CDN 加载, 服务端不需要打包静态文件
+var uiPage = template.Must(template.New("ui").Parse(`<!DOCTYPE html>
+<html lang="zh">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+<script>
+window.ui = SwaggerUIBundle({url: {{.SpecUrl}}, dom_id: "#swagger-ui", deepLinking: true, persistAuthorization: true});
+</script>
+</body>
+</html>
+`))
+
+// UI 浏览文档和调试接口的页面, specUrl 为 JSON 文档的地址
+func UI(title, specUrl string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		uiPage.Execute(c.Writer, map[string]string{"Title": title, "SpecUrl": specUrl})
+	}
+}
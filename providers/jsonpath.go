@@ -0,0 +1,133 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// step JSONPath 中的一步: 取键、取下标或 [*] 展开
+type step struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parsePath 解析 JSONPath 子集: $.a.b、a.b[0].c、a['b-c']、a[*].b、a[-1], $ 可省略
+func parsePath(path string) ([]step, error) {
+	path = strings.TrimSpace(path)
+	if strings.Contains(path, "..") {
+		return nil, fmt.Errorf("invalid path %q: recursive descent is not supported", path)
+	}
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+
+	var result []step
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", path)
+			}
+			inner := strings.TrimSpace(path[i+1 : i+end])
+			i += end + 1
+
+			switch {
+			case inner == "*":
+				result = append(result, step{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				result = append(result, step{key: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: bad index %s", path, inner)
+				}
+				result = append(result, step{index: n, isIndex: true})
+			}
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			key := path[i : i+end]
+			i += end
+			if key == "*" {
+				result = append(result, step{wildcard: true})
+			} else {
+				result = append(result, step{key: key})
+			}
+		}
+	}
+	return result, nil
+}
+
+// evalPath 返回路径匹配的全部值, 不存在时为空
+func evalPath(v interface{}, steps []step) []interface{} {
+	current := []interface{}{v}
+	for _, s := range steps {
+		var next []interface{}
+		for _, c := range current {
+			switch {
+			case s.wildcard:
+				switch t := c.(type) {
+				case []interface{}:
+					next = append(next, t...)
+				case map[string]interface{}:
+					for _, mv := range t {
+						next = append(next, mv)
+					}
+				}
+			case s.isIndex:
+				if list, ok := c.([]interface{}); ok {
+					i := s.index
+					if i < 0 {
+						i += len(list)
+					}
+					if i >= 0 && i < len(list) {
+						next = append(next, list[i])
+					}
+				}
+			default:
+				if m, ok := c.(map[string]interface{}); ok {
+					if mv, found := m[s.key]; found {
+						next = append(next, mv)
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// evalList 条目列表: 路径匹配到一个数组时返回数组元素, 否则返回全部匹配值
+func evalList(v interface{}, steps []step) []interface{} {
+	result := evalPath(v, steps)
+	if len(result) == 1 {
+		if list, ok := result[0].([]interface{}); ok {
+			return list
+		}
+	}
+	return result
+}
+
+// stringify JSON 值转为字符串, 对象和数组输出紧凑 JSON
+func stringify(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,565 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/upstream"
+	"github.com/turbo-uid/hots/utils"
+	"golang.org/x/net/html/charset"
+	"gopkg.in/yaml.v3"
+)
+
+// 响应类型
+const (
+	TypeJSON = "json"
+	TypeHTML = "html"
+)
+
+// Spec 一个声明式榜单, 对应 YAML 中的一个文档
+type Spec struct {
+	// 路由名, 即 /api/hot/<name>
+	Name string `yaml:"name"`
+	// 缓存和快照标识, 默认与 Name 相同
+	Flag     string  `yaml:"flag"`
+	Category string  `yaml:"category"`
+	Type     string  `yaml:"type"`
+	Request  Request `yaml:"request"`
+	// 条目列表: json 为 JSONPath, html 为 CSS 选择器
+	Items string `yaml:"items"`
+	// 最多保留的条目数, 0 不限制
+	Limit  int              `yaml:"limit"`
+	Fields map[string]Field `yaml:"fields"`
+	Extra  map[string]Field `yaml:"extra"`
+	// 字段映射后执行的 expr 脚本, 用于过滤、改写、合并条目, 见 script.go
+	Script string `yaml:"script"`
+}
+
+// Request 上游请求, url、headers、body 为 text/template 模板, 可使用 {{.Vars.x}} 和 {{.Now.Unix}}
+type Request struct {
+	Method  string            `yaml:"method"`
+	Url     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+	Vars    map[string]string `yaml:"vars"`
+	// 非 UTF-8 页面的编码, 如 gbk
+	Charset string `yaml:"charset"`
+	Timeout string `yaml:"timeout"`
+}
+
+// Field 字段映射, 只写字符串时等同于 path
+// 取值顺序: value 常量或 path 取值, 依次执行 transform, 最后套用 template
+// template 中 {value} 为当前值, 其余 {x} 按 path 规则从条目中取值, {x|query} 取值后按查询参数转义
+type Field struct {
+	// json 为相对条目的 JSONPath, html 为相对条目的 CSS 选择器, 为空时取条目本身
+	Path string `yaml:"path"`
+	// html 中读取的属性, 为空时取文本
+	Attr      string   `yaml:"attr"`
+	Value     string   `yaml:"value"`
+	Template  string   `yaml:"template"`
+	Transform []string `yaml:"transform"`
+}
+
+func (f *Field) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		f.Path = node.Value
+		return nil
+	}
+	type plain Field
+	return node.Decode((*plain)(f))
+}
+
+// 可映射的字段, 与 GblRespData 的 json 名一致
+var fieldNames = map[string]bool{
+	"id": true, "title": true, "desc": true, "hot_val": true, "icon": true, "pos": true, "to_url": true,
+	"label": true, "is_top": true, "author": true, "published_at": true, "views": true, "likes": true,
+	"comments": true, "badges": true,
+}
+
+var placeholderRex = regexp.MustCompile(`\{([^{}]+)\}`)
+
+type field struct {
+	name string
+	Field
+	sourced    bool
+	transforms []transform
+}
+
+// Provider 按 Spec 请求上游并转换为统一输出结果
+type Provider struct {
+	Name     string
+	Flag     string
+	Category string
+
+	spec    Spec
+	url     *template.Template
+	body    *template.Template
+	headers map[string]*template.Template
+	paths   map[string][]step
+	fields  []field
+	extra   []field
+	script  *script
+	client  *http.Client
+}
+
+// New 校验并编译 Spec
+func New(spec Spec) (*Provider, error) {
+	if spec.Name == "" || spec.Request.Url == "" || spec.Items == "" {
+		return nil, errors.New("name, request.url and items are required")
+	}
+	if _, ok := spec.Fields["title"]; !ok {
+		return nil, errors.New("fields.title is required")
+	}
+	if spec.Flag == "" {
+		spec.Flag = spec.Name
+	}
+	if spec.Type == "" {
+		spec.Type = TypeJSON
+	}
+	if spec.Type != TypeJSON && spec.Type != TypeHTML {
+		return nil, fmt.Errorf("unknown type: %s", spec.Type)
+	}
+	if spec.Request.Method == "" {
+		spec.Request.Method = http.MethodGet
+	}
+
+	timeout := 10 * time.Second
+	if spec.Request.Timeout != "" {
+		d, err := time.ParseDuration(spec.Request.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %w", err)
+		}
+		timeout = d
+	}
+
+	p := &Provider{
+		Name:     spec.Name,
+		Flag:     spec.Flag,
+		Category: spec.Category,
+		spec:     spec,
+		headers:  map[string]*template.Template{},
+		paths:    map[string][]step{},
+		client:   &http.Client{Timeout: timeout, Transport: upstream.Transport},
+	}
+
+	var err error
+	if p.url, err = template.New("url").Parse(spec.Request.Url); err != nil {
+		return nil, err
+	}
+	if p.body, err = template.New("body").Parse(spec.Request.Body); err != nil {
+		return nil, err
+	}
+	for k, v := range spec.Request.Headers {
+		if p.headers[k], err = template.New(k).Parse(v); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.addPath(spec.Items); err != nil {
+		return nil, err
+	}
+	if p.fields, err = p.compileFields(spec.Fields, true); err != nil {
+		return nil, err
+	}
+	if p.extra, err = p.compileFields(spec.Extra, false); err != nil {
+		return nil, err
+	}
+	if spec.Script != "" {
+		if p.script, err = compileScript(spec.Script); err != nil {
+			return nil, fmt.Errorf("script: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// addPath 预先解析 JSONPath, html 的选择器在使用时解析
+func (p *Provider) addPath(path string) error {
+	if p.spec.Type != TypeJSON {
+		return nil
+	}
+	steps, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	p.paths[path] = steps
+	return nil
+}
+
+func (p *Provider) compileFields(m map[string]Field, known bool) ([]field, error) {
+	var result []field
+	for name, f := range m {
+		if known && !fieldNames[name] {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+
+		cf := field{name: name, Field: f, sourced: f.Value == "" && (f.Path != "" || f.Attr != "" || f.Template == "")}
+		if cf.sourced {
+			if err := p.addPath(f.Path); err != nil {
+				return nil, fmt.Errorf("field %s: %w", name, err)
+			}
+		}
+		for _, m := range placeholderRex.FindAllStringSubmatch(f.Template, -1) {
+			path, _, _ := strings.Cut(m[1], "|")
+			if path == "value" {
+				continue
+			}
+			if err := p.addPath(path); err != nil {
+				return nil, fmt.Errorf("field %s: %w", name, err)
+			}
+		}
+		for _, s := range f.Transform {
+			t, err := parseTransform(s)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", name, err)
+			}
+			cf.transforms = append(cf.transforms, t)
+		}
+		result = append(result, cf)
+	}
+
+	// 按名称排序, 保证每次输出一致
+	sort.Slice(result, func(i, j int) bool { return result[i].name < result[j].name })
+	return result, nil
+}
+
+// Load 读取一个 YAML 文件, 文件中可以用 --- 分隔多个榜单
+func Load(file string) ([]*Provider, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Provider
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	for {
+		var spec Spec
+		err := dec.Decode(&spec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+
+		p, err := New(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: provider %s: %w", file, spec.Name, err)
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// LoadDir 读取目录下全部 .yaml、.yml 文件
+func LoadDir(dir string) ([]*Provider, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	var result []*Provider
+	for _, f := range files {
+		list, err := Load(f)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, list...)
+	}
+	return result, nil
+}
+
+// Fetch 请求上游并映射字段, 符合 Board.Fetch 的签名
+func (p *Provider) Fetch() globals.GblResp {
+	var resultResp globals.GblResp
+
+	body, base, err := p.request()
+	if err != nil {
+		globals.GoLogger.Errorf("PROVIDER %s FETCH ERR %s", p.Name, err.Error())
+		resultResp.Code = 1
+		resultResp.Err = "Failed to fetch data"
+		return resultResp
+	}
+
+	data, err := p.Parse(body, base)
+	if err != nil {
+		globals.GoLogger.Errorf("PROVIDER %s PARSE ERR %s", p.Name, err.Error())
+		resultResp.Code = 1
+		resultResp.Err = "Failed to parse response"
+		return resultResp
+	}
+	if len(data) == 0 {
+		resultResp.Code = 1
+		resultResp.Err = "No items matched"
+		return resultResp
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = data
+	return resultResp
+}
+
+// request 执行请求模板并读取响应, 返回内容和实际请求地址
+func (p *Provider) request() ([]byte, *url.URL, error) {
+	vars := struct {
+		Now  time.Time
+		Vars map[string]string
+	}{time.Now(), p.spec.Request.Vars}
+
+	render := func(t *template.Template) (string, error) {
+		var buf bytes.Buffer
+		err := t.Execute(&buf, vars)
+		return buf.String(), err
+	}
+
+	rawUrl, err := render(p.url)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := render(p.body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(p.spec.Request.Method, rawUrl, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, t := range p.headers {
+		v, err := render(t)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set(k, v)
+	}
+	if body != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("status %s", resp.Status)
+	}
+
+	var r io.Reader = resp.Body
+	if p.spec.Request.Charset != "" {
+		if r, err = charset.NewReaderLabel(p.spec.Request.Charset, resp.Body); err != nil {
+			return nil, nil, err
+		}
+	}
+	content, err := io.ReadAll(io.LimitReader(r, 16<<20))
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, req.URL, nil
+}
+
+// Parse 从响应内容中取出条目并映射字段, base 用于补全相对链接
+func (p *Provider) Parse(content []byte, base *url.URL) ([]globals.GblRespData, error) {
+	var items []func(expr, attr string) []string
+
+	switch p.spec.Type {
+	case TypeJSON:
+		var root interface{}
+		dec := json.NewDecoder(bytes.NewReader(content))
+		dec.UseNumber()
+		if err := dec.Decode(&root); err != nil {
+			return nil, err
+		}
+		for _, item := range evalList(root, p.paths[p.spec.Items]) {
+			item := item
+			items = append(items, func(expr, _ string) []string {
+				var result []string
+				for _, v := range evalPath(item, p.paths[expr]) {
+					result = append(result, stringify(v))
+				}
+				return result
+			})
+		}
+	case TypeHTML:
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		doc.Find(p.spec.Items).Each(func(_ int, s *goquery.Selection) {
+			items = append(items, func(expr, attr string) []string {
+				sel := s
+				if expr != "" {
+					sel = s.Find(expr)
+				}
+				var result []string
+				sel.Each(func(_ int, e *goquery.Selection) {
+					if attr != "" {
+						if v, ok := e.Attr(attr); ok {
+							result = append(result, strings.TrimSpace(v))
+						}
+						return
+					}
+					result = append(result, strings.TrimSpace(e.Text()))
+				})
+				return result
+			})
+		})
+	}
+
+	var result []globals.GblRespData
+	for _, lookup := range items {
+		var v globals.GblRespData
+		for _, f := range p.fields {
+			values := f.resolve(lookup, base)
+			setField(&v, f.name, values)
+		}
+		for _, f := range p.extra {
+			if values := f.resolve(lookup, base); len(values) > 0 && values[0] != "" {
+				v.Extra.Set(f.name, values[0])
+			}
+		}
+		result = append(result, v)
+	}
+
+	if p.script != nil {
+		// 脚本中的原始响应不使用 json.Number, 便于比较和计算
+		var raw interface{}
+		if p.spec.Type == TypeJSON {
+			if err := json.Unmarshal(content, &raw); err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		if result, err = p.script.run(result, raw); err != nil {
+			return nil, fmt.Errorf("script: %w", err)
+		}
+	}
+
+	var data []globals.GblRespData
+	for _, v := range result {
+		if v.Title == "" {
+			continue
+		}
+		if v.Pos == 0 {
+			v.Pos = len(data) + 1
+		}
+
+		data = append(data, v)
+		if p.spec.Limit > 0 && len(data) >= p.spec.Limit {
+			break
+		}
+	}
+	return data, nil
+}
+
+// resolve 计算字段值, 路径匹配多个值时全部返回, 仅 badges 使用多个值
+func (f field) resolve(lookup func(expr, attr string) []string, base *url.URL) []string {
+	values := []string{f.Value}
+	if f.sourced {
+		values = lookup(f.Path, f.Attr)
+		// 路径不存在时按空值处理, default 等转换同样生效
+		if len(values) == 0 {
+			values = []string{""}
+		}
+	}
+
+	for k := range values {
+		for _, t := range f.transforms {
+			values[k] = t(values[k], base)
+		}
+		if f.Template != "" {
+			value := values[k]
+			values[k] = placeholderRex.ReplaceAllStringFunc(f.Template, func(s string) string {
+				path, filter, _ := strings.Cut(s[1:len(s)-1], "|")
+				v := value
+				if path != "value" {
+					if found := lookup(path, ""); len(found) > 0 {
+						v = found[0]
+					} else {
+						v = ""
+					}
+				}
+				if filter == "query" {
+					v = url.QueryEscape(v)
+				}
+				return v
+			})
+		}
+	}
+	return values
+}
+
+func setField(v *globals.GblRespData, name string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	s := values[0]
+	switch name {
+	case "id":
+		v.Id = s
+	case "title":
+		v.Title = s
+	case "desc":
+		v.Desc = s
+	case "hot_val":
+		v.HotVal = s
+	case "icon":
+		v.Icon = s
+	case "pos":
+		v.Pos, _ = strconv.Atoi(s)
+	case "to_url":
+		v.ToUrl = s
+	case "label":
+		v.Lab = s
+	case "is_top":
+		if s == "true" {
+			v.IsTop = 1
+		} else {
+			v.IsTop, _ = strconv.Atoi(s)
+		}
+	case "author":
+		v.Author = s
+	case "published_at":
+		v.PublishedAt = s
+	case "views":
+		v.Views = utils.ParseCount(s)
+	case "likes":
+		v.Likes = utils.ParseCount(s)
+	case "comments":
+		v.Comments = utils.ParseCount(s)
+	case "badges":
+		for _, b := range values {
+			if b != "" {
+				v.Badges = append(v.Badges, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package providers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/upstream"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func newProvider(t *testing.T, spec Spec) *Provider {
+	t.Helper()
+	if spec.Name == "" {
+		spec.Name = "test"
+	}
+	if spec.Request.Url == "" {
+		spec.Request.Url = "https://example.com/hot/list"
+	}
+	p, err := New(spec)
+	if err != nil {
+		t.Fatalf("new: %s", err)
+	}
+	return p
+}
+
+func toJSON(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+const jsonBody = `{"data":{"list":[
+	{"id":101,"name":"第一 条","heat":"1.2万","ts":1700000000,"tags":["热","新"],"meta":{"a-b":"x"},"url":"/item/101"},
+	{"id":102,"name":"第二","heat":"3400","ts":1700000000000,"tags":[],"top":true},
+	{"id":103,"heat":"1"},
+	{"id":104,"name":"第四","heat":"8"}
+]}}`
+
+const htmlBody = `<html><body><ul class="list">
+	<li><span class="idx">1</span><a href="/p/1" title="t1"> 标题一 </a><em class="hot">热度 12345</em><img src="//img.example.com/1.png"></li>
+	<li><span class="idx"></span><a href="https://other.com/p/2">标题二</a><em class="hot">热度 99</em><i class="badge">新</i><i class="badge">爆</i></li>
+	<li><a href="/p/3"></a></li>
+</ul></body></html>`
+
+func TestParse(t *testing.T) {
+	base, _ := url.Parse("https://example.com/hot/list")
+	cases := []struct {
+		name    string
+		spec    Spec
+		content string
+		want    []globals.GblRespData
+	}{
+		{
+			name: "json",
+			spec: Spec{
+				Items: "$.data.list",
+				Fields: map[string]Field{
+					"id":           {Path: "id"},
+					"title":        {Path: "name", Transform: []string{"replace: =>"}},
+					"hot_val":      {Path: "heat", Transform: []string{"count"}},
+					"published_at": {Path: "ts", Transform: []string{"unix"}},
+					"is_top":       {Path: "top"},
+					"badges":       {Path: "tags[*]"},
+					"to_url":       {Template: "https://example.com/s?q={name|query}&id={id}"},
+					"label":        {Path: "tags[-1]", Transform: []string{"default:无"}},
+				},
+				Extra: map[string]Field{"meta": {Path: "meta['a-b']"}, "link": {Path: "url", Transform: []string{"abs_url"}}},
+			},
+			content: jsonBody,
+			want: []globals.GblRespData{
+				{Id: "101", Title: "第一条", HotVal: "12000", PublishedAt: "2023-11-14T22:13:20Z", Badges: []string{"热", "新"}, Pos: 1,
+					ToUrl: "https://example.com/s?q=%E7%AC%AC%E4%B8%80+%E6%9D%A1&id=101", Lab: "新",
+					Extra: globals.GblExtra{"meta": "x", "link": "https://example.com/item/101"}},
+				{Id: "102", Title: "第二", HotVal: "3400", PublishedAt: "2023-11-14T22:13:20Z", IsTop: 1, Pos: 2,
+					ToUrl: "https://example.com/s?q=%E7%AC%AC%E4%BA%8C&id=102", Lab: "无"},
+				// 没有标题的条目被跳过, 排名按输出顺序
+				{Id: "104", Title: "第四", HotVal: "8", Pos: 3, ToUrl: "https://example.com/s?q=%E7%AC%AC%E5%9B%9B&id=104", Lab: "无"},
+			},
+		},
+		{
+			name: "json limit and constant",
+			spec: Spec{
+				Items:  "data.list[*]",
+				Limit:  1,
+				Fields: map[string]Field{"title": {Path: "name"}, "label": {Value: "热"}, "desc": {Template: "热度 {heat}"}},
+			},
+			content: jsonBody,
+			want:    []globals.GblRespData{{Title: "第一 条", Lab: "热", Desc: "热度 1.2万", Pos: 1}},
+		},
+		{
+			name: "html",
+			spec: Spec{
+				Type:  TypeHTML,
+				Items: "ul.list li",
+				Fields: map[string]Field{
+					"title":   {Path: "a"},
+					"pos":     {Path: ".idx", Transform: []string{"int"}},
+					"hot_val": {Path: ".hot", Transform: []string{`regex:热度 (\d+)`}},
+					"to_url":  {Path: "a", Attr: "href", Transform: []string{"abs_url"}},
+					"icon":    {Path: "img", Attr: "src", Transform: []string{"abs_url"}},
+					"badges":  {Path: ".badge"},
+					"desc":    {Path: "a", Attr: "title", Transform: []string{"prefix:<", "suffix:>"}},
+				},
+			},
+			content: htmlBody,
+			want: []globals.GblRespData{
+				{Title: "标题一", Pos: 1, HotVal: "12345", ToUrl: "https://example.com/p/1", Icon: "https://img.example.com/1.png", Desc: "<t1>"},
+				{Title: "标题二", Pos: 2, HotVal: "99", ToUrl: "https://other.com/p/2", Badges: []string{"新", "爆"}, Desc: "<>"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newProvider(t, tc.spec)
+			got, err := p.Parse([]byte(tc.content), base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if toJSON(got) != toJSON(tc.want) {
+				t.Errorf("got  %s\nwant %s", toJSON(got), toJSON(tc.want))
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	var root interface{}
+	json.Unmarshal([]byte(`{"a":{"b-c":[{"d":1},{"d":2},{"d":3}]},"e":{"x":"1","y":"2"}}`), &root)
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"$.a['b-c'][0].d", `[1]`},
+		{`a["b-c"][-1].d`, `[3]`},
+		{"a.b-c[1]", `[{"d":2}]`},
+		{"$.a['b-c'][*].d", `[1,2,3]`},
+		{"a['b-c'].*.d", `[1,2,3]`},
+		{"a['b-c'][5]", `null`},
+		{"$", `[{"a":{"b-c":[{"d":1},{"d":2},{"d":3}]},"e":{"x":"1","y":"2"}}]`},
+	}
+	for _, tc := range cases {
+		steps, err := parsePath(tc.path)
+		if err != nil {
+			t.Errorf("%s: %s", tc.path, err)
+			continue
+		}
+		if got := toJSON(evalPath(root, steps)); got != tc.want {
+			t.Errorf("%s = %s, want %s", tc.path, got, tc.want)
+		}
+	}
+
+	for _, path := range []string{"a..b", "a[0", "a[x]"} {
+		if _, err := parsePath(path); err == nil {
+			t.Errorf("%s: want error", path)
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	base, _ := url.Parse("https://example.com/a/b")
+	cases := []struct {
+		transform, in, want string
+	}{
+		{"trim", "  x ", "x"},
+		{"remove:#", "#话题#", "话题"},
+		{"replace:a=>b", "aaa", "bbb"},
+		{`regex:(\d+)万`, "约 12万 热度", "12"},
+		{`regex:\d+`, "第 3 名", "3"},
+		{`regex:\d+`, "无", ""},
+		{"default:0", "", "0"},
+		{"count", "1.5亿", "150000000"},
+		{"int", "No.12", "12"},
+		{"unix", "abc", "abc"},
+		{"abs_url", "../c?x=1", "https://example.com/c?x=1"},
+		{"abs_url", "", ""},
+		{"query", "a b&c", "a+b%26c"},
+	}
+	for _, tc := range cases {
+		fn, err := parseTransform(tc.transform)
+		if err != nil {
+			t.Errorf("%s: %s", tc.transform, err)
+			continue
+		}
+		if got := fn(tc.in, base); got != tc.want {
+			t.Errorf("%s(%q) = %q, want %q", tc.transform, tc.in, got, tc.want)
+		}
+	}
+
+	for _, s := range []string{"upper", "replace:ab", "regex:("} {
+		if _, err := parseTransform(s); err == nil {
+			t.Errorf("%s: want error", s)
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	title := map[string]Field{"title": {Path: "t"}}
+	for name, spec := range map[string]Spec{
+		"no items":      {Name: "x", Request: Request{Url: "https://example.com"}, Fields: title},
+		"no title":      {Name: "x", Request: Request{Url: "https://example.com"}, Items: "list", Fields: map[string]Field{"desc": {Path: "d"}}},
+		"unknown field": {Name: "x", Request: Request{Url: "https://example.com"}, Items: "list", Fields: map[string]Field{"title": {Path: "t"}, "rank": {Path: "r"}}},
+		"unknown type":  {Name: "x", Type: "xml", Request: Request{Url: "https://example.com"}, Items: "list", Fields: title},
+		"bad path":      {Name: "x", Request: Request{Url: "https://example.com"}, Items: "list[", Fields: title},
+		"bad template":  {Name: "x", Request: Request{Url: "https://example.com/{{.Vars"}, Items: "list", Fields: title},
+		"bad timeout":   {Name: "x", Request: Request{Url: "https://example.com", Timeout: "soon"}, Items: "list", Fields: title},
+	} {
+		if _, err := New(spec); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+}
+
+// TestFetchCharset 按 charset 解码 GBK 页面, 请求模板可使用变量
+func TestFetchCharset(t *testing.T) {
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+
+	page, _ := simplifiedchinese.GBK.NewEncoder().String(`<ul><li><a href="/n/1">中文标题</a></li></ul>`)
+	var gotPath, gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotHeader = r.URL.RequestURI(), r.Header.Get("X-Token")
+		w.Header().Set("Content-Type", "text/html; charset=gbk")
+		io.WriteString(w, page)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := newProvider(t, Spec{
+		Type:    TypeHTML,
+		Request: Request{Url: srv.URL + "/list?page={{.Vars.page}}", Headers: map[string]string{"X-Token": "t-{{.Vars.page}}"}, Vars: map[string]string{"page": "2"}, Charset: "gbk"},
+		Items:   "li",
+		Fields:  map[string]Field{"title": {Path: "a"}, "to_url": {Path: "a", Attr: "href", Transform: []string{"abs_url"}}},
+	})
+	resultResp := p.Fetch()
+	if resultResp.Code != 0 || len(resultResp.Data) != 1 || resultResp.Data[0].Title != "中文标题" || resultResp.Data[0].ToUrl != srv.URL+"/n/1" {
+		t.Errorf("got %+v", resultResp)
+	}
+	if gotPath != "/list?page=2" || gotHeader != "t-2" {
+		t.Errorf("request %s, X-Token %q", gotPath, gotHeader)
+	}
+}
+
+// TestExamples examples/providers 中的榜单都能加载; 有录制响应的回放后条目与内置榜单的 golden 输出一致
+func TestExamples(t *testing.T) {
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+
+	list, err := LoadDir(filepath.Join("..", "examples", "providers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) == 0 {
+		t.Fatal("no example providers")
+	}
+
+	root := filepath.Join("..", "testdata")
+	recorder, err := upstream.NewRecorder(upstream.ModeReplay, filepath.Join(root, "fixtures"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream.Use(recorder)
+	t.Cleanup(func() { upstream.Use(http.DefaultTransport) })
+
+	// 示例对应的内置榜单
+	golden := map[string]string{"weibo-realtime": "weibo", "zhihu-billboard": "zhihu"}
+	for _, p := range list {
+		t.Run(p.Name, func(t *testing.T) {
+			resultResp := p.Fetch()
+			if missing := recorder.TakeMissing(); len(missing) > 0 {
+				if _, ok := golden[p.Name]; ok {
+					t.Fatalf("no fixture for %s", strings.Join(missing, ", "))
+				}
+				t.Skipf("no fixture for %s", strings.Join(missing, ", "))
+			}
+			if resultResp.Code != 0 || len(resultResp.Data) == 0 {
+				t.Fatalf("code = %d, err = %q", resultResp.Code, resultResp.Err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(root, "golden", golden[p.Name]+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want globals.GblResp
+			if err := json.Unmarshal(content, &want); err != nil {
+				t.Fatal(err)
+			}
+			// 字段映射与内置榜单不完全相同, 条目和顺序需一致
+			var got, titles []string
+			for _, v := range resultResp.Data {
+				got = append(got, v.Title)
+				if v.Pos <= 0 {
+					t.Errorf("%s: pos = %d", v.Title, v.Pos)
+				}
+			}
+			for _, v := range want.Data {
+				titles = append(titles, v.Title)
+			}
+			if strings.Join(got, "\n") != strings.Join(titles, "\n") {
+				t.Errorf("titles = %q\nwant %q", got, titles)
+			}
+		})
+	}
+}
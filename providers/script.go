@@ -0,0 +1,165 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/vm"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// script 条目后处理脚本, 使用 expr 表达式, 返回处理后的条目列表
+//
+// 可用变量: items 字段映射后的条目, key 与输出 JSON 一致; raw json 类型榜单的原始响应, html 为 nil
+// 除 expr 内置函数外提供:
+//
+//	set(item, "key", value, ...)  返回设置了若干字段的副本
+//	merge(a, b)                   返回 a 被 b 覆盖后的副本
+//	query(s)                      按查询参数转义
+type script struct {
+	program *vm.Program
+}
+
+// scriptEnv 脚本可用的变量, 用结构体声明类型, raw 为 nil 时编译期也不会报错
+type scriptEnv struct {
+	Items []any  `expr:"items"`
+	Raw   any    `expr:"raw"`
+	Guard *guard `expr:"__guard"`
+}
+
+// guard 一次执行中循环体的执行次数和截止时间
+// expr 无法从外部中断, 编译时在每个循环体中插入 __tick 检查, 超出时脚本自行报错结束
+type guard struct {
+	steps    int
+	deadline time.Time
+}
+
+func (g *guard) tick() error {
+	g.steps++
+	if g.steps > globals.ScriptMaxSteps {
+		return errors.New("script exceeded step limit")
+	}
+	// 每 256 次检查一次时间, 减少系统调用
+	if g.steps%256 == 0 && time.Now().After(g.deadline) {
+		return errors.New("script timed out")
+	}
+	return nil
+}
+
+// loopGuard 将 map、filter 等的循环体 x 改写为 __tick(__guard, x)
+type loopGuard struct{}
+
+func (loopGuard) Visit(node *ast.Node) {
+	if p, ok := (*node).(*ast.PredicateNode); ok {
+		p.Node = &ast.CallNode{
+			Callee:    &ast.IdentifierNode{Value: "__tick"},
+			Arguments: []ast.Node{&ast.IdentifierNode{Value: "__guard"}, p.Node},
+		}
+	}
+}
+
+var scriptFunctions = []expr.Option{
+	expr.Function("set", func(params ...any) (any, error) {
+		if len(params)%2 != 1 {
+			return nil, errors.New("set(item, key, value, ...) needs key value pairs")
+		}
+		result := copyMap(params[0])
+		for i := 1; i < len(params); i += 2 {
+			key, ok := params[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("set: key must be a string, got %T", params[i])
+			}
+			result[key] = params[i+1]
+		}
+		return result, nil
+	}),
+	expr.Function("merge", func(params ...any) (any, error) {
+		result := map[string]any{}
+		for _, p := range params {
+			for k, v := range copyMap(p) {
+				result[k] = v
+			}
+		}
+		return result, nil
+	}),
+	expr.Function("query", func(params ...any) (any, error) {
+		if len(params) != 1 {
+			return nil, errors.New("query(s) needs one argument")
+		}
+		return url.QueryEscape(fmt.Sprint(params[0])), nil
+	}, new(func(any) string)),
+	expr.Function("__tick", func(params ...any) (any, error) {
+		g, _ := params[0].(*guard)
+		if g == nil {
+			return nil, errors.New("__tick is reserved")
+		}
+		if err := g.tick(); err != nil {
+			return nil, err
+		}
+		return params[1], nil
+	}, new(func(*guard, any) any)),
+}
+
+func copyMap(v any) map[string]any {
+	result := map[string]any{}
+	if m, ok := v.(map[string]any); ok {
+		for k, mv := range m {
+			result[k] = mv
+		}
+	}
+	return result
+}
+
+func compileScript(source string) (*script, error) {
+	options := append([]expr.Option{
+		expr.Env(scriptEnv{}),
+		expr.MaxNodes(globals.ScriptMaxNodes),
+		// repeat 分配的字符串不计入内存预算
+		expr.DisableBuiltin("repeat"),
+		expr.Patch(loopGuard{}),
+	}, scriptFunctions...)
+
+	program, err := expr.Compile(source, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &script{program: program}, nil
+}
+
+// run 执行脚本, 内存用量受 ScriptMemoryBudget 限制, 循环体执行次数受 ScriptMaxSteps 限制
+// 超过 ScriptTimeout 时在下一次循环中报错结束
+func (s *script) run(items []globals.GblRespData, raw interface{}) ([]globals.GblRespData, error) {
+	content, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var list []any
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, err
+	}
+
+	machine := vm.VM{MemoryBudget: globals.ScriptMemoryBudget}
+	env := scriptEnv{Items: list, Raw: raw, Guard: &guard{deadline: time.Now().Add(globals.ScriptTimeout)}}
+	value, err := machine.Run(s.program, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := value.([]any); !ok {
+		return nil, fmt.Errorf("script must return a list of items, got %T", value)
+	}
+	content, err = json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result []globals.GblRespData
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("script returned invalid items: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package providers
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+var scriptItems = []globals.GblRespData{
+	{Title: "置顶", ToUrl: "https://example.com/0"},
+	{Title: "第一", Pos: 1, HotVal: "300"},
+	{Title: "第二", Pos: 2, HotVal: "100"},
+}
+
+func runScript(t *testing.T, source string, raw interface{}) ([]globals.GblRespData, error) {
+	t.Helper()
+	s, err := compileScript(source)
+	if err != nil {
+		t.Fatalf("compile %q: %s", source, err)
+	}
+	return s.run(scriptItems, raw)
+}
+
+func TestScript(t *testing.T) {
+	cases := []struct {
+		source string
+		want   []string
+	}{
+		{`map(items, #index == 0 && .pos == 0 ? set(#, "pos", 999, "is_top", 1) : #)`, []string{"置顶:999", "第一:1", "第二:2"}},
+		{`filter(items, .pos > 0 && int(.hot_val) > 200)`, []string{"第一:1"}},
+		{`sortBy(filter(items, .pos > 0), .pos, "desc")`, []string{"第二:2", "第一:1"}},
+		{`map(items, merge(#, {"title": .title + query("a b")}))`, []string{"置顶a+b:0", "第一a+b:1", "第二a+b:2"}},
+	}
+	for _, tc := range cases {
+		got, err := runScript(t, tc.source, nil)
+		if err != nil {
+			t.Errorf("%s: %s", tc.source, err)
+			continue
+		}
+		var titles []string
+		for _, v := range got {
+			titles = append(titles, v.Title+":"+strconv.Itoa(v.Pos))
+		}
+		if strings.Join(titles, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s: got %q, want %q", tc.source, titles, tc.want)
+		}
+	}
+}
+
+// 遍历 raw 的嵌套循环不分配内存, 只靠内存预算无法结束
+const slowScript = `filter(items, all(raw, all(raw, all(raw, # != nil))))`
+
+func slowRaw() []any {
+	raw := make([]any, 1000)
+	for k := range raw {
+		raw[k] = k
+	}
+	return raw
+}
+
+func TestScriptStepLimit(t *testing.T) {
+	// 放宽超时, 开启 -race 等较慢的环境下同样先达到步数上限
+	steps, timeout := globals.ScriptMaxSteps, globals.ScriptTimeout
+	globals.ScriptMaxSteps, globals.ScriptTimeout = 1e5, time.Minute
+	t.Cleanup(func() { globals.ScriptMaxSteps, globals.ScriptTimeout = steps, timeout })
+
+	start := time.Now()
+	_, err := runScript(t, slowScript, slowRaw())
+	if err == nil || !strings.Contains(err.Error(), "step limit") {
+		t.Fatalf("err = %v, want step limit", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("stopped after %s", d)
+	}
+}
+
+func TestScriptTimeout(t *testing.T) {
+	steps, timeout := globals.ScriptMaxSteps, globals.ScriptTimeout
+	globals.ScriptMaxSteps, globals.ScriptTimeout = 1e12, 20*time.Millisecond
+	t.Cleanup(func() { globals.ScriptMaxSteps, globals.ScriptTimeout = steps, timeout })
+
+	start := time.Now()
+	_, err := runScript(t, slowScript, slowRaw())
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("err = %v, want timed out", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("stopped after %s", d)
+	}
+}
+
+func TestScriptReservedTick(t *testing.T) {
+	if _, err := runScript(t, `__tick(nil, items)`, nil); err == nil {
+		t.Error("calling __tick without the guard succeeded")
+	}
+}
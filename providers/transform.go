@@ -0,0 +1,107 @@
+package providers
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/turbo-uid/hots/utils"
+)
+
+// transform 字段值转换, base 为本次请求的地址, 用于补全相对链接
+type transform func(v string, base *url.URL) string
+
+// parseTransform 解析一个转换, 形如 name 或 name:参数
+//
+//	trim                 去掉首尾空白
+//	remove:#             删除字符
+//	replace:旧=>新       替换
+//	prefix:x / suffix:x  加前缀、后缀
+//	regex:模式           取第一个分组, 没有分组时取整个匹配
+//	default:x            为空时使用默认值
+//	count                "1.2万" 这样的热度转为整数
+//	int                  只保留数字
+//	unix                 Unix 秒或毫秒转为 RFC3339 时间
+//	abs_url              相对链接补全为绝对链接
+//	query                按 URL 查询参数转义
+func parseTransform(s string) (transform, error) {
+	name, arg, _ := strings.Cut(s, ":")
+	switch strings.TrimSpace(name) {
+	case "trim":
+		return func(v string, _ *url.URL) string { return strings.TrimSpace(v) }, nil
+	case "remove":
+		return func(v string, _ *url.URL) string { return utils.RemoveChar(v, arg) }, nil
+	case "replace":
+		old, new, ok := strings.Cut(arg, "=>")
+		if !ok {
+			return nil, fmt.Errorf("replace needs old=>new: %s", s)
+		}
+		return func(v string, _ *url.URL) string { return strings.ReplaceAll(v, old, new) }, nil
+	case "prefix":
+		return func(v string, _ *url.URL) string { return arg + v }, nil
+	case "suffix":
+		return func(v string, _ *url.URL) string { return v + arg }, nil
+	case "regex":
+		rex, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, err
+		}
+		return func(v string, _ *url.URL) string {
+			m := rex.FindStringSubmatch(v)
+			switch {
+			case m == nil:
+				return ""
+			case len(m) > 1:
+				return m[1]
+			}
+			return m[0]
+		}, nil
+	case "default":
+		return func(v string, _ *url.URL) string {
+			if v == "" {
+				return arg
+			}
+			return v
+		}, nil
+	case "count":
+		return func(v string, _ *url.URL) string { return strconv.FormatInt(utils.ParseCount(v), 10) }, nil
+	case "int":
+		return func(v string, _ *url.URL) string {
+			return strings.Map(func(r rune) rune {
+				if r >= '0' && r <= '9' {
+					return r
+				}
+				return -1
+			}, v)
+		}, nil
+	case "unix":
+		return func(v string, _ *url.URL) string {
+			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil || n <= 0 {
+				return v
+			}
+			// 超过 1e12 视为毫秒
+			if n > 1e12 {
+				return time.UnixMilli(n).Format(time.RFC3339)
+			}
+			return time.Unix(n, 0).Format(time.RFC3339)
+		}, nil
+	case "abs_url":
+		return func(v string, base *url.URL) string {
+			if v == "" || base == nil {
+				return v
+			}
+			u, err := base.Parse(strings.TrimSpace(v))
+			if err != nil {
+				return v
+			}
+			return u.String()
+		}, nil
+	case "query":
+		return func(v string, _ *url.URL) string { return url.QueryEscape(v) }, nil
+	}
+	return nil, fmt.Errorf("unknown transform: %s", s)
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/upstream"
 	"github.com/turbo-uid/hots/utils"
 
 	"github.com/gin-gonic/gin"
@@ -49,14 +50,10 @@ type To36krReqParam struct {
 var To36krUrl string = "https://gateway.36kr.com/api/mis/nav/home/nav/rank/hot"
 
 func To36krHot(c *gin.Context) {
+	hotHandler(c, globals.To36krFlag, fetchTo36krHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.To36krFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.To36krFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchTo36krHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
@@ -66,27 +63,24 @@ func To36krHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error marshaling JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	req, err := http.NewRequest("POST", To36krUrl, bytes.NewBuffer(jsonData))
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error creating request"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
+	client := upstream.Client
 	resp, err := client.Do(req)
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error making POST request"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -94,8 +88,7 @@ func To36krHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp To36krShellResponse
@@ -103,8 +96,7 @@ func To36krHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -113,18 +105,16 @@ func To36krHot(c *gin.Context) {
 	for k, v := range shellResp.Data.HotRankList {
 		var newData globals.GblRespData
 
+		newData.Id = strconv.FormatInt(v.ItemId, 10)
 		newData.Title = v.Material.Title
 		newData.Desc = ""
 		newData.HotVal = strconv.Itoa(v.Material.HotVal) // 兼容其他平台
 		newData.Pos = k + 1
 		newData.ToUrl = fmt.Sprintf("https://m.36kr.com/p/%d", v.ItemId)
 		newData.Icon = v.Material.Icon
+		newData.Author = v.Material.Author
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.To36krFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.To36krFlag), len(resultResp.Data))
-	// 将解析后的数据作为响应返回给客户端
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/apikeys"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+)
+
+type reloader struct {
+	name string
+	fn   func() error
+}
+
+// 可通过管理接口重新加载的配置, 按注册顺序执行
+var reloaders []reloader
+
+// RegisterReloader 注册可重新加载的配置, 由 main 按启用的配置注册
+func RegisterReloader(name string, fn func() error) {
+	reloaders = append(reloaders, reloader{name, fn})
+}
+
+// AdminRefresh 忽略缓存立即刷新 platform 指定的榜单(逗号分隔, 默认全部), 等待全部完成后返回各榜单结果
+func AdminRefresh(c *gin.Context) {
+	var resultResp globals.AdminResultResp
+
+	boards, unknown := ParsePlatforms(c.Query("platform"))
+	if unknown != "" {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown platform: " + unknown
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	// 从实例不请求上游
+	if !leader.IsLeader() {
+		resultResp.Code = 1
+		resultResp.Err = "Not the leader"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	results := make([]globals.AdminResult, len(boards))
+	sem := make(chan struct{}, globals.AdminRefreshConcurrency)
+	var wg sync.WaitGroup
+	for k, b := range boards {
+		wg.Add(1)
+		go func(k int, b Board) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			resp := RefreshHot(b.Flag, b.Fetch)
+			results[k] = globals.AdminResult{Name: b.Name, Ok: resp.Code == 0, Err: resp.Err, Count: len(resp.Data)}
+		}(k, b)
+	}
+	wg.Wait()
+
+	resultResp.Code = 0
+	resultResp.Succ = "ok"
+	for _, v := range results {
+		if !v.Ok {
+			resultResp.Code = 1
+			resultResp.Succ = ""
+			resultResp.Err = "Some boards failed to refresh"
+		}
+	}
+	resultResp.Data = results
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// AdminReload 重新加载配置文件, 返回各项结果; 没有启用任何可重新加载的配置时返回空列表
+func AdminReload(c *gin.Context) {
+	var resultResp globals.AdminResultResp
+
+	resultResp.Code = 0
+	resultResp.Succ = "ok"
+	resultResp.Data = []globals.AdminResult{}
+	for _, r := range reloaders {
+		result := globals.AdminResult{Name: r.name, Ok: true}
+		if err := r.fn(); err != nil {
+			globals.GoLogger.Errorf("RELOAD %s ERR %s", r.name, err.Error())
+			result.Ok = false
+			result.Err = err.Error()
+			resultResp.Code = 1
+			resultResp.Succ = ""
+			resultResp.Err = "Some configs failed to reload"
+		}
+		resultResp.Data = append(resultResp.Data, result)
+	}
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// ApiKeys 列出全部 API Key, 不含明文
+func ApiKeys(c *gin.Context) {
+	var resultResp globals.ApiKeyListResp
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = apikeys.Default.List()
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// CreateApiKey 创建 API Key, 返回的明文 key 只出现这一次
+func CreateApiKey(c *gin.Context) {
+	var resultResp globals.ApiKeyResp
+
+	var req struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid api key: " + err.Error()
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	key, err := apikeys.Default.Create(req.Name, req.Role)
+	if err != nil {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid api key: " + err.Error()
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = key
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+func RevokeApiKey(c *gin.Context) {
+	var resultResp globals.GblResp
+
+	if !apikeys.Default.Revoke(c.Param("id")) {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown api key"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+
+	c.JSON(http.StatusOK, resultResp)
+}
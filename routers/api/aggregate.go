@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Aggregate 多个榜单合并输出, 每个榜单取前 top(默认 10) 条并标明来源
+// platforms 为逗号分隔的榜单, category 为分类, 都为空时输出全部榜单
+func Aggregate(c *gin.Context) {
+	var resultResp globals.GblResp
+
+	boards, unknown := ParsePlatforms(c.Query("platforms"))
+	if unknown != "" {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown platform: " + unknown
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+	if category := c.Query("category"); category != "" {
+		boards = FindCategory(category)
+		if len(boards) == 0 {
+			resultResp.Code = 1
+			resultResp.Err = "Unknown category: " + category
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+	}
+
+	top, err := strconv.Atoi(c.DefaultQuery("top", "10"))
+	if err != nil || top <= 0 {
+		top = 10
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = AggregateItems(boards, top)
+
+	renderHot(c, resultResp)
+}
+
+// AggregateItems 按 boards 顺序合并各榜单前 top 条, 上游失败的榜单跳过
+func AggregateItems(boards []Board, top int) []globals.GblRespData {
+	var result []globals.GblRespData
+	for k, resultResp := range GetHots(boards) {
+		data := resultResp.Data
+		if top > 0 && len(data) > top {
+			data = data[:top]
+		}
+		for _, v := range data {
+			v.Platform = boards[k].Flag
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// GetHots 并发获取多个榜单, 结果顺序与 boards 一致
+func GetHots(boards []Board) []globals.GblResp {
+	result := make([]globals.GblResp, len(boards))
+
+	var wg sync.WaitGroup
+	for k, b := range boards {
+		wg.Add(1)
+		go func(k int, b Board) {
+			defer wg.Done()
+			result[k] = GetHot(b.Flag, b.Fetch)
+		}(k, b)
+	}
+	wg.Wait()
+
+	return result
+}
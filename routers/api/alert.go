@@ -0,0 +1,138 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/alerts"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// WatchRules 列出全部关注规则
+func WatchRules(c *gin.Context) {
+	var resultResp globals.WatchRuleListResp
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = alerts.Default.Rules()
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// WatchRule 查看单个关注规则
+func WatchRule(c *gin.Context) {
+	var resultResp globals.WatchRuleResp
+
+	rule, found := alerts.Default.Rule(c.Param("id"))
+	if !found {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown rule"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = rule
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// AddWatchRule 新增关注规则, platforms 可以是路由名或平台标识, 统一保存为平台标识
+func AddWatchRule(c *gin.Context) {
+	var resultResp globals.WatchRuleResp
+
+	var rule globals.WatchRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid rule: " + err.Error()
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	var flags []string
+	for _, v := range rule.Platforms {
+		b, ok := FindBoard(v)
+		if !ok {
+			resultResp.Code = 1
+			resultResp.Err = "Unknown platform: " + v
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+		flags = append(flags, b.Flag)
+	}
+	rule.Platforms = flags
+
+	rule, err := alerts.Default.AddRule(rule)
+	if err != nil {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid rule: " + err.Error()
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = rule
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// DeleteWatchRule 删除关注规则, 已产生的告警保留
+func DeleteWatchRule(c *gin.Context) {
+	var resultResp globals.GblResp
+
+	if !alerts.Default.RemoveRule(c.Param("id")) {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown rule"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// Alerts 按时间倒序列出告警, 可按 rule、platform、since 过滤, limit 默认 50
+func Alerts(c *gin.Context) {
+	var resultResp globals.AlertListResp
+
+	platform := c.Query("platform")
+	if platform != "" {
+		b, ok := FindBoard(platform)
+		if !ok {
+			resultResp.Code = 1
+			resultResp.Err = "Unknown platform"
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+		platform = b.Flag
+	}
+
+	var since time.Time
+	if s := c.Query("since"); s != "" {
+		var err error
+		if since, err = utils.ParseTime(s, time.Now()); err != nil {
+			resultResp.Code = 1
+			resultResp.Err = "Invalid since"
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = alerts.Default.Alerts(c.Query("rule"), platform, since, limit)
+
+	c.JSON(http.StatusOK, resultResp)
+}
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"net/http"
 	"strconv"
 	"strings"
 
@@ -9,26 +8,21 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 )
 
 func BaiduHot(c *gin.Context) {
+	hotHandler(c, globals.BaiduFlag, fetchBaiduHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.BaiduFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.BaiduFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchBaiduHot() globals.GblResp {
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://top.baidu.com/board?tab=realtime")
+	resp, err := upstream.Get("https://top.baidu.com/board?tab=realtime")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -36,7 +30,7 @@ func BaiduHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
+		return resultResp
 	}
 
 	doc.Find(".category-wrap_iQLoo").Each(func(i int, s *goquery.Selection) {
@@ -92,9 +86,5 @@ func BaiduHot(c *gin.Context) {
 		resultResp.Code = 0
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.BaiduFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.BaiduFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
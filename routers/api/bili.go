@@ -4,12 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 )
 
 type BiliShellResponse struct {
@@ -40,23 +39,18 @@ type BiliTopList struct {
 }
 
 func BiliHot(c *gin.Context) {
+	hotHandler(c, globals.BiliFlag, fetchBiliHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.BiliFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.BiliFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchBiliHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://app.bilibili.com/x/v2/search/trending/ranking?limit=30")
+	resp, err := upstream.Get("https://app.bilibili.com/x/v2/search/trending/ranking?limit=30")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -64,8 +58,7 @@ func BiliHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp BiliShellResponse
@@ -73,8 +66,7 @@ func BiliHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -104,9 +96,5 @@ func BiliHot(c *gin.Context) {
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.BiliFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.BiliFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
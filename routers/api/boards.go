@@ -0,0 +1,131 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Board 一个榜单: 路由名、缓存/快照标识、分类、获取函数以及接口路径
+type Board struct {
+	Name     string
+	Flag     string
+	Category string
+	Fetch    func() globals.GblResp
+	// 接口路径, 可能带查询参数, 如 /api/hot/endata?t=s; 与 /api/hot/<Name> 不同时两者都可以访问
+	Path string
+}
+
+// Boards 所有支持快照的榜单, 顺序与路由注册一致
+var Boards = []Board{
+	{"bili", globals.BiliFlag, globals.CategoryNews, fetchBiliHot, "/api/hot/bili"},
+	{"weibo", globals.WeiboFlag, globals.CategoryNews, fetchWeiboHot, "/api/hot/weibo"},
+	{"douyin", globals.DouyinFlag, globals.CategoryNews, fetchDouyinHot, "/api/hot/douyin"},
+	{"toutiao", globals.ToutiaoFlag, globals.CategoryNews, fetchToutiaoHot, "/api/hot/toutiao"},
+	{"douban", globals.DoubanFlag, globals.CategoryNews, fetchDoubanHot, "/api/hot/douban"},
+	{"thepaper", globals.ThepaperFlag, globals.CategoryNews, fetchThepaperHot, "/api/hot/thepaper"},
+	{"xhs", globals.XhsFlag, globals.CategoryNews, fetchXhsHot, "/api/hot/xhs"},
+	{"wy163", globals.Wy163Flag, globals.CategoryNews, fetchWy163Hot, "/api/hot/wy163"},
+	{"qq", globals.QqFlag, globals.CategoryNews, fetchQqHot, "/api/hot/qq"},
+	{"baidu", globals.BaiduFlag, globals.CategoryNews, fetchBaiduHot, "/api/hot/baidu"},
+	{"zhihu", globals.ZhihuFlag, globals.CategoryNews, fetchZhihuByJsonHot, "/api/hot/zhihu/v2"},
+	{"36kr", globals.To36krFlag, globals.CategoryNews, fetchTo36krHot, "/api/hot/36kr"},
+	{"csdn", globals.CsdnFlag, globals.CategoryTech, fetchCsdnHot, "/api/hot/csdn"},
+	{"csdn-content", globals.CsdnContentFlag, globals.CategoryTech, fetchCsdnContent, "/api/hot/csdn-content"},
+	{"hellogithub", globals.HelloGithubFlag, globals.CategoryTech, fetchHelloGithubHot, "/api/hot/hellogithub"},
+	{"ithome", globals.ItHomeFlag, globals.CategoryTech, fetchItHomeHot, "/api/hot/ithome"},
+	{"juejin", globals.JueJinFlag, globals.CategoryTech, fetchJueJinHot, "/api/hot/juejin"},
+	{"juejin-aibox", globals.JueJinAIBoxFlag, globals.CategoryTech, fetchJueJinAIBox, "/api/hot/juejin-aibox"},
+	{"carhome", globals.CarHomeFlag, globals.CategoryCar, fetchCarHomeHot, "/api/hot/carhome"},
+	{"dongchedi", globals.DongCheDiFlag, globals.CategoryCar, fetchDongCheDiHot, "/api/hot/dongchedi"},
+	{"cheshi", globals.CheShiFlag, globals.CategoryCar, fetchCheShiHot, "/api/hot/cheshi"},
+	{"qctt", globals.QcttFlag, globals.CategoryCar, fetchQcttHot, "/api/hot/qctt"},
+	{"endata", globals.EnDataMFlag, globals.CategoryMovie, fetchEnDataMHot, "/api/hot/endata"},
+	{"endata-s", globals.EnDataSFlag, globals.CategoryMovie, fetchEnDataSHot, "/api/hot/endata?t=s"},
+	{"toolify", globals.ToolifyFlag, globals.CategoryAI, fetchToolifyHot, "/api/hot/toolify"},
+}
+
+// 配置文件定义的榜单, 由 RegisterBoard 加入
+var customBoards []Board
+
+var boardNameRex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// RegisterBoard 注册配置文件定义的榜单, 需在 InitRouter 之前调用; 路由名或标识与已有榜单重复时返回错误
+// all 为聚合订阅源 /feed/all.* 保留, 不能用作路由名或标识
+func RegisterBoard(b Board) error {
+	if !boardNameRex.MatchString(b.Name) {
+		return fmt.Errorf("invalid board name: %s", b.Name)
+	}
+	for _, name := range []string{b.Name, b.Flag} {
+		if name == aggregateName {
+			return fmt.Errorf("board name %s is reserved", name)
+		}
+		if _, found := FindBoard(name); found {
+			return fmt.Errorf("board %s already exists", name)
+		}
+	}
+
+	if b.Path == "" {
+		b.Path = "/api/hot/" + b.Name
+	}
+	Boards = append(Boards, b)
+	customBoards = append(customBoards, b)
+	return nil
+}
+
+// CustomBoards 配置文件定义的榜单
+func CustomBoards() []Board {
+	return customBoards
+}
+
+// BoardHandler 榜单的路由处理函数
+func BoardHandler(b Board) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hotHandler(c, b.Flag, b.Fetch)
+	}
+}
+
+// FindBoard 按路由名或标识查找榜单, 如 bili 或 bilibili
+func FindBoard(name string) (Board, bool) {
+	for _, b := range Boards {
+		if b.Name == name || b.Flag == name {
+			return b, true
+		}
+	}
+	return Board{}, false
+}
+
+// ParsePlatforms 解析逗号分隔的榜单列表, 为空时返回全部; 第二个返回值为无法识别的名称
+func ParsePlatforms(s string) ([]Board, string) {
+	if strings.TrimSpace(s) == "" {
+		return Boards, ""
+	}
+
+	var result []Board
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		b, ok := FindBoard(name)
+		if !ok {
+			return nil, name
+		}
+		result = append(result, b)
+	}
+	return result, ""
+}
+
+// FindCategory 某一分类下的榜单
+func FindCategory(category string) []Board {
+	var result []Board
+	for _, b := range Boards {
+		if b.Category == category {
+			result = append(result, b)
+		}
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+func TestRegisterBoard(t *testing.T) {
+	boards, custom := Boards, customBoards
+	t.Cleanup(func() { Boards, customBoards = boards, custom })
+	fetch := func() globals.GblResp { return globals.GblResp{} }
+
+	tests := []struct {
+		board Board
+		err   string
+	}{
+		// all 与聚合订阅源 /feed/all.* 冲突
+		{Board{Name: "all", Flag: "my-all", Fetch: fetch}, "reserved"},
+		{Board{Name: "my-all", Flag: "all", Fetch: fetch}, "reserved"},
+		{Board{Name: "Bad Name", Flag: "bad", Fetch: fetch}, "invalid board name"},
+		{Board{Name: "weibo", Flag: "my-weibo", Fetch: fetch}, "already exists"},
+		{Board{Name: "my-bili", Flag: globals.BiliFlag, Fetch: fetch}, "already exists"},
+	}
+	for _, tt := range tests {
+		if err := RegisterBoard(tt.board); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("RegisterBoard(%s/%s) err = %v, want %q", tt.board.Name, tt.board.Flag, err, tt.err)
+		}
+	}
+	if len(Boards) != len(boards) || len(customBoards) != len(custom) {
+		t.Fatal("rejected boards were registered")
+	}
+
+	if err := RegisterBoard(Board{Name: "my-board", Flag: "my-board-flag", Fetch: fetch}); err != nil {
+		t.Fatal(err)
+	}
+	b, found := FindBoard("my-board-flag")
+	if !found || b.Path != "/api/hot/my-board" || len(CustomBoards()) != len(custom)+1 {
+		t.Errorf("registered board = %+v, %v", b, found)
+	}
+}
@@ -3,12 +3,11 @@ package api
 import (
 	"encoding/json"
 	"io/ioutil"
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 )
 
 type CarHomeShellResponse struct {
@@ -28,24 +27,19 @@ type CarHomeData struct {
 var CarHomeUrl string = "https://content.api.autohome.com.cn/pc/rank/list?ranktype=1&count=20"
 
 func CarHomeHot(c *gin.Context) {
+	hotHandler(c, globals.CarHomeFlag, fetchCarHomeHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.CarHomeFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.CarHomeFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchCarHomeHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get(CarHomeUrl)
+	resp, err := upstream.Get(CarHomeUrl)
 
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -53,8 +47,7 @@ func CarHomeHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp CarHomeShellResponse
@@ -62,8 +55,7 @@ func CarHomeHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -72,6 +64,9 @@ func CarHomeHot(c *gin.Context) {
 	for k, v := range shellResp.Data {
 		var newData globals.GblRespData
 
+		if v.BizId > 0 {
+			newData.Id = strconv.Itoa(v.BizId)
+		}
 		newData.Title = v.Title
 		newData.Desc = v.Desc
 		newData.HotVal = strconv.Itoa(v.HotVal)
@@ -81,9 +76,5 @@ func CarHomeHot(c *gin.Context) {
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.CarHomeFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.CarHomeFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
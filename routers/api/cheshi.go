@@ -1,34 +1,28 @@
 package api
 
 import (
-	"net/http"
 	"regexp"
 	"strings"
 
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gin-gonic/gin"
 )
 
 func CheShiHot(c *gin.Context) {
+	hotHandler(c, globals.CheShiFlag, fetchCheShiHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.CheShiFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.CheShiFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchCheShiHot() globals.GblResp {
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://news.cheshi.com/djbd/")
+	resp, err := upstream.Get("https://news.cheshi.com/djbd/")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -36,7 +30,7 @@ func CheShiHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
+		return resultResp
 	}
 
 	doc.Find(".fall_list").Each(func(i int, s *goquery.Selection) {
@@ -72,9 +66,5 @@ func CheShiHot(c *gin.Context) {
 		resultResp.Code = 0
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.CheShiFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.CheShiFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
@@ -3,10 +3,9 @@ package api
 import (
 	"encoding/json"
 	"io/ioutil"
-	"net/http"
 
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,23 +26,18 @@ type CsdnData struct {
 }
 
 func CsdnHot(c *gin.Context) {
+	hotHandler(c, globals.CsdnFlag, fetchCsdnHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.CsdnFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.CsdnFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchCsdnHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://blog.csdn.net/phoenix/web/blog/hot-rank?page=0&pageSize=30")
+	resp, err := upstream.Get("https://blog.csdn.net/phoenix/web/blog/hot-rank?page=0&pageSize=30")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -51,8 +45,7 @@ func CsdnHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp CsdnShellResponse
@@ -60,8 +53,7 @@ func CsdnHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -75,6 +67,7 @@ func CsdnHot(c *gin.Context) {
 		newData.HotVal = v.PcHotRankScore
 		newData.Pos = k + 1
 		newData.ToUrl = v.Url
+		newData.Author = v.Author
 
 		if len(v.PicList) > 0 {
 			newData.Icon = v.PicList[0]
@@ -82,31 +75,22 @@ func CsdnHot(c *gin.Context) {
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.CsdnFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.CsdnFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
 
 func CsdnContent(c *gin.Context) {
+	hotHandler(c, globals.CsdnContentFlag, fetchCsdnContent)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.CsdnContentFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.CsdnContentFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchCsdnContent() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://blog.csdn.net/phoenix/web/blog/hot-rank?page=0&pageSize=50&child_channel=%E4%BA%BA%E5%B7%A5%E6%99%BA%E8%83%BD&type=")
+	resp, err := upstream.Get("https://blog.csdn.net/phoenix/web/blog/hot-rank?page=0&pageSize=50&child_channel=%E4%BA%BA%E5%B7%A5%E6%99%BA%E8%83%BD&type=")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -114,8 +98,7 @@ func CsdnContent(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp CsdnShellResponse
@@ -123,8 +106,7 @@ func CsdnContent(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -138,6 +120,7 @@ func CsdnContent(c *gin.Context) {
 		newData.HotVal = v.PcHotRankScore
 		newData.Pos = k + 1
 		newData.ToUrl = v.Url
+		newData.Author = v.Author
 
 		if len(v.PicList) > 0 {
 			newData.Icon = v.PicList[0]
@@ -145,9 +128,5 @@ func CsdnContent(c *gin.Context) {
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.CsdnContentFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.CsdnContentFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
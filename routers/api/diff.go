@@ -0,0 +1,134 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/snapshots"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// HotDiff 对比榜单在 since 时的快照与当前(或 to 指定的)快照
+// since/to 可以是快照ID、Unix 时间戳、RFC3339 时间或 30m 这样的相对时长
+func HotDiff(c *gin.Context) {
+	var resultResp globals.DiffResp
+
+	board, ok := FindBoard(c.Param("platform"))
+	if !ok {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown platform"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	if globals.GoSnapshots == nil {
+		resultResp.Code = 1
+		resultResp.Err = "Snapshots are disabled"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	since := c.Query("since")
+	if since == "" {
+		resultResp.Code = 1
+		resultResp.Err = "Missing since"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	var to globals.Snapshot
+	var found bool
+	if s := c.Query("to"); s != "" {
+		to, found = findSnapshot(board.Flag, s)
+	} else {
+		// 缓存过期时顺便刷新, 保证对比的是当前榜单
+		GetHot(board.Flag, board.Fetch)
+		to, found = globals.GoSnapshots.Latest(board.Flag)
+	}
+	if !found {
+		resultResp.Code = 1
+		resultResp.Err = "No snapshot found for to"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	from, found := findSnapshot(board.Flag, since)
+	if !found {
+		resultResp.Code = 1
+		resultResp.Err = "No snapshot found for since"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	diff := snapshots.Diff(from, to)
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = &diff
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// HotHistory 列出榜单的历史快照, from/to 格式同 HotDiff, limit 默认 20
+func HotHistory(c *gin.Context) {
+	var resultResp globals.SnapshotListResp
+
+	board, ok := FindBoard(c.Param("platform"))
+	if !ok {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown platform"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	if globals.GoSnapshots == nil {
+		resultResp.Code = 1
+		resultResp.Err = "Snapshots are disabled"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	var from, to time.Time
+	var err error
+	now := time.Now()
+	if s := c.Query("from"); s != "" {
+		if from, err = utils.ParseTime(s, now); err != nil {
+			resultResp.Code = 1
+			resultResp.Err = "Invalid from"
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+	}
+	if s := c.Query("to"); s != "" {
+		if to, err = utils.ParseTime(s, now); err != nil {
+			resultResp.Code = 1
+			resultResp.Err = "Invalid to"
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	list := globals.GoSnapshots.List(board.Flag, from, to)
+	if len(list) > limit {
+		list = list[len(list)-limit:]
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = list
+
+	renderHistory(c, resultResp)
+}
+
+// findSnapshot 先按快照ID查找, 否则按时间取当时的快照
+func findSnapshot(flag, s string) (globals.Snapshot, bool) {
+	return snapshots.Find(globals.GoSnapshots, flag, s, time.Now())
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/digests"
+	"github.com/turbo-uid/hots/formats"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Digests 按时间倒序列出摘要, period 为 daily/weekly, limit 默认 10
+func Digests(c *gin.Context) {
+	var resultResp globals.DigestListResp
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = digests.Default.List(c.Query("period"), limit)
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// Digest 查看一期摘要, 支持 json、markdown、html 格式
+func Digest(c *gin.Context) {
+	var resultResp globals.DigestResp
+
+	digest, found := digests.Default.Get(c.Param("id"))
+	if !found {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown digest"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	switch format := requestFormat(c); format {
+	case formats.JSON:
+		resultResp.Succ = "ok"
+		resultResp.Code = 0
+		resultResp.Data = digest
+		c.JSON(http.StatusOK, resultResp)
+	case formats.Markdown:
+		c.Data(http.StatusOK, formats.ContentType(format), []byte(digests.Markdown(digest)))
+	case formats.HTML:
+		c.Data(http.StatusOK, formats.ContentType(format), []byte(digests.HTML(digest)))
+	default:
+		resultResp.Code = 1
+		resultResp.Err = "Unsupported format"
+		c.JSON(http.StatusOK, resultResp)
+	}
+}
+
+// GenerateDigest 立即生成截至当前的一期摘要, push=true 时同时推送
+func GenerateDigest(c *gin.Context) {
+	var resultResp globals.DigestResp
+
+	digest, err := digests.Default.Generate(c.DefaultQuery("period", globals.DigestDaily), time.Now())
+	if err != nil {
+		resultResp.Code = 1
+		resultResp.Err = err.Error()
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	if push, _ := strconv.ParseBool(c.Query("push")); push {
+		if err := digests.Default.Push(digest); err != nil {
+			resultResp.Code = 1
+			resultResp.Err = "Push failed: " + err.Error()
+			resultResp.Data = digest
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = digest
+
+	c.JSON(http.StatusOK, resultResp)
+}
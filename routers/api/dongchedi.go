@@ -4,11 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"strconv"
 
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 
 	"github.com/gin-gonic/gin"
 )
@@ -33,24 +32,19 @@ type DongCheDiList struct {
 var DongCheDiUrl string = "https://www.dongchedi.com/motor/pc/content/pgc_content_rank?aid=1839&app_name=auto_web_pc&rank_type=pgc_article_total_rank" // 文章榜
 
 func DongCheDiHot(c *gin.Context) {
+	hotHandler(c, globals.DongCheDiFlag, fetchDongCheDiHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.DongCheDiFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.DongCheDiFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchDongCheDiHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get(DongCheDiUrl)
+	resp, err := upstream.Get(DongCheDiUrl)
 
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -58,8 +52,7 @@ func DongCheDiHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp DongCheDiShellResponse
@@ -67,8 +60,7 @@ func DongCheDiHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -76,11 +68,8 @@ func DongCheDiHot(c *gin.Context) {
 
 	for k, v := range shellResp.Data.List {
 		var newData globals.GblRespData
-		type DongCheDiList struct {
-			Title   string `json:"title"`
-			HotVal  int    `json:"count"`
-			GroupId string `json:"group_id"`
-		}
+
+		newData.Id = v.GroupId
 		newData.Title = v.Title
 		newData.Desc = ""
 		newData.HotVal = strconv.Itoa(v.HotVal)
@@ -88,13 +77,10 @@ func DongCheDiHot(c *gin.Context) {
 		// https://www.dongchedi.com/video/7457505435944223273
 		// https://www.dongchedi.com/article/7459679563439473203
 		newData.ToUrl = fmt.Sprintf("https://www.dongchedi.com/article/%s", v.GroupId)
+		newData.Extra.Set(globals.ExtraGroupId, v.GroupId)
 
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.DongCheDiFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.DongCheDiFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
@@ -8,7 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 )
 
 type DoubanShellResponse struct {
@@ -29,25 +29,20 @@ type DoubanTailIcon struct {
 }
 
 func DoubanHot(c *gin.Context) {
+	hotHandler(c, globals.DoubanFlag, fetchDoubanHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.DoubanFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.DoubanFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchDoubanHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	client := &http.Client{}
+	client := upstream.Client
 
 	req, err := http.NewRequest("GET", "https://m.douban.com/rexxar/api/v2/search/hots?ck=", nil)
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error creating request"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	// 设置请求头部
 	req.Header.Set("Accept", "application/json, text/javascript, */*; q=0.01")
@@ -64,8 +59,7 @@ func DoubanHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error making GET request"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	defer resp.Body.Close()
@@ -74,8 +68,7 @@ func DoubanHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp DoubanShellResponse
@@ -83,8 +76,7 @@ func DoubanHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -102,9 +94,5 @@ func DoubanHot(c *gin.Context) {
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.DoubanFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.DoubanFlag), len(resultResp.Data))
-	// 将解析后的数据作为响应返回给客户端
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
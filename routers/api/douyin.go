@@ -4,12 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 )
 
 type DouyinShellResponse struct {
@@ -30,24 +29,19 @@ type DouyinList struct {
 }
 
 func DouyinHot(c *gin.Context) {
+	hotHandler(c, globals.DouyinFlag, fetchDouyinHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.DouyinFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.DouyinFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchDouyinHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://aweme-lq.snssdk.com/aweme/v1/hot/search/list/?device_platform=webapp&aid=6383&channel=channel_pc_web&detail_list=1&source=6&main_billboard_count=5&update_version_code=170400&pc_client_type=1&pc_libra_divert=Windows&version_code=170400&version_name=17.4.0&cookie_enabled=true&screen_width=1920&screen_height=1080&browser_language=zh-CN&browser_platform=Win32&browser_name=Chrome&browser_version=131.0.0.0&browser_online=true&engine_name=Blink&engine_version=131.0.0.0&os_name=Windows") // 替换为实际的第三方API URL
+	resp, err := upstream.Get("https://aweme-lq.snssdk.com/aweme/v1/hot/search/list/?device_platform=webapp&aid=6383&channel=channel_pc_web&detail_list=1&source=6&main_billboard_count=5&update_version_code=170400&pc_client_type=1&pc_libra_divert=Windows&version_code=170400&version_name=17.4.0&cookie_enabled=true&screen_width=1920&screen_height=1080&browser_language=zh-CN&browser_platform=Win32&browser_name=Chrome&browser_version=131.0.0.0&browser_online=true&engine_name=Blink&engine_version=131.0.0.0&os_name=Windows") // 替换为实际的第三方API URL
 
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -55,8 +49,7 @@ func DouyinHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp DouyinShellResponse
@@ -64,8 +57,7 @@ func DouyinHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -90,17 +82,19 @@ func DouyinHot(c *gin.Context) {
 		} else {
 			newData.Lab = ""
 		}
+		if newData.Lab != "" {
+			newData.Badges = append(newData.Badges, newData.Lab)
+		}
 		if v.IsN1 || (v.HotVal == 0 && v.Pos == 0) {
 			newData.Pos = 999
 			newData.IsTop = 1
 		}
+		if v.IsN1 {
+			newData.Badges = append(newData.Badges, "n1")
+		}
 
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.DouyinFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.DouyinFlag), len(resultResp.Data))
-	// 将解析后的数据作为响应返回给客户端
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
@@ -5,12 +5,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/rand"
-	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 
 	"github.com/gin-gonic/gin"
 )
@@ -37,42 +36,39 @@ var EnDataMUrl string = "https://ys.endata.cn/enlib-api/api/home/getrank_mainlan
 var EnDataSUrl string = "https://ys.endata.cn/enlib-api/api/home/getrank_singleday.do"
 
 func EnDataHot(c *gin.Context) {
-	var EnDataUrl, EnDataFlag, EnDataType string
-
-	s := c.DefaultQuery("t", "m")
-	if s == "s" {
-		EnDataUrl = EnDataSUrl
-		EnDataFlag = globals.EnDataSFlag
-		EnDataType = "1"
+	if c.DefaultQuery("t", "m") == "s" {
+		hotHandler(c, globals.EnDataSFlag, fetchEnDataSHot)
 	} else {
-		EnDataUrl = EnDataMUrl
-		EnDataFlag = globals.EnDataMFlag
-		EnDataType = "0"
+		hotHandler(c, globals.EnDataMFlag, fetchEnDataMHot)
 	}
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(EnDataFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(EnDataFlag))
+// 内地总票房榜
+func fetchEnDataMHot() globals.GblResp {
+	return fetchEnData(EnDataMUrl, "0")
+}
 
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
+// 单日票房榜
+func fetchEnDataSHot() globals.GblResp {
+	return fetchEnData(EnDataSUrl, "1")
+}
 
+func fetchEnData(enDataUrl, enDataType string) globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
 	formData := url.Values{
 		"r":    {fmtRandomNum()},
 		"top":  {"50"},
-		"type": {EnDataType},
+		"type": {enDataType},
 	}
 
 	// 发送 POST 请求
-	resp, err := http.PostForm(EnDataUrl, formData)
+	resp, err := upstream.Client.PostForm(enDataUrl, formData)
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to send POST request"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -80,8 +76,7 @@ func EnDataHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp EnDataShellResponse
@@ -89,8 +84,7 @@ func EnDataHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -99,27 +93,18 @@ func EnDataHot(c *gin.Context) {
 	for _, v := range shellResp.Data.Table0 {
 		var newData globals.GblRespData
 
-		type EnDataTableList struct {
-			MovieName   string  `json:"MovieName"`
-			ReleaseTime string  `json:"ReleaseTime"`
-			BoxOffice   float64 `json:"BoxOffice"`
-			Irank       int     `json:"Irank"`
-		}
-
 		newData.Title = v.MovieName
 		newData.Desc = v.ReleaseTime
 		newData.HotVal = fmtBoxOffice(v.BoxOffice)
 		newData.Pos = v.Irank
 		newData.ToUrl = ""
+		newData.Extra.Set(globals.ExtraReleaseDate, v.ReleaseTime).
+			Set(globals.ExtraBoxOffice, v.BoxOffice)
 
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(EnDataFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(EnDataFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
 
 func fmtBoxOffice(num float64) string {
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/feeds"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// 聚合订阅源的名称
+const aggregateName = "all"
+
+// FeedBoard /feed/:platform.{rss,atom,json}, platform 为 all 时输出所有榜单的聚合
+func FeedBoard(c *gin.Context) {
+	name, format := splitFeedName(c.Param("name"))
+
+	if name == aggregateName {
+		renderFeed(c, format, "全网热榜", "/api/aggregate", Boards)
+		return
+	}
+
+	b, ok := FindBoard(name)
+	if !ok {
+		c.String(http.StatusNotFound, "Unknown platform")
+		return
+	}
+
+	renderFeed(c, format, b.Name+" 热榜", b.Path, []Board{b})
+}
+
+// FeedCategory /feed/category/:category.{rss,atom,json}
+func FeedCategory(c *gin.Context) {
+	name, format := splitFeedName(c.Param("name"))
+
+	boards := FindCategory(name)
+	if len(boards) == 0 {
+		c.String(http.StatusNotFound, "Unknown category")
+		return
+	}
+
+	renderFeed(c, format, name+" 分类热榜", "/api/aggregate?category="+name, boards)
+}
+
+func splitFeedName(s string) (string, string) {
+	ext := path.Ext(s)
+	return strings.TrimSuffix(s, ext), strings.TrimPrefix(ext, ".")
+}
+
+// renderFeed 单个榜单按排名输出; 多个榜单每个取前 top(默认 10) 条, 按首次出现时间倒序
+func renderFeed(c *gin.Context, format, title, link string, boards []Board) {
+	contentType := feeds.ContentType(format)
+	if contentType == "" {
+		c.String(http.StatusNotFound, "Unsupported feed format")
+		return
+	}
+
+	top, err := strconv.Atoi(c.DefaultQuery("top", "10"))
+	if err != nil || top <= 0 {
+		top = 10
+	}
+
+	base := requestBaseUrl(c)
+	feed := feeds.Feed{
+		Title:   title,
+		Link:    base + link,
+		FeedUrl: base + c.Request.URL.Path,
+		Desc:    title,
+		Updated: time.Now(),
+	}
+
+	if len(boards) == 1 {
+		top = 0
+	}
+
+	now := time.Now()
+	for _, v := range AggregateItems(boards, top) {
+		item := feeds.Item{
+			Id:        v.Id,
+			Title:     v.Title,
+			Link:      v.ToUrl,
+			Desc:      v.Desc,
+			Image:     v.Icon,
+			Author:    v.Author,
+			Published: now,
+		}
+		if len(boards) > 1 {
+			item.Category = v.Platform
+		}
+		if globals.GoSnapshots != nil {
+			if t, found := globals.GoSnapshots.FirstSeen(v.Platform, v.Id); found {
+				item.Published = t
+			}
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	if len(boards) > 1 {
+		sort.SliceStable(feed.Items, func(i, j int) bool {
+			return feed.Items[i].Published.After(feed.Items[j].Published)
+		})
+	}
+
+	out, err := feed.Render(format)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to render feed")
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, out)
+}
+
+// requestBaseUrl 经过 nginx 转发时以 X-Forwarded-Proto 为准
+func requestBaseUrl(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return fmt.Sprintf("%s://%s", scheme, c.Request.Host)
+}
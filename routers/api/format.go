@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/formats"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/translate"
+)
+
+// requestFormat ?format= 优先, 其次按 Accept 头协商; format 不支持时返回空
+func requestFormat(c *gin.Context) string {
+	if s := c.Query("format"); s != "" {
+		return formats.Parse(s)
+	}
+	return formats.Negotiate(c.GetHeader("Accept"))
+}
+
+// renderTable 非 JSON 格式输出表格, 出错或 JSON 时输出 obj
+func renderTable(c *gin.Context, code int, table func() formats.Table, obj interface{}) {
+	format := requestFormat(c)
+	if format == "" {
+		c.JSON(http.StatusOK, globals.GblResp{Code: 1, Err: "Unsupported format: " + c.Query("format")})
+		return
+	}
+	if format == formats.JSON || code != 0 {
+		c.JSON(http.StatusOK, obj)
+		return
+	}
+
+	out, err := table().Render(format)
+	if err != nil {
+		c.JSON(http.StatusOK, globals.GblResp{Code: 1, Err: err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, formats.ContentType(format), out)
+}
+
+// renderHot 输出榜单或聚合结果, 带 ?lang= 时标题替换为译文, 翻译失败的条目保留原文
+func renderHot(c *gin.Context, resultResp globals.GblResp) {
+	if lang := c.Query("lang"); lang != "" && resultResp.Code == 0 {
+		if !translate.Default.Supported(lang) {
+			c.JSON(http.StatusOK, globals.GblResp{Code: 1, Err: "Unsupported lang: " + lang})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), globals.TranslateRequestTimeout)
+		var err error
+		resultResp, err = translate.Default.Apply(ctx, resultResp, lang)
+		cancel()
+		if err != nil {
+			globals.GoLogger.Errorf("TRANSLATE %s ERR %s", lang, err.Error())
+		}
+	}
+
+	renderTable(c, resultResp.Code, func() formats.Table {
+		return formats.ItemsTable(resultResp.Data)
+	}, resultResp)
+}
+
+// renderHistory 输出历史快照, 表格格式下每个条目一行
+func renderHistory(c *gin.Context, resultResp globals.SnapshotListResp) {
+	renderTable(c, resultResp.Code, func() formats.Table {
+		return formats.SnapshotsTable(resultResp.Data)
+	}, resultResp)
+}
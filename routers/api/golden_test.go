@@ -0,0 +1,52 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/turbo-uid/hots/upstream"
+)
+
+// TestGolden 回放 testdata/fixtures 中的上游响应, 解析结果需与 testdata/golden 一致, 同 go run ./cmd/golden -strict
+// 解析逻辑有意改动后用 go run ./cmd/golden -update 重写 golden 文件
+func TestGolden(t *testing.T) {
+	root := filepath.Join("..", "..", "testdata")
+	recorder, err := upstream.NewRecorder(upstream.ModeReplay, filepath.Join(root, "fixtures"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	useUpstream(t, recorder)
+
+	for _, b := range Boards {
+		t.Run(b.Name, func(t *testing.T) {
+			resultResp := b.Fetch()
+			if missing := recorder.TakeMissing(); len(missing) > 0 {
+				t.Fatalf("no fixture for %s", strings.Join(missing, ", "))
+			}
+			if resultResp.Code != 0 || len(resultResp.Data) == 0 {
+				t.Fatalf("code = %d, err = %q, %d items", resultResp.Code, resultResp.Err, len(resultResp.Data))
+			}
+
+			var got bytes.Buffer
+			enc := json.NewEncoder(&got)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(resultResp); err != nil {
+				t.Fatal(err)
+			}
+
+			file := filepath.Join(root, "golden", b.Flag+".json")
+			want, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(want, got.Bytes()) {
+				t.Errorf("%s differs, run go run ./cmd/golden to see the first difference", file)
+			}
+		})
+	}
+}
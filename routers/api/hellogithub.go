@@ -4,10 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,23 +26,18 @@ type HelloGithubData struct {
 }
 
 func HelloGithubHot(c *gin.Context) {
+	hotHandler(c, globals.HelloGithubFlag, fetchHelloGithubHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.HelloGithubFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.HelloGithubFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchHelloGithubHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://abroad.hellogithub.com/v1/?sort_by=all&tid=&page=1")
+	resp, err := upstream.Get("https://abroad.hellogithub.com/v1/?sort_by=all&tid=&page=1")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -51,8 +45,7 @@ func HelloGithubHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp HelloGithubShellResponse
@@ -60,8 +53,7 @@ func HelloGithubHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -70,18 +62,17 @@ func HelloGithubHot(c *gin.Context) {
 	for k, v := range shellResp.Data {
 		var newData globals.GblRespData
 
+		newData.Id = v.ItemId
 		newData.Title = v.Title
 		newData.Desc = v.Desc
 		newData.HotVal = fmt.Sprintf("%d", v.ClicksTotal)
 		newData.Pos = k + 1
 		newData.ToUrl = fmt.Sprintf("https://hellogithub.com/repository/%s", v.ItemId)
+		newData.Author = v.Author
+		newData.Views = int64(v.ClicksTotal)
 
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.HelloGithubFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.HelloGithubFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/alerts"
+	"github.com/turbo-uid/hots/events"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+	"github.com/turbo-uid/hots/search"
+	"github.com/turbo-uid/hots/sinks"
+	"github.com/turbo-uid/hots/snapshots"
+	"github.com/turbo-uid/hots/translate"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// hotHandler 统一输出榜单, fetch 负责请求上游并转换为统一输出结果
+func hotHandler(c *gin.Context, flag string, fetch func() globals.GblResp) {
+	// 将解析后的数据作为响应返回给客户端
+	renderHot(c, GetHot(flag, fetch))
+}
+
+// 同一榜单同时只有一个请求访问上游
+var fetchLocks sync.Map
+
+// GetHot 优先读取缓存, 未命中时请求上游, 成功后写入缓存、保存快照并广播变化; 上游失败时返回之前的榜单
+func GetHot(flag string, fetch func() globals.GblResp) globals.GblResp {
+
+	var resultResp globals.GblResp
+	if globals.GoCache.Get(utils.GetHotCacheKey(flag), &resultResp) {
+		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(flag))
+
+		return resultResp
+	}
+
+	return loadHot(flag, fetch, false)
+}
+
+// RefreshHot 忽略缓存立即请求上游, 用于手动刷新; 上游失败时返回错误而不是之前的榜单
+func RefreshHot(flag string, fetch func() globals.GblResp) globals.GblResp {
+	return loadHot(flag, fetch, true)
+}
+
+func loadHot(flag string, fetch func() globals.GblResp, force bool) globals.GblResp {
+	// 开启选举后从实例不请求上游, 也不等待, 直接返回 leader 最近写入共享缓存的榜单
+	if !leader.IsLeader() {
+		var sharedResp globals.GblResp
+		if globals.GoCache.Get(utils.GetHotStaleKey(flag), &sharedResp) {
+			return sharedResp
+		}
+		return globals.GblResp{Code: 1, Err: "Waiting for leader to refresh"}
+	}
+
+	var resultResp globals.GblResp
+
+	lock, _ := fetchLocks.LoadOrStore(flag, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	// 等锁期间可能已被其他请求刷新
+	if !force && globals.GoCache.Get(utils.GetHotCacheKey(flag), &resultResp) {
+		return resultResp
+	}
+
+	// 多实例共享缓存时只有一个实例请求上游, 其他实例等待其写入缓存, 超时后自己请求
+	if unlock, ok := globals.GoCache.Lock(utils.GetHotLockKey(flag), fetchLockExpired); ok {
+		defer unlock()
+	} else if waitHot(flag, &resultResp) {
+		return resultResp
+	}
+
+	resultResp = fetch()
+	// 上游改版后常常解析不到条目但不报错, 同样视为失败, 不覆盖之前的榜单
+	if resultResp.Code == 0 && len(resultResp.Data) == 0 {
+		resultResp = globals.GblResp{Code: 1, Err: "No items in upstream response"}
+	}
+	setProviderStatus(flag, resultResp)
+	if resultResp.Code != 0 {
+		if force {
+			return resultResp
+		}
+		return staleHot(flag, resultResp)
+	}
+
+	fillItemIds(flag, resultResp.Data)
+
+	globals.GoCache.Set(utils.GetHotCacheKey(flag), resultResp, globals.HotCacheExpired)
+	globals.GoCache.Set(utils.GetHotStaleKey(flag), resultResp, globals.HotStaleExpired)
+
+	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(flag), len(resultResp.Data))
+
+	saveSnapshot(flag, resultResp)
+
+	return resultResp
+}
+
+// staleHot 返回之前的榜单, 没有时返回 errResp
+func staleHot(flag string, errResp globals.GblResp) globals.GblResp {
+	var staleResp globals.GblResp
+	if globals.GoCache.Get(utils.GetHotStaleKey(flag), &staleResp) {
+		staleResp.Stale = true
+		return staleResp
+	}
+	return errResp
+}
+
+// 刷新锁的有效期, 以及未获取到锁时等待其他实例写入缓存的时间
+var fetchLockExpired = 30 * time.Second
+var fetchLockWait = 5 * time.Second
+
+// waitHot 轮询缓存直到其他实例写入或超时
+func waitHot(flag string, resultResp *globals.GblResp) bool {
+	deadline := time.Now().Add(fetchLockWait)
+	for time.Now().Before(deadline) {
+		time.Sleep(200 * time.Millisecond)
+		if globals.GoCache.Get(utils.GetHotCacheKey(flag), resultResp) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveSnapshot 保存快照并写入共享缓存, 供从实例同步
+func saveSnapshot(flag string, resultResp globals.GblResp) {
+	if globals.GoSnapshots == nil || len(resultResp.Data) == 0 {
+		return
+	}
+
+	now := time.Now()
+	snap := globals.Snapshot{
+		Id:        snapshots.NewId(now),
+		Platform:  flag,
+		FetchedAt: now,
+		Data:      resultResp.Data,
+	}
+	globals.GoCache.Set(utils.GetHotSnapshotKey(flag), snap, globals.HotStaleExpired)
+
+	recordSnapshot(snap)
+}
+
+// syncSnapshot 从实例读取 leader 最新写入的快照, 比本地新时记录
+func syncSnapshot(flag string) {
+	if globals.GoSnapshots == nil {
+		return
+	}
+
+	var snap globals.Snapshot
+	if !globals.GoCache.Get(utils.GetHotSnapshotKey(flag), &snap) {
+		return
+	}
+	if prev, found := globals.GoSnapshots.Latest(flag); found && !snap.FetchedAt.After(prev.FetchedAt) {
+		return
+	}
+
+	recordSnapshot(snap)
+}
+
+// recordSnapshot 保存快照, 更新搜索索引并评估关注规则, 与上一份快照不同时发布 board_updated 事件
+func recordSnapshot(snap globals.Snapshot) {
+	flag := snap.Platform
+	prev, _ := globals.GoSnapshots.Latest(flag)
+
+	err := globals.GoSnapshots.Save(snap)
+	if err != nil {
+		globals.GoLogger.Errorf("SAVE SNAPSHOT %s ERR %s", flag, err.Error())
+	}
+
+	search.Default.Add(snap)
+
+	// 多实例共用同一个数据库和译文缓存, 只由 leader 写入
+	if leader.IsLeader() {
+		sinks.Default.Add(snap)
+		translate.Default.Add(snap)
+	}
+
+	for _, a := range alerts.Default.Evaluate(prev, snap) {
+		a := a
+		events.Default.Publish(globals.Event{
+			Type:     globals.EventAlertFired,
+			Platform: flag,
+			Time:     a.FiredAt,
+			Alert:    &a,
+		})
+	}
+
+	diff := snapshots.Diff(prev, snap)
+	if diff.Empty() {
+		return
+	}
+
+	events.Default.Publish(globals.Event{
+		Type:     globals.EventBoardUpdated,
+		Platform: flag,
+		Time:     snap.FetchedAt,
+		Snapshot: &snap,
+		Diff:     &diff,
+	})
+}
+
+// 各榜单上游最近一次的状态
+var providerStatus sync.Map
+
+// setProviderStatus 记录上游状态, 可用性发生变化时发布 provider_status 事件
+func setProviderStatus(flag string, resultResp globals.GblResp) {
+	ok := resultResp.Code == 0
+	prev, found := providerStatus.Load(flag)
+	if found && prev.(globals.ProviderStatus).Ok == ok {
+		return
+	}
+
+	status := globals.ProviderStatus{Platform: flag, Ok: ok, Err: resultResp.Err, Since: time.Now()}
+	providerStatus.Store(flag, status)
+
+	// 首次成功不算状态变化
+	if !found && ok {
+		return
+	}
+
+	events.Default.Publish(globals.Event{
+		Type:     globals.EventProviderStatus,
+		Platform: flag,
+		Time:     status.Since,
+		Status:   &status,
+	})
+}
+
+// ProviderStatuses 各榜单上游最近一次的状态
+func ProviderStatuses() []globals.ProviderStatus {
+	var result []globals.ProviderStatus
+	for _, b := range Boards {
+		if v, found := providerStatus.Load(b.Flag); found {
+			result = append(result, v.(globals.ProviderStatus))
+		}
+	}
+	return result
+}
+
+// fillItemIds 为没有上游ID的条目按标题生成ID, 已有ID的补上平台前缀
+func fillItemIds(flag string, data []globals.GblRespData) {
+	for k := range data {
+		data[k].Id = utils.GetItemId(flag, data[k].Id, data[k].Title)
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/caches"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// useMemoryCache 测试期间使用新的进程内缓存
+func useMemoryCache(t *testing.T) {
+	t.Helper()
+	if globals.GoLogger == nil {
+		globals.GoLogger = logrus.New()
+		globals.GoLogger.SetOutput(io.Discard)
+	}
+	globals.GoCache = caches.NewMemory(cache.New(time.Hour, time.Hour))
+	t.Cleanup(func() { globals.GoCache = nil })
+}
+
+// denyElector 从不授予租约
+type denyElector struct{}
+
+func (denyElector) Acquire(ctx context.Context) (bool, error) { return false, nil }
+func (denyElector) Release(ctx context.Context) error         { return nil }
+
+// asFollower 测试期间本实例为从实例, 结束后恢复为单实例
+func asFollower(t *testing.T) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		leader.Run(ctx, denyElector{}, time.Hour)
+		close(done)
+	}()
+	for leader.IsLeader() {
+		time.Sleep(time.Millisecond)
+	}
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		leader.Standalone()
+	})
+}
+
+func TestFollowerReadsShared(t *testing.T) {
+	useMemoryCache(t)
+	leader.Standalone()
+	asFollower(t)
+
+	fetch := func() globals.GblResp {
+		t.Fatal("follower requested upstream")
+		return globals.GblResp{}
+	}
+
+	// leader 尚未写入时立即返回错误, 不等待
+	start := time.Now()
+	if resultResp := GetHot("follower-test", fetch); resultResp.Code != 1 || resultResp.Err != "Waiting for leader to refresh" {
+		t.Errorf("before leader refresh got %+v", resultResp)
+	}
+
+	shared := globals.GblResp{Succ: "ok", Data: []globals.GblRespData{{Title: "热搜", Pos: 1}}}
+	globals.GoCache.Set(utils.GetHotStaleKey("follower-test"), shared, time.Hour)
+	resultResp := GetHot("follower-test", fetch)
+	if len(resultResp.Data) != 1 || resultResp.Data[0].Title != "热搜" || resultResp.Stale {
+		t.Errorf("got %+v, want the shared board", resultResp)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("follower waited %s", d)
+	}
+}
@@ -1,34 +1,28 @@
 package api
 
 import (
-	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gin-gonic/gin"
 )
 
 func ItHomeHot(c *gin.Context) {
+	hotHandler(c, globals.ItHomeFlag, fetchItHomeHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.ItHomeFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.ItHomeFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchItHomeHot() globals.GblResp {
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://m.ithome.com/rankm/")
+	resp, err := upstream.Get("https://m.ithome.com/rankm/")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -36,7 +30,7 @@ func ItHomeHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
+		return resultResp
 	}
 
 	doc.Find(".rank-box .placeholder").Each(func(i int, s *goquery.Selection) {
@@ -71,9 +65,5 @@ func ItHomeHot(c *gin.Context) {
 		resultResp.Code = 0
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.ItHomeFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.ItHomeFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
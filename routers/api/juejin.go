@@ -4,11 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"strconv"
 
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 
 	"github.com/gin-gonic/gin"
 )
@@ -40,23 +39,18 @@ type JueJinDataAuthor struct {
 }
 
 func JueJinHot(c *gin.Context) {
+	hotHandler(c, globals.JueJinFlag, fetchJueJinHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.JueJinFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.JueJinFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchJueJinHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://api.juejin.cn/content_api/v1/content/article_rank?category_id=1&type=hot")
+	resp, err := upstream.Get("https://api.juejin.cn/content_api/v1/content/article_rank?category_id=1&type=hot")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -64,8 +58,7 @@ func JueJinHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp JueJinShellResponse
@@ -73,8 +66,7 @@ func JueJinHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -83,40 +75,34 @@ func JueJinHot(c *gin.Context) {
 	for k, v := range shellResp.Data {
 		var newData globals.GblRespData
 
+		newData.Id = v.Content.ContentId
 		newData.Title = v.Content.Title
 		newData.Desc = ""
 		newData.HotVal = strconv.Itoa(v.ContentCounter.HotRank)
 		newData.Pos = k + 1
 		newData.ToUrl = fmt.Sprintf("https://juejin.cn/post/%s", v.Content.ContentId)
+		newData.Author = v.Author.Name
+		newData.Views = int64(v.ContentCounter.View)
 
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.JueJinFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.JueJinFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
 
 func JueJinAIBox(c *gin.Context) {
+	hotHandler(c, globals.JueJinAIBoxFlag, fetchJueJinAIBox)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.JueJinAIBoxFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.JueJinAIBoxFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchJueJinAIBox() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://api.juejin.cn/content_api/v1/content/article_rank?category_id=6809637773935378440&type=hot")
+	resp, err := upstream.Get("https://api.juejin.cn/content_api/v1/content/article_rank?category_id=6809637773935378440&type=hot")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -124,8 +110,7 @@ func JueJinAIBox(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp JueJinShellResponse
@@ -133,8 +118,7 @@ func JueJinAIBox(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -143,18 +127,17 @@ func JueJinAIBox(c *gin.Context) {
 	for k, v := range shellResp.Data {
 		var newData globals.GblRespData
 
+		newData.Id = v.Content.ContentId
 		newData.Title = v.Content.Title
 		newData.Desc = ""
 		newData.HotVal = strconv.Itoa(v.ContentCounter.HotRank)
 		newData.Pos = k + 1
 		newData.ToUrl = fmt.Sprintf("https://juejin.cn/post/%s", v.Content.ContentId)
+		newData.Author = v.Author.Name
+		newData.Views = int64(v.ContentCounter.View)
 
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.JueJinAIBoxFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.JueJinAIBoxFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/upstream"
+)
+
+// stubUpstream 对所有上游请求返回同一个响应
+type stubUpstream string
+
+func (s stubUpstream) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(s))),
+		Request:    req,
+	}, nil
+}
+
+// useUpstream 在测试期间替换上游请求, 结束后恢复
+func useUpstream(t *testing.T, rt http.RoundTripper) {
+	t.Helper()
+	upstream.Use(rt)
+	t.Cleanup(func() { upstream.Use(http.DefaultTransport) })
+}
+
+// TestFetchMapping 上游响应中的作者、计数、发布时间等字段映射到输出的可选字段
+func TestFetchMapping(t *testing.T) {
+	cases := []struct {
+		name  string
+		fetch func() globals.GblResp
+		shell string
+		want  globals.GblRespData
+	}{
+		{
+			name:  "juejin",
+			fetch: fetchJueJinHot,
+			shell: `{"err_no":0,"data":[{"content":{"title":"Go 泛型实践","content_id":"7451"},"content_counter":{"hot_rank":3210,"view":45678},"author":{"name":"掘友"}}]}`,
+			want: globals.GblRespData{
+				Id: "7451", Title: "Go 泛型实践", HotVal: "3210", Pos: 1, ToUrl: "https://juejin.cn/post/7451",
+				Author: "掘友", Views: 45678,
+			},
+		},
+		{
+			name:  "hellogithub",
+			fetch: fetchHelloGithubHot,
+			shell: `{"success":true,"page":1,"data":[{"title":"hots","author":"turbo-uid","summary":"热榜聚合","clicks_total":1024,"item_id":"abc"}]}`,
+			want: globals.GblRespData{
+				Id: "abc", Title: "hots", Desc: "热榜聚合", HotVal: "1024", Pos: 1, ToUrl: "https://hellogithub.com/repository/abc",
+				Author: "turbo-uid", Views: 1024,
+			},
+		},
+		{
+			name:  "36kr",
+			fetch: fetchTo36krHot,
+			shell: `{"code":0,"data":{"hotRankList":[{"itemId":3100,"templateMaterial":{"widgetTitle":"新能源车出海","statRead":8800,"widgetImage":"https://img.36krcdn.com/a.png","itemId":3100,"authorName":"36氪编辑"}}]}}`,
+			want: globals.GblRespData{
+				Id: "3100", Title: "新能源车出海", HotVal: "8800", Pos: 1, ToUrl: "https://m.36kr.com/p/3100",
+				Icon: "https://img.36krcdn.com/a.png", Author: "36氪编辑",
+			},
+		},
+		{
+			name:  "thepaper",
+			fetch: fetchThepaperHot,
+			// 无法解析的发布时间不输出
+			shell: `{"resultCode":1,"data":{"hotNews":[{"name":"早间新闻","sharePic":"https://imgpai.thepaper.cn/a.png","contId":"2990","pubTimeNew":"01-15","praiseTimes":"1.2万","interactionNum":"356"}]}}`,
+			want: globals.GblRespData{
+				Id: "2990", Title: "早间新闻", Desc: "评论数: 356 点赞数: 1.2万 更新时间: 01-15", Pos: 1,
+				ToUrl: "https://www.thepaper.cn/newsDetail_forward_2990", Icon: "https://imgpai.thepaper.cn/a.png",
+				Comments: 356, Likes: 12000,
+			},
+		},
+		{
+			name:  "qq",
+			fetch: fetchQqHot,
+			shell: `{"ret":0,"idlist":[{"newslist":[{"abstract":"摘要内容","longtitle":"完整标题","shareUrl":"https://view.inews.qq.com/a/1","miniProShareImage":"https://inews.gtimg.com/a.png","hotEvent":{"title":"标题","hotScore":5000,"ranking":3,"is_top":0}}]}]}`,
+			want: globals.GblRespData{
+				Title: "标题", Desc: "完整标题", HotVal: "5000", Pos: 2, ToUrl: "https://view.inews.qq.com/a/1",
+				Icon: "https://inews.gtimg.com/a.png", Extra: globals.GblExtra{globals.ExtraAbstract: "摘要内容"},
+			},
+		},
+		{
+			name:  "csdn",
+			fetch: fetchCsdnHot,
+			shell: `{"code":200,"data":[{"articleTitle":"Rust 入门","articleDetailUrl":"https://blog.csdn.net/a/1","pcHotRankScore":"9527","nickName":"博主","picList":["https://img.csdn.net/a.png"]}]}`,
+			want: globals.GblRespData{
+				Title: "Rust 入门", HotVal: "9527", Pos: 1, ToUrl: "https://blog.csdn.net/a/1",
+				Icon: "https://img.csdn.net/a.png", Author: "博主",
+			},
+		},
+		{
+			name:  "dongchedi",
+			fetch: fetchDongCheDiHot,
+			shell: `{"status":0,"data":{"list":[{"title":"新车上市","count":777,"group_id":"7459679563439473203"}]}}`,
+			want: globals.GblRespData{
+				Id: "7459679563439473203", Title: "新车上市", HotVal: "777", Pos: 1,
+				ToUrl: "https://www.dongchedi.com/article/7459679563439473203",
+				Extra: globals.GblExtra{globals.ExtraGroupId: "7459679563439473203"},
+			},
+		},
+		{
+			name:  "douyin",
+			fetch: fetchDouyinHot,
+			shell: `{"status_code":0,"data":{"word_list":[{"word":"热搜","hot_value":100,"position":1,"label":3,"is_n1":true}]}}`,
+			want: globals.GblRespData{
+				Title: "热搜", HotVal: "100", Pos: 999, IsTop: 1,
+				ToUrl: "https://www.douyin.com/root/search/热搜?aid=8f302f2a-b661-4a1b-a88a-1027f4475461&type=general",
+				Lab:   "热", Badges: []string{"热", "n1"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			useUpstream(t, stubUpstream(tc.shell))
+
+			resp := tc.fetch()
+			if resp.Code != 0 {
+				t.Fatalf("code = %d, err = %q", resp.Code, resp.Err)
+			}
+			if len(resp.Data) != 1 {
+				t.Fatalf("got %d items, want 1", len(resp.Data))
+			}
+			if got := resp.Data[0]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestThepaperPublishedAt(t *testing.T) {
+	useUpstream(t, stubUpstream(`{"resultCode":1,"data":{"hotNews":[{"name":"a","contId":"1","pubTimeNew":"3小时前"}]}}`))
+
+	resp := fetchThepaperHot()
+	if len(resp.Data) != 1 {
+		t.Fatalf("got %d items, want 1", len(resp.Data))
+	}
+	got, err := time.Parse(time.RFC3339, resp.Data[0].PublishedAt)
+	if err != nil {
+		t.Fatalf("published_at %q: %s", resp.Data[0].PublishedAt, err)
+	}
+	if d := time.Since(got); d < 3*time.Hour-time.Minute || d > 3*time.Hour+time.Minute {
+		t.Errorf("published_at %s is %s ago, want about 3h", got, d)
+	}
+}
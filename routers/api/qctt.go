@@ -3,10 +3,9 @@ package api
 import (
 	"encoding/json"
 	"io/ioutil"
-	"net/http"
 
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,23 +19,18 @@ type QcttData struct {
 const QcttUrl string = "https://www.qctt.cn/channelDataList?page=1&id=1"
 
 func QcttHot(c *gin.Context) {
+	hotHandler(c, globals.QcttFlag, fetchQcttHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.QcttFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.QcttFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchQcttHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get(QcttUrl)
+	resp, err := upstream.Get(QcttUrl)
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -44,8 +38,7 @@ func QcttHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp []QcttData
@@ -53,8 +46,7 @@ func QcttHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -68,6 +60,7 @@ func QcttHot(c *gin.Context) {
 		newData.HotVal = ""
 		newData.Pos = k + 1
 		newData.ToUrl = "" // resourceLoc 暂不处理
+		newData.Author = v.Author
 
 		if len(v.PicList) > 0 {
 			newData.Icon = v.PicList[0]
@@ -75,9 +68,5 @@ func QcttHot(c *gin.Context) {
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.QcttFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.QcttFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
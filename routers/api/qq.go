@@ -3,12 +3,11 @@ package api
 import (
 	"encoding/json"
 	"io/ioutil"
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 )
 
 type QqShellResponse struct {
@@ -37,22 +36,17 @@ type QqHotEvent struct {
 }
 
 func QqHot(c *gin.Context) {
+	hotHandler(c, globals.QqFlag, fetchQqHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.QqFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.QqFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchQqHot() globals.GblResp {
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://r.inews.qq.com/gw/event/hot_ranking_list?page_size=51")
+	resp, err := upstream.Get("https://r.inews.qq.com/gw/event/hot_ranking_list?page_size=51")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -60,8 +54,7 @@ func QqHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	// 解析JSON响应
@@ -70,8 +63,7 @@ func QqHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -92,8 +84,12 @@ func QqHot(c *gin.Context) {
 	// 	IsTop int `json:"is_top"`
 	// }
 
-	listData := shellResp.Idlist[0]
-	for _, v := range listData.Newslist {
+	// 上游结构变化时 idlist 可能为空, 没有条目时由 loadHot 视为失败
+	var newslist []QqActualData
+	if len(shellResp.Idlist) > 0 {
+		newslist = shellResp.Idlist[0].Newslist
+	}
+	for _, v := range newslist {
 		if len(v.ShareUrl) > 0 && len(v.Longtitle) > 0 {
 			var newData globals.GblRespData
 
@@ -104,6 +100,7 @@ func QqHot(c *gin.Context) {
 			newData.ToUrl = v.ShareUrl
 			newData.Icon = v.MiniImage
 			newData.IsTop = 0
+			newData.Extra.Set(globals.ExtraAbstract, v.Desc)
 			// 兼容其他平台
 			if v.HotEvent.IsTop == 1 && v.HotEvent.Pos == 1 {
 				newData.IsTop = 1
@@ -115,9 +112,5 @@ func QqHot(c *gin.Context) {
 		}
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.QqFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.QqFlag), len(resultResp.Data))
-	// 将解析后的数据作为响应返回给客户端
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+)
+
+// 正在刷新的榜单, 上一次刷新未结束时跳过
+var refreshing sync.Map
+
+// RefreshLoop 定时刷新所有榜单, 使快照和推送不依赖客户端请求
+func RefreshLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		RefreshAll()
+		<-ticker.C
+	}
+}
+
+// RefreshAll 触发一次所有榜单的刷新, 缓存未过期的榜单不会请求上游; 从实例只同步 leader 保存的快照
+func RefreshAll() {
+	for _, b := range Boards {
+		if _, busy := refreshing.LoadOrStore(b.Flag, true); busy {
+			continue
+		}
+
+		go func(b Board) {
+			defer refreshing.Delete(b.Flag)
+
+			// 新当选的 leader 也需要先补上前任保存的快照
+			syncSnapshot(b.Flag)
+			if !leader.IsLeader() {
+				return
+			}
+
+			resultResp := GetHot(b.Flag, b.Fetch)
+			if resultResp.Code != 0 {
+				globals.GoLogger.Warnf("REFRESH %s ERR %s", b.Flag, resultResp.Err)
+			}
+		}(b)
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/search"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// Search 搜索全部快照中的标题和描述, 同一条目合并为一条结果
+// platforms 为逗号分隔的榜单, from/to 格式同 HotDiff, limit 默认 20
+func Search(c *gin.Context) {
+	var resultResp globals.SearchResp
+
+	q := search.Query{Q: c.Query("q")}
+	if q.Q == "" {
+		resultResp.Code = 1
+		resultResp.Err = "Missing q"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	if s := c.Query("platforms"); s != "" {
+		boards, unknown := ParsePlatforms(s)
+		if unknown != "" {
+			resultResp.Code = 1
+			resultResp.Err = "Unknown platform: " + unknown
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+		q.Platforms = map[string]bool{}
+		for _, b := range boards {
+			q.Platforms[b.Flag] = true
+		}
+	}
+
+	var err error
+	now := time.Now()
+	if s := c.Query("from"); s != "" {
+		if q.From, err = utils.ParseTime(s, now); err != nil {
+			resultResp.Code = 1
+			resultResp.Err = "Invalid from"
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+	}
+	if s := c.Query("to"); s != "" {
+		if q.To, err = utils.ParseTime(s, now); err != nil {
+			resultResp.Code = 1
+			resultResp.Err = "Invalid to"
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+	}
+
+	if q.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "20")); err != nil || q.Limit <= 0 {
+		q.Limit = 20
+	}
+	if q.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil || q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	hits, total, err := search.Default.Search(q, now)
+	if err != nil {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid q: " + err.Error()
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Total = total
+	resultResp.Data = hits
+
+	c.JSON(http.StatusOK, resultResp)
+}
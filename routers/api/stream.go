@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/events"
+	"github.com/turbo-uid/hots/globals"
+)
+
+const (
+	streamModeDiff     = "diff"
+	streamModeSnapshot = "snapshot"
+)
+
+// Stream 以 SSE 推送榜单变化
+// platforms 为逗号分隔的榜单, 为空表示全部; mode=diff(默认) 推送变化, mode=snapshot 推送完整榜单
+// 首次连接先推送各榜单当前快照, 携带 Last-Event-ID 重连时补发期间的事件, ID 来自其他实例或重启前时重新推送快照
+func Stream(c *gin.Context) {
+	var resultResp globals.GblResp
+
+	boards, unknown := ParsePlatforms(c.Query("platforms"))
+	if unknown != "" {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown platform: " + unknown
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	mode := c.DefaultQuery("mode", streamModeDiff)
+	if mode != streamModeDiff && mode != streamModeSnapshot {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid mode"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	flags := map[string]bool{}
+	for _, b := range boards {
+		flags[b.Flag] = true
+	}
+	filter := func(e globals.Event) bool {
+		return e.Type == globals.EventBoardUpdated && flags[e.Platform]
+	}
+
+	// 先订阅再补发, 避免遗漏; 重复的事件按序号跳过
+	sub := events.Default.Subscribe(64, filter)
+	defer events.Default.Unsubscribe(sub)
+
+	// 长连接不受 server WriteTimeout 限制
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	lastId := c.GetHeader("Last-Event-ID")
+	if lastId == "" {
+		lastId = c.Query("last_event_id")
+	}
+
+	var lastSeq uint64
+	resync := true
+	// 其他实例或重启前的ID无法续传
+	if seq, ok := events.Default.ParseEventId(lastId); ok {
+		if missed, ok := events.Default.Since(seq, filter); ok {
+			resync = false
+			lastSeq = seq
+			for _, e := range missed {
+				renderStreamEvent(c, mode, e)
+				lastSeq = e.Seq
+			}
+		}
+	}
+	if resync {
+		lastSeq = renderStreamSnapshots(c, boards)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(globals.StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	dropped := sub.Dropped()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprintf(c.Writer, ": heartbeat %d\n\n", time.Now().Unix())
+		case e := <-sub.C:
+			if e.Seq <= lastSeq {
+				continue
+			}
+			// 消费过慢丢过事件时, 差量已不连续, 改推完整快照
+			if n := sub.Dropped(); n != dropped {
+				dropped = n
+				lastSeq = renderStreamSnapshots(c, boards)
+				continue
+			}
+			renderStreamEvent(c, mode, e)
+			lastSeq = e.Seq
+		}
+		c.Writer.Flush()
+	}
+}
+
+func renderStreamEvent(c *gin.Context, mode string, e globals.Event) {
+	var data interface{} = e.Diff
+	if mode == streamModeSnapshot {
+		data = e.Snapshot
+	}
+
+	c.Render(-1, sse.Event{
+		Id:    events.Default.EventId(e.Seq),
+		Event: mode,
+		Data:  data,
+	})
+}
+
+// renderStreamSnapshots 推送各榜单最新快照, 事件ID对应当前序号, 返回该序号
+func renderStreamSnapshots(c *gin.Context, boards []Board) uint64 {
+	seq := events.Default.Seq()
+	if globals.GoSnapshots == nil {
+		return seq
+	}
+
+	for _, b := range boards {
+		snap, found := globals.GoSnapshots.Latest(b.Flag)
+		if !found {
+			continue
+		}
+		c.Render(-1, sse.Event{
+			Id:    events.Default.EventId(seq),
+			Event: streamModeSnapshot,
+			Retry: 3000,
+			Data:  snap,
+		})
+	}
+	return seq
+}
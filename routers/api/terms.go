@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/terms"
+)
+
+// Terms 热词, window 为统计窗口(默认 1h), baseline 为之前用于对比的时段(默认 24h)
+// 两者之和不超过快照的保留时长, 超出时缩短 baseline
+// platforms 为逗号分隔的榜单, top 默认 50, min 为最少出现的条目数(默认 2); 结果缓存 1 分钟
+func Terms(c *gin.Context) {
+	var resultResp globals.TermsResp
+
+	if globals.GoSnapshots == nil {
+		resultResp.Code = 1
+		resultResp.Err = "Snapshots are disabled"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	var opts terms.Options
+	var err error
+	if opts.Window, err = time.ParseDuration(c.DefaultQuery("window", "1h")); err != nil || opts.Window <= 0 || opts.Window > globals.SnapshotKeep {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid window"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+	if opts.Baseline, err = time.ParseDuration(c.DefaultQuery("baseline", "24h")); err != nil || opts.Baseline < 0 {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid baseline"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+	opts.Baseline = min(opts.Baseline, globals.SnapshotKeep-opts.Window)
+
+	boards, unknown := ParsePlatforms(c.Query("platforms"))
+	if unknown != "" {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown platform: " + unknown
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+	for _, b := range boards {
+		opts.Platforms = append(opts.Platforms, b.Flag)
+	}
+
+	if opts.Top, err = strconv.Atoi(c.DefaultQuery("top", "50")); err != nil || opts.Top <= 0 {
+		opts.Top = 50
+	}
+	if opts.MinCount, err = strconv.Atoi(c.DefaultQuery("min", "2")); err != nil || opts.MinCount <= 0 {
+		opts.MinCount = 2
+	}
+
+	cacheKey := fmt.Sprintf("terms_%s_%s_%s_%d_%d", opts.Window, opts.Baseline, strings.Join(opts.Platforms, ","), opts.Top, opts.MinCount)
+	if globals.GoCache.Get(cacheKey, &resultResp) {
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = terms.Trending(globals.GoSnapshots, opts, time.Now())
+
+	globals.GoCache.Set(cacheKey, resultResp, time.Minute)
+
+	c.JSON(http.StatusOK, resultResp)
+}
@@ -4,10 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/upstream"
 	"github.com/turbo-uid/hots/utils"
 )
 
@@ -31,22 +32,20 @@ type ThepaperList struct {
 }
 
 func ThepaperHot(c *gin.Context) {
+	hotHandler(c, globals.ThepaperFlag, fetchThepaperHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.ThepaperFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.ThepaperFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
+// 澎湃返回的日期为北京时间
+var thepaperZone = time.FixedZone("CST", 8*3600)
 
+func fetchThepaperHot() globals.GblResp {
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://cache.thepaper.cn/contentapi/wwwIndex/rightSidebar")
+	resp, err := upstream.Get("https://cache.thepaper.cn/contentapi/wwwIndex/rightSidebar")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -54,8 +53,7 @@ func ThepaperHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp ThepaperShellResponse
@@ -63,8 +61,7 @@ func ThepaperHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -73,6 +70,7 @@ func ThepaperHot(c *gin.Context) {
 	for k, v := range shellResp.Data.HotNews {
 		var newData globals.GblRespData
 
+		newData.Id = v.ContId
 		newData.Title = v.Title
 		newData.Desc = fmt.Sprintf("评论数: %s 点赞数: %s 更新时间: %s", v.InteractionNum, v.PraiseTimes, v.PubTimeNew)
 		newData.HotVal = ""
@@ -80,12 +78,14 @@ func ThepaperHot(c *gin.Context) {
 		newData.ToUrl = fmt.Sprintf("https://www.thepaper.cn/newsDetail_forward_%s", v.ContId)
 		newData.Lab = ""
 		newData.Icon = v.Icon
+		newData.Comments = utils.ParseCount(v.InteractionNum)
+		newData.Likes = utils.ParseCount(v.PraiseTimes)
+		// pubTimeNew 为 "3小时前" 这样的相对时间或北京时间的日期, 无法解析时不输出
+		if t, err := utils.ParseRelativeTime(v.PubTimeNew, time.Now().In(thepaperZone)); err == nil {
+			newData.PublishedAt = t.Format(time.RFC3339)
+		}
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.ThepaperFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.ThepaperFlag), len(resultResp.Data))
-	// 将解析后的数据作为响应返回给客户端
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
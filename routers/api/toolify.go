@@ -7,7 +7,7 @@ import (
 	"net/http"
 
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 
 	"github.com/gin-gonic/gin"
 )
@@ -33,53 +33,30 @@ type ToolifyDataList struct {
 	Date              string   `json:"date"`
 }
 
-type AIResp struct {
-	Succ string       `json:"succ"`
-	Err  string       `json:"err"`
-	Code int          `json:"code"`
-	Data []AIRespData `json:"data"`
-}
-
-type AIRespData struct {
-	Name          string   `json:"name"`
-	MonthlyVisits string   `json:"monthlyVisits"`
-	Growth        string   `json:"growth"`
-	GrowthRate    string   `json:"growthRate"`
-	Description   string   `json:"description"`
-	Tags          []string `json:"tags"`
-	Expanded      bool     `json:"expanded"`
-}
-
 var ToolifyUrl string = "https://www.toolify.ai/self-api/v1/top/month-top?page=1&per_page=50&direction=desc&order_by=growth"
 
 func ToolifyHot(c *gin.Context) {
+	hotHandler(c, globals.ToolifyFlag, fetchToolifyHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.ToolifyFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.ToolifyFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchToolifyHot() globals.GblResp {
 	// 统一输出结果
-	var resultResp AIResp
+	var resultResp globals.GblResp
 
-	client := &http.Client{}
+	client := upstream.Client
 
 	req, err := http.NewRequest("GET", ToolifyUrl, nil)
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error creating request"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error making GET request"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -87,8 +64,7 @@ func ToolifyHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp ToolifyShellResponse
@@ -96,32 +72,30 @@ func ToolifyHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
 	resultResp.Code = 0
 
-	for _, v := range shellResp.Data.Data {
-		var newData AIRespData
+	for k, v := range shellResp.Data.Data {
+		var newData globals.GblRespData
 
-		newData.Name = v.Name
-		newData.Description = v.Description
-		newData.Tags = v.Tags
-		newData.Expanded = false
-		newData.MonthlyVisits = fmtVisitedCount(v.MonthVisitedVount)
-		newData.Growth = fmt.Sprintf("+%s", fmtVisitedCount(v.Growth))
-		newData.GrowthRate = fmt.Sprintf("%.2f%%", v.GrowthRate*100)
+		newData.Title = v.Name
+		newData.Desc = v.Description
+		newData.HotVal = fmtVisitedCount(v.MonthVisitedVount)
+		newData.Pos = k + 1
+		newData.ToUrl = ""
+		newData.Extra.Set(globals.ExtraTags, v.Tags).
+			Set(globals.ExtraMonthlyVisits, v.MonthVisitedVount).
+			Set(globals.ExtraGrowth, fmt.Sprintf("+%s", fmtVisitedCount(v.Growth))).
+			Set(globals.ExtraGrowthRate, fmt.Sprintf("%.2f%%", v.GrowthRate*100)).
+			Set(globals.ExtraReleaseDate, v.Date)
 
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.ToolifyFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.ToolifyFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
 
 func fmtVisitedCount(num int) string {
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/webhooks"
+)
+
+// Webhooks 列出全部 Webhook, 不含 secret
+func Webhooks(c *gin.Context) {
+	var resultResp globals.WebhookListResp
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = webhooks.Default.List()
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// Webhook 查看单个 Webhook
+func Webhook(c *gin.Context) {
+	var resultResp globals.WebhookResp
+
+	hook, found := webhooks.Default.Get(c.Param("id"))
+	if !found {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown webhook"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = hook
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// AddWebhook 注册 Webhook, 返回的 secret 只出现这一次
+func AddWebhook(c *gin.Context) {
+	var resultResp globals.WebhookResp
+
+	var hook globals.Webhook
+	if err := c.ShouldBindJSON(&hook); err != nil {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid webhook: " + err.Error()
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	var flags []string
+	for _, v := range hook.Platforms {
+		b, ok := FindBoard(v)
+		if !ok {
+			resultResp.Code = 1
+			resultResp.Err = "Unknown platform: " + v
+			c.JSON(http.StatusOK, resultResp)
+			return
+		}
+		flags = append(flags, b.Flag)
+	}
+	hook.Platforms = flags
+
+	hook, err := webhooks.Default.Add(hook)
+	if err != nil {
+		resultResp.Code = 1
+		resultResp.Err = "Invalid webhook: " + err.Error()
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = hook
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+func DeleteWebhook(c *gin.Context) {
+	var resultResp globals.GblResp
+
+	if !webhooks.Default.Remove(c.Param("id")) {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown webhook"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// WebhookDeliveries 按时间倒序列出投递记录, limit 默认 50
+func WebhookDeliveries(c *gin.Context) {
+	var resultResp globals.DeliveryListResp
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = webhooks.Default.Deliveries(c.Param("id"), limit)
+
+	c.JSON(http.StatusOK, resultResp)
+}
+
+// ReplayDelivery 用原请求体重新投递, 返回新的投递记录
+func ReplayDelivery(c *gin.Context) {
+	var resultResp globals.DeliveryResp
+
+	delivery, found := webhooks.Default.Delivery(c.Param("delivery"))
+	if !found || delivery.WebhookId != c.Param("id") {
+		resultResp.Code = 1
+		resultResp.Err = "Unknown delivery"
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	delivery, err := webhooks.Default.Replay(delivery.Id)
+	if err != nil {
+		resultResp.Code = 1
+		resultResp.Err = err.Error()
+		c.JSON(http.StatusOK, resultResp)
+		return
+	}
+
+	resultResp.Succ = "ok"
+	resultResp.Code = 0
+	resultResp.Data = delivery
+
+	c.JSON(http.StatusOK, resultResp)
+}
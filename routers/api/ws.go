@@ -0,0 +1,394 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/turbo-uid/hots/events"
+	"github.com/turbo-uid/hots/globals"
+)
+
+const (
+	wsSendBuffer   = 64
+	wsWriteTimeout = 10 * time.Second
+	wsReadLimit    = 64 * 1024
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	// 与 CorsReq 一致, 允许所有来源
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsClient 一个 WebSocket 连接及其订阅
+type wsClient struct {
+	conn *websocket.Conn
+	send chan globals.WsMessage
+	done chan struct{}
+	once sync.Once
+
+	mu         sync.Mutex
+	platforms  map[string]bool
+	categories map[string]bool
+	keywords   map[string]bool
+}
+
+// WebSocket 在一个连接上按平台、分类、关键词订阅榜单变化
+// 客户端发送 subscribe/unsubscribe/ping, 服务端推送 snapshot、diff、alert、provider_status 等消息
+func WebSocket(c *gin.Context) {
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade 已经写入了错误响应
+		return
+	}
+
+	client := &wsClient{
+		conn:       conn,
+		send:       make(chan globals.WsMessage, wsSendBuffer),
+		done:       make(chan struct{}),
+		platforms:  map[string]bool{},
+		categories: map[string]bool{},
+		keywords:   map[string]bool{},
+	}
+
+	sub := events.Default.Subscribe(wsSendBuffer, nil)
+	defer events.Default.Unsubscribe(sub)
+
+	go client.writeLoop()
+	go client.readLoop()
+
+	dropped := sub.Dropped()
+	for {
+		select {
+		case <-client.done:
+			return
+		case e := <-sub.C:
+			// 消费过慢丢过事件时, 差量已不连续, 重新推送快照
+			if n := sub.Dropped(); n != dropped {
+				dropped = n
+				client.sendSnapshots(client.selectedBoards())
+			}
+			client.dispatch(e)
+		}
+	}
+}
+
+func (cl *wsClient) close(code int, reason string) {
+	cl.once.Do(func() {
+		msg := websocket.FormatCloseMessage(code, reason)
+		cl.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+		cl.conn.Close()
+		close(cl.done)
+	})
+}
+
+// enqueue 发送队列已满说明客户端消费不过来, 直接断开让其重连
+func (cl *wsClient) enqueue(msg globals.WsMessage) {
+	select {
+	case cl.send <- msg:
+	case <-cl.done:
+	default:
+		cl.close(websocket.CloseTryAgainLater, "slow consumer")
+	}
+}
+
+func (cl *wsClient) writeLoop() {
+	ping := time.NewTicker(globals.StreamHeartbeat)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-cl.done:
+			return
+		case msg := <-cl.send:
+			cl.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := cl.conn.WriteJSON(msg); err != nil {
+				cl.close(websocket.CloseGoingAway, "")
+				return
+			}
+		case <-ping.C:
+			if err := cl.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				cl.close(websocket.CloseGoingAway, "")
+				return
+			}
+		}
+	}
+}
+
+func (cl *wsClient) readLoop() {
+	pongWait := 2 * globals.StreamHeartbeat
+
+	cl.conn.SetReadLimit(wsReadLimit)
+	cl.conn.SetReadDeadline(time.Now().Add(pongWait))
+	cl.conn.SetPongHandler(func(string) error {
+		return cl.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := cl.conn.ReadMessage()
+		if err != nil {
+			// 客户端关闭、读超时或连接断开
+			cl.close(websocket.CloseNormalClosure, "")
+			return
+		}
+
+		var req globals.WsRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			cl.enqueue(globals.WsMessage{Type: globals.WsMsgError, Err: "Invalid message"})
+			continue
+		}
+		cl.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		switch req.Action {
+		case globals.WsActionPing:
+			cl.enqueue(globals.WsMessage{Type: globals.WsMsgPong})
+		case globals.WsActionSubscribe:
+			cl.subscribe(req)
+		case globals.WsActionUnsubscribe:
+			cl.unsubscribe(req)
+		default:
+			cl.enqueue(globals.WsMessage{Type: globals.WsMsgError, Err: "Unknown action: " + req.Action})
+		}
+	}
+}
+
+// subscribe 增加订阅, 校验名称和数量上限, 并推送新增榜单的当前快照
+func (cl *wsClient) subscribe(req globals.WsRequest) {
+	platforms, categories, keywords, errMsg := parseWsTopics(req)
+	if errMsg != "" {
+		cl.enqueue(globals.WsMessage{Type: globals.WsMsgError, Err: errMsg})
+		return
+	}
+
+	before := map[string]bool{}
+	for _, b := range cl.selectedBoards() {
+		before[b.Flag] = true
+	}
+
+	cl.mu.Lock()
+	total := len(cl.platforms) + len(cl.categories) + len(cl.keywords)
+	for _, v := range platforms {
+		if !cl.platforms[v] {
+			total++
+		}
+	}
+	for _, v := range categories {
+		if !cl.categories[v] {
+			total++
+		}
+	}
+	for _, v := range keywords {
+		if !cl.keywords[v] {
+			total++
+		}
+	}
+	if total > globals.WsMaxTopics {
+		cl.mu.Unlock()
+		cl.enqueue(globals.WsMessage{Type: globals.WsMsgError, Err: "Too many subscriptions"})
+		return
+	}
+	for _, v := range platforms {
+		cl.platforms[v] = true
+	}
+	for _, v := range categories {
+		cl.categories[v] = true
+	}
+	for _, v := range keywords {
+		cl.keywords[v] = true
+	}
+	cl.mu.Unlock()
+
+	cl.enqueue(globals.WsMessage{Type: globals.WsMsgAck, Data: cl.topics()})
+
+	var added []Board
+	for _, b := range cl.selectedBoards() {
+		if !before[b.Flag] {
+			added = append(added, b)
+		}
+	}
+	cl.sendSnapshots(added)
+}
+
+func (cl *wsClient) unsubscribe(req globals.WsRequest) {
+	platforms, categories, keywords, errMsg := parseWsTopics(req)
+	if errMsg != "" {
+		cl.enqueue(globals.WsMessage{Type: globals.WsMsgError, Err: errMsg})
+		return
+	}
+
+	cl.mu.Lock()
+	for _, v := range platforms {
+		delete(cl.platforms, v)
+	}
+	for _, v := range categories {
+		delete(cl.categories, v)
+	}
+	for _, v := range keywords {
+		delete(cl.keywords, v)
+	}
+	cl.mu.Unlock()
+
+	cl.enqueue(globals.WsMessage{Type: globals.WsMsgAck, Data: cl.topics()})
+}
+
+// parseWsTopics 平台名统一为标识, 关键词统一为小写
+func parseWsTopics(req globals.WsRequest) (platforms, categories, keywords []string, errMsg string) {
+	for _, v := range req.Platforms {
+		b, ok := FindBoard(v)
+		if !ok {
+			return nil, nil, nil, "Unknown platform: " + v
+		}
+		platforms = append(platforms, b.Flag)
+	}
+	for _, v := range req.Categories {
+		if len(FindCategory(v)) == 0 {
+			return nil, nil, nil, "Unknown category: " + v
+		}
+		categories = append(categories, v)
+	}
+	for _, v := range req.Keywords {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v != "" {
+			keywords = append(keywords, v)
+		}
+	}
+	return
+}
+
+// topics 当前订阅, 用于 ack
+func (cl *wsClient) topics() globals.WsRequest {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	result := globals.WsRequest{Action: globals.WsActionSubscribe}
+	for k := range cl.platforms {
+		result.Platforms = append(result.Platforms, k)
+	}
+	for k := range cl.categories {
+		result.Categories = append(result.Categories, k)
+	}
+	for k := range cl.keywords {
+		result.Keywords = append(result.Keywords, k)
+	}
+	sort.Strings(result.Platforms)
+	sort.Strings(result.Categories)
+	sort.Strings(result.Keywords)
+	return result
+}
+
+// selected 只订阅了关键词时匹配所有平台
+func (cl *wsClient) selected(flag string) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.platforms[flag] {
+		return true
+	}
+	if b, ok := FindBoard(flag); ok && cl.categories[b.Category] {
+		return true
+	}
+	return len(cl.platforms) == 0 && len(cl.categories) == 0 && len(cl.keywords) > 0
+}
+
+func (cl *wsClient) selectedBoards() []Board {
+	var result []Board
+	for _, b := range Boards {
+		if cl.selected(b.Flag) {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// match 未订阅关键词时全部匹配
+func (cl *wsClient) match(texts ...string) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if len(cl.keywords) == 0 {
+		return true
+	}
+	for _, t := range texts {
+		t = strings.ToLower(t)
+		for k := range cl.keywords {
+			if strings.Contains(t, k) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cl *wsClient) filterItems(data []globals.GblRespData) []globals.GblRespData {
+	var result []globals.GblRespData
+	for _, v := range data {
+		if cl.match(v.Title, v.Desc) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func (cl *wsClient) filterEntries(list []globals.DiffEntry) []globals.DiffEntry {
+	var result []globals.DiffEntry
+	for _, v := range list {
+		if cl.match(v.Title) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func (cl *wsClient) sendSnapshots(boards []Board) {
+	if globals.GoSnapshots == nil {
+		return
+	}
+
+	seq := events.Default.Seq()
+	for _, b := range boards {
+		snap, found := globals.GoSnapshots.Latest(b.Flag)
+		if !found {
+			continue
+		}
+		snap.Data = cl.filterItems(snap.Data)
+		if len(snap.Data) == 0 {
+			continue
+		}
+		cl.enqueue(globals.WsMessage{Type: globals.WsMsgSnapshot, Seq: seq, Platform: b.Flag, Data: snap})
+	}
+}
+
+// dispatch 将事件转换为消息, 按订阅和关键词过滤
+func (cl *wsClient) dispatch(e globals.Event) {
+	if !cl.selected(e.Platform) {
+		return
+	}
+
+	switch e.Type {
+	case globals.EventBoardUpdated:
+		diff := *e.Diff
+		diff.Entered = cl.filterEntries(diff.Entered)
+		diff.Left = cl.filterEntries(diff.Left)
+		diff.MovedUp = cl.filterEntries(diff.MovedUp)
+		diff.MovedDown = cl.filterEntries(diff.MovedDown)
+		diff.HeatChanged = cl.filterEntries(diff.HeatChanged)
+		if diff.Empty() {
+			return
+		}
+		cl.enqueue(globals.WsMessage{Type: globals.WsMsgDiff, Seq: e.Seq, Platform: e.Platform, Data: diff})
+	case globals.EventAlertFired:
+		if !cl.match(e.Alert.Item.Title, e.Alert.Item.Desc) {
+			return
+		}
+		cl.enqueue(globals.WsMessage{Type: globals.WsMsgAlert, Seq: e.Seq, Platform: e.Platform, Data: e.Alert})
+	case globals.EventProviderStatus:
+		cl.enqueue(globals.WsMessage{Type: globals.WsMsgProviderStatus, Seq: e.Seq, Platform: e.Platform, Data: e.Status})
+	}
+}
@@ -4,11 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 )
 
 type Wy163ShellResponse struct {
@@ -30,22 +29,17 @@ type Wy163List struct {
 }
 
 func Wy163Hot(c *gin.Context) {
+	hotHandler(c, globals.Wy163Flag, fetchWy163Hot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.Wy163Flag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.Wy163Flag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchWy163Hot() globals.GblResp {
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://gw.m.163.com/search/api/v2/hot-search")
+	resp, err := upstream.Get("https://gw.m.163.com/search/api/v2/hot-search")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -53,8 +47,7 @@ func Wy163Hot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp Wy163ShellResponse
@@ -62,8 +55,7 @@ func Wy163Hot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -81,9 +73,5 @@ func Wy163Hot(c *gin.Context) {
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.Wy163Flag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.Wy163Flag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
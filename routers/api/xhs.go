@@ -7,7 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 )
 
 type XhsShellResponse struct {
@@ -30,24 +30,19 @@ type XhsItemsList struct {
 }
 
 func XhsHot(c *gin.Context) {
+	hotHandler(c, globals.XhsFlag, fetchXhsHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.XhsFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.XhsFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchXhsHot() globals.GblResp {
 	var resultResp globals.GblResp
 
-	client := &http.Client{}
+	client := upstream.Client
 
 	req, err := http.NewRequest("GET", "https://edith.xiaohongshu.com/api/sns/v1/search/hot_list", nil)
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error creating request"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	// 设置请求头部
 	// req.Header.Set("x-legacy-fid", " 1695182528-0-0-63b29d709954a1bb8c8733eb2fb58f29")
@@ -73,8 +68,7 @@ func XhsHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error making GET request"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	defer resp.Body.Close()
@@ -83,8 +77,7 @@ func XhsHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	var shellResp XhsShellResponse
@@ -92,8 +85,7 @@ func XhsHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to parse JSON"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	resultResp.Succ = "ok"
@@ -116,9 +108,5 @@ func XhsHot(c *gin.Context) {
 		resultResp.Data = append(resultResp.Data, newData)
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.XhsFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.XhsFlag), len(resultResp.Data))
-	// 将解析后的数据作为响应返回给客户端
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
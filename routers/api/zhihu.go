@@ -3,7 +3,6 @@ package api
 import (
 	"encoding/json"
 	"io/ioutil"
-	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -11,7 +10,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gin-gonic/gin"
 	"github.com/turbo-uid/hots/globals"
-	"github.com/turbo-uid/hots/utils"
+	"github.com/turbo-uid/hots/upstream"
 )
 
 type ZhihuShellResponse struct {
@@ -64,16 +63,23 @@ type ZhHotLink struct {
 	Text string `json:"url"`
 }
 
+// 页面版不走缓存, 直接返回最新结果
 func ZhihuByHtmlHot(c *gin.Context) {
+	resultResp := fetchZhihuByHtmlHot()
+	fillItemIds(globals.ZhihuFlag, resultResp.Data)
+
+	renderHot(c, resultResp)
+}
+
+func fetchZhihuByHtmlHot() globals.GblResp {
 	// 统一输出结果
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://www.zhihu.com/billboard")
+	resp, err := upstream.Get("https://www.zhihu.com/billboard")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -81,7 +87,7 @@ func ZhihuByHtmlHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to read response body"
-		c.JSON(http.StatusOK, resultResp)
+		return resultResp
 	}
 
 	doc.Find(".Card .HotList-item").Each(func(i int, s *goquery.Selection) {
@@ -127,27 +133,21 @@ func ZhihuByHtmlHot(c *gin.Context) {
 		resultResp.Code = 0
 	}
 
-	// 将解析后的数据作为响应返回给客户端
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
 
 func ZhihuByJsonHot(c *gin.Context) {
+	hotHandler(c, globals.ZhihuFlag, fetchZhihuByJsonHot)
+}
 
-	if cacheResult, found := globals.GoCache.Get(utils.GetHotCacheKey(globals.ZhihuFlag)); found {
-		globals.GoLogger.Infof("API GET GCACHE %s", utils.GetHotCacheKey(globals.ZhihuFlag))
-
-		c.JSON(http.StatusOK, cacheResult)
-		return
-	}
-
+func fetchZhihuByJsonHot() globals.GblResp {
 	var resultResp globals.GblResp
 
-	resp, err := http.Get("https://www.zhihu.com/billboard")
+	resp, err := upstream.Get("https://www.zhihu.com/billboard")
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Failed to fetch data"
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 	defer resp.Body.Close()
 
@@ -155,8 +155,7 @@ func ZhihuByJsonHot(c *gin.Context) {
 	if err != nil {
 		resultResp.Code = 1
 		resultResp.Err = "Error reading the response body: " + err.Error()
-		c.JSON(http.StatusOK, resultResp)
-		return
+		return resultResp
 	}
 
 	rex := regexp.MustCompile(`<script id="js-initialData" type="text\/json">(.*?)<\/script>`)
@@ -172,8 +171,7 @@ func ZhihuByJsonHot(c *gin.Context) {
 		if err != nil {
 			resultResp.Code = 1
 			resultResp.Err = "Failed to parse JSON: " + err.Error()
-			c.JSON(http.StatusOK, resultResp)
-			return
+			return resultResp
 		}
 
 		for k, v := range shellResp.InitialState.Topstory.HotList {
@@ -198,9 +196,5 @@ func ZhihuByJsonHot(c *gin.Context) {
 		resultResp.Err = "No match found in the fetched HTML content."
 	}
 
-	globals.GoCache.Set(utils.GetHotCacheKey(globals.ZhihuFlag), resultResp, globals.HotCacheExpired)
-
-	globals.GoLogger.Infof("API SET GCACHE %s DATA LEN %d", utils.GetHotCacheKey(globals.ZhihuFlag), len(resultResp.Data))
-
-	c.JSON(http.StatusOK, resultResp)
+	return resultResp
 }
@@ -0,0 +1,242 @@
+package routers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/openapi"
+	"github.com/turbo-uid/hots/routers/api"
+	"github.com/turbo-uid/hots/translate"
+)
+
+// apiDocument 由已注册的路由生成 OpenAPI 文档, 榜单、分类等取值按当前注册的榜单生成
+func apiDocument(r *gin.Engine) *openapi.Document {
+	var names, categories []string
+	seen := map[string]bool{}
+	for _, b := range api.Boards {
+		names = append(names, b.Name)
+		if !seen[b.Category] {
+			seen[b.Category] = true
+			categories = append(categories, b.Category)
+		}
+	}
+	// 榜单参数同样接受标识, 如 bilibili
+	known := map[string]bool{}
+	for _, v := range names {
+		known[v] = true
+	}
+	for _, b := range api.Boards {
+		if !known[b.Flag] {
+			known[b.Flag] = true
+			names = append(names, b.Flag)
+		}
+	}
+
+	var (
+		platform  = openapi.Path("platform", "榜单路由名, 也可以使用标识, 如 bilibili", openapi.String(names...))
+		platforms = openapi.Query("platforms", "逗号分隔的榜单, 为空表示全部", openapi.List(names...))
+		category  = openapi.Query("category", "榜单分类", openapi.String(categories...))
+		lang      = openapi.Query("lang", "标题翻译的目标语言, 需配置翻译", openapi.String(translate.Default.Langs()...))
+		format    = openapi.Query("format", "输出格式, 默认按 Accept 协商为 json", openapi.String("json", "csv", "ndjson", "markdown", "html"))
+		top       = openapi.Query("top", "每个榜单取前 n 条, 默认 10", openapi.Integer())
+		from      = openapi.Query("from", "起始时间: 快照ID、Unix 时间戳、RFC3339 或 24h 这样的相对时长", openapi.String())
+		to        = openapi.Query("to", "结束时间, 格式同 from", openapi.String())
+		limit     = openapi.Query("limit", "最多返回的数量", openapi.Integer())
+		id        = openapi.Path("id", "", openapi.String())
+	)
+	tables := []string{"text/csv", "application/x-ndjson", "text/markdown", "text/html"}
+	feeds := []string{"application/rss+xml", "application/atom+xml", "application/feed+json"}
+
+	docs := map[string]openapi.Route{
+		"GET /openapi.json": {Hidden: true},
+		"GET /docs":         {Hidden: true},
+
+		"GET /api/aggregate": {
+			Tag: "hot", Summary: "多个榜单合并输出", Description: "platforms 和 category 都为空时合并全部榜单, 条目的 platform 标明来源",
+			Params: []openapi.Parameter{platforms, category, top, lang, format},
+			Resp:   globals.GblResp{}, Alt: tables,
+		},
+		"GET /api/hot/:platform/diff": {
+			Tag: "history", Summary: "对比两份快照", Description: "to 为空时对比当前榜单",
+			Params: []openapi.Parameter{platform, openapi.Required("since", "对比的起点, 格式同 from", openapi.String()), to},
+			Resp:   globals.DiffResp{},
+		},
+		"GET /api/hot/:platform/history": {
+			Tag: "history", Summary: "历史快照", Description: "按时间升序, 表格格式下每个条目一行",
+			Params: []openapi.Parameter{platform, from, to, openapi.Query("limit", "最近的 n 份快照, 默认 20", openapi.Integer()), format},
+			Resp:   globals.SnapshotListResp{}, Alt: tables,
+		},
+		"GET /api/stream": {
+			Tag: "stream", Summary: "SSE 推送榜单变化",
+			Description: "事件名为 diff 或 snapshot, data 分别为 BoardDiff 和 Snapshot; 携带 Last-Event-ID 重连时补发期间的事件, ID 来自其他实例或重启前时重新推送快照",
+			Params: []openapi.Parameter{
+				platforms,
+				openapi.Query("mode", "diff 推送变化, snapshot 推送完整榜单", openapi.String("diff", "snapshot")),
+				openapi.Query("last_event_id", "同 Last-Event-ID 请求头", openapi.String()),
+			},
+			Resp: globals.GblResp{}, Alt: []string{"text/event-stream"},
+		},
+		"GET /api/ws": {
+			Tag: "stream", Summary: "WebSocket 订阅",
+			Description: "客户端发送 WsRequest, 服务端推送 WsMessage; 浏览器中用 ?api_key= 认证",
+			Upgrade:     true,
+		},
+
+		"GET /api/watch-rules":        {Tag: "alerts", Summary: "全部关注规则", Resp: globals.WatchRuleListResp{}},
+		"POST /api/watch-rules":       {Tag: "alerts", Summary: "新增关注规则", Body: globals.WatchRule{}, Resp: globals.WatchRuleResp{}},
+		"GET /api/watch-rules/:id":    {Tag: "alerts", Summary: "查看关注规则", Params: []openapi.Parameter{id}, Resp: globals.WatchRuleResp{}},
+		"DELETE /api/watch-rules/:id": {Tag: "alerts", Summary: "删除关注规则", Params: []openapi.Parameter{id}, Resp: globals.GblResp{}},
+		"GET /api/alerts": {
+			Tag: "alerts", Summary: "告警列表", Description: "按时间倒序",
+			Params: []openapi.Parameter{
+				openapi.Query("rule", "规则ID", openapi.String()),
+				openapi.Query("platform", "榜单", openapi.String(names...)),
+				openapi.Query("since", "格式同 from", openapi.String()),
+				openapi.Query("limit", "默认 50", openapi.Integer()),
+			},
+			Resp: globals.AlertListResp{},
+		},
+
+		"GET /api/search": {
+			Tag: "search", Summary: "搜索历史快照", Description: "同一条目的多次上榜合并为一条结果",
+			Params: []openapi.Parameter{
+				openapi.Required("q", "关键词", openapi.String()),
+				platforms, from, to,
+				openapi.Query("limit", "默认 20", openapi.Integer()),
+				openapi.Query("offset", "", openapi.Integer()),
+			},
+			Resp: globals.SearchResp{},
+		},
+		"GET /api/terms": {
+			Tag: "search", Summary: "热词",
+			Params: []openapi.Parameter{
+				openapi.Query("window", "统计窗口, 默认 1h, 最长为快照保留时长", openapi.String()),
+				openapi.Query("baseline", "用于对比的之前时段, 默认 24h, 与 window 之和超出快照保留时长时缩短", openapi.String()),
+				platforms,
+				openapi.Query("top", "默认 50", openapi.Integer()),
+				openapi.Query("min", "最少出现的条目数, 默认 2", openapi.Integer()),
+			},
+			Resp: globals.TermsResp{},
+		},
+
+		"GET /api/digests": {
+			Tag: "digests", Summary: "摘要列表",
+			Params: []openapi.Parameter{openapi.Query("period", "", openapi.String(globals.DigestDaily, globals.DigestWeekly)), limit},
+			Resp:   globals.DigestListResp{},
+		},
+		"POST /api/digests": {
+			Tag: "digests", Summary: "立即生成一期摘要",
+			Params: []openapi.Parameter{
+				openapi.Query("period", "默认 daily", openapi.String(globals.DigestDaily, globals.DigestWeekly)),
+				openapi.Query("push", "同时推送到通知渠道", openapi.Boolean()),
+			},
+			Resp: globals.DigestResp{},
+		},
+		"GET /api/digests/:id": {
+			Tag: "digests", Summary: "查看摘要",
+			Params: []openapi.Parameter{id, openapi.Query("format", "", openapi.String("json", "markdown", "html"))},
+			Resp:   globals.DigestResp{}, Alt: []string{"text/markdown", "text/html"},
+		},
+
+		"GET /api/webhooks": {Tag: "webhooks", Summary: "全部 Webhook", Resp: globals.WebhookListResp{}},
+		"POST /api/webhooks": {
+			Tag: "webhooks", Summary: "新增 Webhook", Description: "返回的 secret 只在创建时可见; 回调地址不能是内网地址, 除非在 WEBHOOK_ALLOW_NETS 中",
+			Body: globals.Webhook{}, Resp: globals.WebhookResp{},
+		},
+		"GET /api/webhooks/:id":    {Tag: "webhooks", Summary: "查看 Webhook", Params: []openapi.Parameter{id}, Resp: globals.WebhookResp{}},
+		"DELETE /api/webhooks/:id": {Tag: "webhooks", Summary: "删除 Webhook", Params: []openapi.Parameter{id}, Resp: globals.GblResp{}},
+		"GET /api/webhooks/:id/deliveries": {
+			Tag: "webhooks", Summary: "投递记录",
+			Params: []openapi.Parameter{id, openapi.Query("limit", "默认 50", openapi.Integer())},
+			Resp:   globals.DeliveryListResp{},
+		},
+		"POST /api/webhooks/:id/deliveries/:delivery/replay": {
+			Tag: "webhooks", Summary: "重新投递",
+			Params: []openapi.Parameter{id, openapi.Path("delivery", "投递ID", openapi.String())},
+			Resp:   globals.DeliveryResp{},
+		},
+
+		"POST /api/admin/refresh": {
+			Tag: "admin", Summary: "忽略缓存立即刷新榜单", Description: "只能在 leader 上执行",
+			Params: []openapi.Parameter{openapi.Query("platform", "逗号分隔的榜单, 为空表示全部", openapi.List(names...))},
+			Resp:   globals.AdminResultResp{},
+		},
+		"POST /api/admin/reload": {Tag: "admin", Summary: "重新加载配置文件", Resp: globals.AdminResultResp{}},
+		"GET /api/admin/keys":    {Tag: "admin", Summary: "API Key 列表", Resp: globals.ApiKeyListResp{}},
+		"POST /api/admin/keys": {
+			Tag: "admin", Summary: "创建 API Key", Description: "返回的 key 只在创建时可见; 只能在 leader 上执行",
+			Body: struct {
+				Name string `json:"name"`
+				Role string `json:"role" enum:"read,admin"`
+			}{},
+			Resp: globals.ApiKeyResp{},
+		},
+		"DELETE /api/admin/keys/:id": {Tag: "admin", Summary: "吊销 API Key", Description: "只能在 leader 上执行", Params: []openapi.Parameter{id}, Resp: globals.GblResp{}},
+
+		"GET /feed/:name": {
+			Tag: "feed", Summary: "榜单订阅源", Description: "all 为全部榜单的聚合",
+			Params: []openapi.Parameter{openapi.Path("name", "榜单加扩展名, 如 weibo.rss、all.atom、bili.json", openapi.String()), top},
+			Alt:    feeds,
+		},
+		"GET /feed/category/:name": {
+			Tag: "feed", Summary: "分类订阅源",
+			Params: []openapi.Parameter{openapi.Path("name", "分类加扩展名, 如 tech.rss", openapi.String()), top},
+			Alt:    feeds,
+		},
+	}
+
+	// 每个榜单一个路由, 票房榜用 t 区分总票房和单日票房
+	for _, ri := range r.Routes() {
+		if ri.Method != "GET" || !strings.HasPrefix(ri.Path, "/api/hot/") || strings.Contains(ri.Path, ":") {
+			continue
+		}
+		name := strings.TrimPrefix(ri.Path, "/api/hot/")
+		route := openapi.Route{
+			Tag: "hot", Summary: name + " 热榜", Description: "上游失败时返回之前的榜单, 此时 stale 为 true",
+			Params: []openapi.Parameter{lang, format},
+			Resp:   globals.GblResp{}, Alt: tables,
+		}
+		if b, ok := api.FindBoard(strings.Split(name, "/")[0]); ok {
+			route.Summary += ", 分类 " + b.Category
+		}
+		if name == "endata" {
+			route.Params = append([]openapi.Parameter{openapi.Query("t", "m 为内地总票房榜(默认), s 为单日票房榜", openapi.String("m", "s"))}, route.Params...)
+		}
+		docs[ri.Method+" "+ri.Path] = route
+	}
+
+	// 未单独声明的按路径前缀确定认证要求, /api 下修改数据的接口都需要管理权限
+	for k, route := range docs {
+		switch {
+		case route.Auth != openapi.AuthNone:
+		case strings.Contains(k, " /api/admin/"), !strings.HasPrefix(k, "GET ") && strings.Contains(k, " /api/"):
+			route.Auth = openapi.AuthAdmin
+		case strings.Contains(k, " /api/"), strings.Contains(k, " /feed/"):
+			route.Auth = openapi.AuthKey
+		}
+		docs[k] = route
+	}
+
+	return openapi.Build(openapi.Config{
+		Info: openapi.Info{
+			Title:       "hots",
+			Version:     "1.0",
+			Description: "各平台热榜接口。所有 JSON 响应都是 {succ, err, code, data}, code 为 0 表示成功, 业务错误同样返回 HTTP 200。",
+		},
+		Tags: []openapi.Tag{
+			{Name: "hot", Description: "榜单"},
+			{Name: "history", Description: "快照与对比, 默认保存在内存, 设置 SNAPSHOT_DIR 后落盘"},
+			{Name: "stream", Description: "实时推送"},
+			{Name: "search", Description: "搜索与热词"},
+			{Name: "alerts", Description: "关注规则与告警"},
+			{Name: "digests", Description: "摘要"},
+			{Name: "webhooks", Description: "Webhook"},
+			{Name: "admin", Description: "管理接口"},
+			{Name: "feed", Description: "RSS、Atom、JSON Feed"},
+		},
+		DefaultResp: globals.GblResp{},
+		KeyOptional: !globals.ApiKeyRequired,
+		Extra:       []interface{}{globals.WsRequest{}, globals.WsMessage{}, globals.Event{}},
+	}, r.Routes(), docs)
+}
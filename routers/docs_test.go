@@ -0,0 +1,136 @@
+package routers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/translate"
+)
+
+var pathParamRex = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// document 测试用到的 OpenAPI 文档字段
+type document struct {
+	Paths map[string]map[string]struct {
+		Parameters []struct {
+			Name   string `json:"name"`
+			In     string `json:"in"`
+			Schema struct {
+				Enum []string `json:"enum"`
+			} `json:"schema"`
+		} `json:"parameters"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]interface{} `json:"schemas"`
+	} `json:"components"`
+}
+
+func getDocument(t *testing.T, r *gin.Engine) (document, interface{}) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	var doc document
+	var raw interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal(w.Body.Bytes(), &raw)
+	return doc, raw
+}
+
+func enum(doc document, path, method, param string) []string {
+	for _, p := range doc.Paths[path][method].Parameters {
+		if p.Name == param {
+			return p.Schema.Enum
+		}
+	}
+	return nil
+}
+
+// refs 文档中全部 $ref
+func refs(v interface{}) []string {
+	var result []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if s, ok := child.(string); ok && k == "$ref" {
+				result = append(result, s)
+				continue
+			}
+			result = append(result, refs(child)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			result = append(result, refs(child)...)
+		}
+	}
+	return result
+}
+
+func TestApiDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+
+	r := InitRouter()
+	doc, raw := getDocument(t, r)
+
+	// 每个路由都有对应的操作
+	for _, ri := range r.Routes() {
+		if ri.Path == "/openapi.json" || ri.Path == "/docs" {
+			continue
+		}
+		p := pathParamRex.ReplaceAllString(ri.Path, "{$1}")
+		if _, ok := doc.Paths[p][strings.ToLower(ri.Method)]; !ok {
+			t.Errorf("%s %s has no operation", ri.Method, ri.Path)
+		}
+	}
+
+	// $ref 都指向已定义的 schema
+	list := refs(raw)
+	if len(list) == 0 {
+		t.Fatal("no $ref found")
+	}
+	for _, ref := range list {
+		name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+		if _, defined := doc.Components.Schemas[name]; !ok || !defined {
+			t.Errorf("unresolved $ref %s", ref)
+		}
+	}
+
+	// 榜单参数同时列出路由名和标识
+	platforms := strings.Join(enum(doc, "/api/hot/{platform}/history", "get", "platform"), ",")
+	for _, v := range []string{"bili", globals.BiliFlag, "endata-s", globals.EnDataSFlag} {
+		if !strings.Contains(","+platforms+",", ","+v+",") {
+			t.Errorf("platform enum missing %s: %s", v, platforms)
+		}
+	}
+
+	// 重新加载翻译配置后 lang 取值随之更新
+	if got := enum(doc, "/api/aggregate", "get", "lang"); len(got) != 0 {
+		t.Fatalf("lang enum = %v before configuring", got)
+	}
+	err := translate.Default.Configure(translate.Config{
+		Backends:  []translate.BackendConfig{{Name: "libre", Type: translate.TypeLibreTranslate, BaseUrl: "http://127.0.0.1:1"}},
+		Languages: []translate.Language{{Lang: "en", Backend: "libre"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { translate.Default.Configure(translate.Config{}) })
+	doc, _ = getDocument(t, r)
+	if got := enum(doc, "/api/aggregate", "get", "lang"); len(got) != 1 || got[0] != "en" {
+		t.Errorf("lang enum = %v after reload, want [en]", got)
+	}
+}
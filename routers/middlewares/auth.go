@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/apikeys"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// RequestKey 依次从 X-API-Key、Authorization: Bearer 和 ?api_key= 读取 Key
+// 浏览器中的 EventSource、WebSocket 无法设置请求头, 只能使用查询参数
+func RequestKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return c.Query("api_key")
+}
+
+func isAdminToken(key string) bool {
+	return globals.AdminToken != "" && subtle.ConstantTimeCompare([]byte(key), []byte(globals.AdminToken)) == 1
+}
+
+// ApiKey ApiKeyRequired 为 true 时要求有效的 API Key, ADMIN_TOKEN 同样可用
+func ApiKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !globals.ApiKeyRequired {
+			c.Next()
+			return
+		}
+
+		key := RequestKey(c)
+		if _, ok := apikeys.Default.Check(key); ok || isAdminToken(key) {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, globals.GblResp{Code: 1, Err: "Invalid api key"})
+	}
+}
+
+// Admin 管理接口要求 ADMIN_TOKEN 或 admin 权限的 API Key; 两者都没有配置时管理接口不可用
+func Admin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if globals.AdminToken == "" && !apikeys.Default.HasAdmin() {
+			c.AbortWithStatusJSON(http.StatusForbidden, globals.GblResp{Code: 1, Err: "Admin API is disabled"})
+			return
+		}
+
+		key := RequestKey(c)
+		if isAdminToken(key) {
+			c.Next()
+			return
+		}
+		if k, ok := apikeys.Default.Check(key); ok && k.Role == globals.ApiKeyRoleAdmin {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, globals.GblResp{Code: 1, Err: "Invalid admin token"})
+	}
+}
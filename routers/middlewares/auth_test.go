@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/apikeys"
+	"github.com/turbo-uid/hots/globals"
+)
+
+func newAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	r.GET("/read", ApiKey(), ok)
+	r.POST("/admin", Admin(), ok)
+	return r
+}
+
+func status(r *gin.Engine, method, path string, header http.Header) int {
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAdmin(t *testing.T) {
+	r := newAuthRouter()
+
+	// 没有 ADMIN_TOKEN 和 admin Key 时管理接口不可用
+	if code := status(r, "POST", "/admin", nil); code != http.StatusForbidden {
+		t.Errorf("disabled admin = %d, want 403", code)
+	}
+
+	globals.AdminToken = "admin-secret"
+	t.Cleanup(func() { globals.AdminToken = "" })
+	read, _ := apikeys.Default.Create("read", globals.ApiKeyRoleRead)
+	admin, _ := apikeys.Default.Create("ops", globals.ApiKeyRoleAdmin)
+	t.Cleanup(func() {
+		apikeys.Default.Revoke(read.Id)
+		apikeys.Default.Revoke(admin.Id)
+	})
+
+	cases := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"no key", nil, http.StatusUnauthorized},
+		{"wrong token", http.Header{"Authorization": {"Bearer admin-secre"}}, http.StatusUnauthorized},
+		{"read key", http.Header{"X-Api-Key": {read.Key}}, http.StatusUnauthorized},
+		{"admin token", http.Header{"Authorization": {"Bearer admin-secret"}}, http.StatusOK},
+		{"admin key", http.Header{"X-Api-Key": {admin.Key}}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		if code := status(r, "POST", "/admin", tc.header); code != tc.want {
+			t.Errorf("%s: %d, want %d", tc.name, code, tc.want)
+		}
+	}
+	if code := status(r, "POST", "/admin?api_key="+admin.Key, nil); code != http.StatusOK {
+		t.Errorf("admin key in query: %d, want 200", code)
+	}
+
+	// 吊销后立即失效
+	apikeys.Default.Revoke(admin.Id)
+	if code := status(r, "POST", "/admin", http.Header{"X-Api-Key": {admin.Key}}); code != http.StatusUnauthorized {
+		t.Errorf("revoked admin key: %d, want 401", code)
+	}
+}
+
+func TestApiKey(t *testing.T) {
+	r := newAuthRouter()
+	if code := status(r, "GET", "/read", nil); code != http.StatusOK {
+		t.Errorf("key not required: %d, want 200", code)
+	}
+
+	globals.ApiKeyRequired, globals.AdminToken = true, "admin-secret"
+	t.Cleanup(func() { globals.ApiKeyRequired, globals.AdminToken = false, "" })
+	read, _ := apikeys.Default.Create("read", globals.ApiKeyRoleRead)
+	t.Cleanup(func() { apikeys.Default.Revoke(read.Id) })
+
+	cases := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"no key", nil, http.StatusUnauthorized},
+		{"unknown key", http.Header{"X-Api-Key": {"hots_nope"}}, http.StatusUnauthorized},
+		{"read key", http.Header{"X-Api-Key": {read.Key}}, http.StatusOK},
+		{"bearer read key", http.Header{"Authorization": {"Bearer " + read.Key}}, http.StatusOK},
+		{"admin token", http.Header{"X-Api-Key": {"admin-secret"}}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		if code := status(r, "GET", "/read", tc.header); code != tc.want {
+			t.Errorf("%s: %d, want %d", tc.name, code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+)
+
+// Leader Webhook、关注规则、API Key 等只能在 leader 上修改, 从实例拒绝修改, 返回 503 便于负载均衡重试其他实例
+func Leader() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !leader.IsLeader() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, globals.GblResp{Code: 1, Err: "Not the leader"})
+			return
+		}
+		c.Next()
+	}
+}
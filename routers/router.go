@@ -1,6 +1,7 @@
 package routers
 
 import (
+	"github.com/turbo-uid/hots/openapi"
 	"github.com/turbo-uid/hots/routers/api"
 	"github.com/turbo-uid/hots/routers/middlewares"
 
@@ -16,7 +17,7 @@ func InitRouter() *gin.Engine {
 
 	r.Use(middlewares.Logger())
 
-	apiGroup := r.Group("/api")
+	apiGroup := r.Group("/api", middlewares.ApiKey())
 	{
 		apiGroup.GET("/hot/bili", api.BiliHot)
 		apiGroup.GET("/hot/weibo", api.WeiboHot)
@@ -43,7 +44,70 @@ func InitRouter() *gin.Engine {
 		apiGroup.GET("/hot/qctt", api.QcttHot)
 		apiGroup.GET("/hot/endata", api.EnDataHot)
 		apiGroup.GET("/hot/toolify", api.ToolifyHot)
+
+		// 路径与榜单名不一致的榜单, 如 zhihu、endata-s, 同时可以按榜单名访问
+		for _, b := range api.Boards {
+			if b.Path != "/api/hot/"+b.Name {
+				apiGroup.GET("/hot/"+b.Name, api.BoardHandler(b))
+			}
+		}
+
+		// 配置文件定义的榜单
+		for _, b := range api.CustomBoards() {
+			apiGroup.GET("/hot/"+b.Name, api.BoardHandler(b))
+		}
+
+		apiGroup.GET("/aggregate", api.Aggregate)
+
+		apiGroup.GET("/hot/:platform/diff", api.HotDiff)
+		apiGroup.GET("/hot/:platform/history", api.HotHistory)
+
+		apiGroup.GET("/stream", api.Stream)
+		apiGroup.GET("/ws", api.WebSocket)
+
+		// 修改数据的接口都需要管理权限, 读 Key 只能查询
+		apiGroup.GET("/watch-rules", api.WatchRules)
+		apiGroup.POST("/watch-rules", middlewares.Admin(), middlewares.Leader(), api.AddWatchRule)
+		apiGroup.GET("/watch-rules/:id", api.WatchRule)
+		apiGroup.DELETE("/watch-rules/:id", middlewares.Admin(), middlewares.Leader(), api.DeleteWatchRule)
+		apiGroup.GET("/alerts", api.Alerts)
+
+		apiGroup.GET("/search", api.Search)
+		apiGroup.GET("/terms", api.Terms)
+
+		apiGroup.GET("/digests", api.Digests)
+		// 生成并推送摘要会发邮件和群消息, 需要管理权限
+		apiGroup.POST("/digests", middlewares.Admin(), api.GenerateDigest)
+		apiGroup.GET("/digests/:id", api.Digest)
+
+		// 新增、删除和重放 Webhook 会向外发请求, 需要管理权限
+		apiGroup.GET("/webhooks", api.Webhooks)
+		apiGroup.POST("/webhooks", middlewares.Admin(), middlewares.Leader(), api.AddWebhook)
+		apiGroup.GET("/webhooks/:id", api.Webhook)
+		apiGroup.DELETE("/webhooks/:id", middlewares.Admin(), middlewares.Leader(), api.DeleteWebhook)
+		apiGroup.GET("/webhooks/:id/deliveries", api.WebhookDeliveries)
+		apiGroup.POST("/webhooks/:id/deliveries/:delivery/replay", middlewares.Admin(), middlewares.Leader(), api.ReplayDelivery)
+	}
+
+	// 管理接口, 需要 ADMIN_TOKEN 或 admin 权限的 API Key
+	adminGroup := r.Group("/api/admin", middlewares.Admin())
+	{
+		adminGroup.POST("/refresh", api.AdminRefresh)
+		adminGroup.POST("/reload", api.AdminReload)
+		adminGroup.GET("/keys", api.ApiKeys)
+		adminGroup.POST("/keys", middlewares.Leader(), api.CreateApiKey)
+		adminGroup.DELETE("/keys/:id", middlewares.Leader(), api.RevokeApiKey)
 	}
 
+	feedGroup := r.Group("/feed", middlewares.ApiKey())
+	{
+		feedGroup.GET("/:name", api.FeedBoard)
+		feedGroup.GET("/category/:name", api.FeedCategory)
+	}
+
+	// 接口文档, 每次请求时由上面注册的路由生成
+	r.GET("/openapi.json", openapi.Handler(func() *openapi.Document { return apiDocument(r) }))
+	r.GET("/docs", openapi.UI("hots API", "openapi.json"))
+
 	return r
 }
@@ -0,0 +1,50 @@
+package routers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/apikeys"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// TestMutatingRoutesRequireAdmin /api 下修改数据的接口拒绝 read 权限的 Key, 新增路由时同样适用
+func TestMutatingRoutesRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+	globals.AdminToken = "admin-secret"
+	t.Cleanup(func() { globals.AdminToken = "" })
+
+	key, err := apikeys.Default.Create("reader", globals.ApiKeyRoleRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { apikeys.Default.Revoke(key.Id) })
+
+	r := InitRouter()
+	var checked int
+	for _, ri := range r.Routes() {
+		if ri.Method == http.MethodGet || !strings.HasPrefix(ri.Path, "/api/") {
+			continue
+		}
+		checked++
+
+		path := strings.NewReplacer(":id", "x", ":delivery", "y").Replace(ri.Path)
+		req := httptest.NewRequest(ri.Method, path, strings.NewReader("{}"))
+		req.Header.Set("X-API-Key", key.Key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s with read key: status %d, want 401", ri.Method, ri.Path, w.Code)
+		}
+	}
+	if checked == 0 {
+		t.Fatal("no mutating routes found")
+	}
+}
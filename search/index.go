@@ -0,0 +1,329 @@
+package search
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Default 进程内的搜索索引, 启动时用已有快照构建, 之后每保存一份快照增量更新
+var Default = NewIndex()
+
+// topic 一个平台的一个条目, 即搜索结果的分组单位
+type topic struct {
+	platform string
+	id       string
+	title    string
+	desc     string
+	toUrl    string
+	hotVal   string
+	bestPos  int
+	// 出现过的全部标题和描述, 已规范化
+	text   string
+	titles string
+	// 上榜时间段, 升序; 连续上榜合并为一段, 内存占用不随快照数增长
+	spans []span
+}
+
+// span 一段连续上榜的时间, Unix 秒, hits 为期间出现的次数
+type span struct {
+	from, to int64
+	hits     int
+}
+
+// 两次上榜间隔不超过该时长时视为连续, 需大于快照抽稀后的间隔
+var spanGap = int64((2 * time.Hour).Seconds())
+
+// mark 记录一次上榜, 早于已记录时间的忽略
+func (t *topic) mark(at int64) {
+	n := len(t.spans)
+	if n > 0 && at <= t.spans[n-1].to {
+		return
+	}
+	if n > 0 && at-t.spans[n-1].to <= spanGap {
+		t.spans[n-1].to = at
+		t.spans[n-1].hits++
+		return
+	}
+	t.spans = append(t.spans, span{from: at, to: at, hits: 1})
+}
+
+// within [from, to] 内的上榜次数和首末次时间, 部分重叠的时间段按比例估算次数
+func (t *topic) within(from, to int64) (int, int64, int64) {
+	var hits int
+	var first, last int64
+	for _, s := range t.spans {
+		if s.to < from || s.from > to {
+			continue
+		}
+		lo, hi := max(s.from, from), min(s.to, to)
+		n := s.hits
+		if s.to > s.from && (lo > s.from || hi < s.to) {
+			n = max(1, int(math.Round(float64(s.hits)*float64(hi-lo)/float64(s.to-s.from))))
+		}
+		if hits == 0 {
+			first = lo
+		}
+		hits += n
+		last = hi
+	}
+	return hits, first, last
+}
+
+// Index 倒排索引, 词 -> 升序的 topic 下标
+type Index struct {
+	mu       sync.RWMutex
+	topics   []*topic
+	byKey    map[string]int
+	postings map[string][]int
+}
+
+func NewIndex() *Index {
+	return &Index{byKey: map[string]int{}, postings: map[string][]int{}}
+}
+
+// Build 用快照库中全部快照构建索引, 持久化存储中更早的记录先用 AddRecord 加入
+func (x *Index) Build(store globals.SnapshotStore) {
+	for _, flag := range store.Platforms() {
+		for _, snap := range store.List(flag, time.Time{}, time.Time{}) {
+			x.Add(snap)
+		}
+	}
+}
+
+// AddRecord 索引持久化存储中一个条目的累计记录, 首末次上榜之间视为一段
+func (x *Index) AddRecord(r globals.ItemRecord) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	k, t := x.topic(r.Platform, r.Item)
+	if t.fresh(r.LastSeen.Unix()) {
+		t.update(r.Item)
+	}
+	if r.BestPos > 0 && (t.bestPos <= 0 || r.BestPos < t.bestPos) {
+		t.bestPos = r.BestPos
+	}
+	from, to := r.FirstSeen.Unix(), r.LastSeen.Unix()
+	if n := len(t.spans); n == 0 || t.spans[n-1].to < from {
+		t.spans = append(t.spans, span{from: from, to: to, hits: max(1, r.Appearances)})
+	}
+	x.index(k, t, r.Item)
+}
+
+// Add 索引一份快照
+func (x *Index) Add(snap globals.Snapshot) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	at := snap.FetchedAt.Unix()
+	for _, v := range snap.Data {
+		k, t := x.topic(snap.Platform, v)
+		if t.fresh(at) {
+			t.update(v)
+		}
+		if v.Pos > 0 && (t.bestPos <= 0 || v.Pos < t.bestPos) {
+			t.bestPos = v.Pos
+		}
+		t.mark(at)
+		x.index(k, t, v)
+	}
+}
+
+// topic 查找或新建条目; 调用方需持有锁
+func (x *Index) topic(platform string, v globals.GblRespData) (int, *topic) {
+	key := platform + "|" + v.Id
+	k, found := x.byKey[key]
+	if !found {
+		k = len(x.topics)
+		x.byKey[key] = k
+		x.topics = append(x.topics, &topic{platform: platform, id: v.Id, bestPos: v.Pos})
+	}
+	return k, x.topics[k]
+}
+
+// fresh at 不早于已记录的最近上榜时间, 此时的内容应覆盖已有内容
+func (t *topic) fresh(at int64) bool {
+	n := len(t.spans)
+	return n == 0 || at >= t.spans[n-1].to
+}
+
+// update 更新为最新的内容
+func (t *topic) update(v globals.GblRespData) {
+	t.title = v.Title
+	t.desc = v.Desc
+	t.toUrl = v.ToUrl
+	t.hotVal = v.HotVal
+}
+
+// index 标题或描述有变化时补充索引, 调用方需持有锁
+func (x *Index) index(k int, t *topic, v globals.GblRespData) {
+	text := Normalize(v.Title + "\n" + v.Desc)
+	if strings.Contains(t.text, text) {
+		return
+	}
+	t.text += text + "\n"
+	t.titles += Normalize(v.Title) + "\n"
+	for token := range indexTokens(text) {
+		x.post(token, k)
+	}
+}
+
+// post 调用方需持有锁
+func (x *Index) post(token string, k int) {
+	list := x.postings[token]
+	n := len(list)
+	if n > 0 && list[n-1] == k {
+		return
+	}
+	if n == 0 || list[n-1] < k {
+		x.postings[token] = append(list, k)
+		return
+	}
+
+	i := sort.SearchInts(list, k)
+	if i < n && list[i] == k {
+		return
+	}
+	list = append(list, 0)
+	copy(list[i+1:], list[i:])
+	list[i] = k
+	x.postings[token] = list
+}
+
+// intersect 两个升序列表的交集
+func intersect(a, b []int) []int {
+	var result []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// Query 搜索条件, Platforms 为空时不过滤, From、To 零值表示不限制
+type Query struct {
+	Q         string
+	Platforms map[string]bool
+	From      time.Time
+	To        time.Time
+	Limit     int
+	Offset    int
+}
+
+// 相关度按时间衰减的半衰期
+var recencyHalfLife = 24 * time.Hour
+
+// Search 查询词全部命中才算匹配, 按相关度、时间新近程度和上榜次数排序, 返回分页结果和总数
+func (x *Index) Search(q Query, now time.Time) ([]globals.SearchHit, int, error) {
+	tokens := queryTokens(q.Q)
+	if len(tokens) == 0 {
+		return nil, 0, errors.New("empty query")
+	}
+	cjk, words := runs(q.Q)
+	parts := append(cjk, words...)
+
+	from, to := int64(math.MinInt64), int64(math.MaxInt64)
+	if !q.From.IsZero() {
+		from = q.From.Unix()
+	}
+	if !q.To.IsZero() {
+		to = q.To.Unix()
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	// 从最短的倒排列表开始求交集
+	lists := make([][]int, 0, len(tokens))
+	for _, token := range tokens {
+		lists = append(lists, x.postings[token])
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return len(lists[i]) < len(lists[j])
+	})
+	candidates := lists[0]
+	for _, list := range lists[1:] {
+		candidates = intersect(candidates, list)
+	}
+
+	var result []globals.SearchHit
+	for _, k := range candidates {
+		t := x.topics[k]
+		if len(q.Platforms) > 0 && !q.Platforms[t.platform] {
+			continue
+		}
+
+		// 二元组不要求连续, 需确认每个片段完整出现
+		matched := true
+		for _, p := range parts {
+			if !strings.Contains(t.text, p) {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		hits, first, last := t.within(from, to)
+		if hits == 0 {
+			continue
+		}
+
+		var relevance float64
+		for _, token := range tokens {
+			idf := math.Log(1 + float64(len(x.topics))/float64(len(x.postings[token])))
+			if strings.Contains(t.titles, token) {
+				idf *= 2
+			}
+			relevance += idf
+		}
+		lastSeen := time.Unix(last, 0)
+		recency := math.Exp2(-now.Sub(lastSeen).Hours() / recencyHalfLife.Hours())
+		score := relevance * (0.5 + 0.5*recency) * (1 + 0.1*math.Log1p(float64(hits)))
+
+		result = append(result, globals.SearchHit{
+			Platform:  t.platform,
+			Id:        t.id,
+			Title:     t.title,
+			Desc:      t.desc,
+			ToUrl:     t.toUrl,
+			HotVal:    t.hotVal,
+			BestPos:   t.bestPos,
+			Hits:      hits,
+			FirstSeen: time.Unix(first, 0),
+			LastSeen:  lastSeen,
+			Score:     math.Round(score*1000) / 1000,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+
+	total := len(result)
+	if q.Offset >= total {
+		return nil, total, nil
+	}
+	result = result[q.Offset:]
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result, total, nil
+}
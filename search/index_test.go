@@ -0,0 +1,77 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+var base = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func snapshot(at time.Time, items ...globals.GblRespData) globals.Snapshot {
+	return globals.Snapshot{Platform: "weibo", FetchedAt: at, Data: items}
+}
+
+func TestSpansCollapse(t *testing.T) {
+	x := NewIndex()
+	item := globals.GblRespData{Id: "1", Title: "苹果发布会", Pos: 3}
+
+	// 每分钟一份, 连续 3 小时
+	for i := 0; i < 180; i++ {
+		x.Add(snapshot(base.Add(time.Duration(i)*time.Minute), item))
+	}
+	// 间隔一天后再次上榜
+	x.Add(snapshot(base.Add(27*time.Hour), item))
+
+	if n := len(x.topics[0].spans); n != 2 {
+		t.Fatalf("got %d spans, want 2", n)
+	}
+
+	hits, _, err := x.Search(Query{Q: "苹果"}, base.Add(28*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].Hits != 181 {
+		t.Fatalf("hits = %+v, want 1 hit with 181 appearances", hits)
+	}
+	if !hits[0].FirstSeen.Equal(base) || !hits[0].LastSeen.Equal(base.Add(27*time.Hour)) {
+		t.Errorf("seen %s ~ %s", hits[0].FirstSeen, hits[0].LastSeen)
+	}
+
+	// 只覆盖第一段的一半时按比例估算
+	hits, _, _ = x.Search(Query{Q: "苹果", From: base.Add(90 * time.Minute), To: base.Add(3 * time.Hour)}, base)
+	if len(hits) != 1 || hits[0].Hits < 85 || hits[0].Hits > 95 {
+		t.Errorf("partial hits = %+v, want about 90", hits)
+	}
+}
+
+func TestAddRecord(t *testing.T) {
+	x := NewIndex()
+	x.AddRecord(globals.ItemRecord{
+		Platform:    "weibo",
+		Item:        globals.GblRespData{Id: "1", Title: "苹果发布会新品"},
+		FirstSeen:   base,
+		LastSeen:    base.Add(5 * time.Hour),
+		BestPos:     2,
+		Appearances: 300,
+	})
+	// 快照库中较早的快照不覆盖内容也不重复计数, 之后的快照继续累计
+	x.Add(snapshot(base.Add(time.Hour), globals.GblRespData{Id: "1", Title: "苹果发布会", Pos: 5}))
+	x.Add(snapshot(base.Add(6*time.Hour), globals.GblRespData{Id: "1", Title: "苹果发布会新品", Pos: 4}))
+
+	hits, total, err := x.Search(Query{Q: "新品"}, base.Add(6*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Fatalf("total = %d, want 1", total)
+	}
+	got := hits[0]
+	if got.Hits != 301 || got.BestPos != 2 || got.Title != "苹果发布会新品" {
+		t.Errorf("hit = %+v", got)
+	}
+	if !got.FirstSeen.Equal(base) || !got.LastSeen.Equal(base.Add(6*time.Hour)) {
+		t.Errorf("seen %s ~ %s", got.FirstSeen, got.LastSeen)
+	}
+}
@@ -0,0 +1,109 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 英文、数字单词按前缀建索引, 前缀最长 maxPrefix 个字符
+const maxPrefix = 20
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+// Normalize 转小写并将全角字母数字转为半角
+func Normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '！' && r <= '～' {
+			r = r - '！' + '!'
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// runs 按文字类型切分: 连续的中日韩文字为一段, 连续的字母数字为一段, 其他字符丢弃
+func runs(s string) (cjk, words []string) {
+	var cur []rune
+	curCJK := false
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		if curCJK {
+			cjk = append(cjk, string(cur))
+		} else {
+			words = append(words, string(cur))
+		}
+		cur = cur[:0]
+	}
+
+	for _, r := range Normalize(s) {
+		switch {
+		case isCJK(r):
+			if !curCJK {
+				flush()
+			}
+			curCJK = true
+			cur = append(cur, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if curCJK {
+				flush()
+			}
+			curCJK = false
+			cur = append(cur, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return cjk, words
+}
+
+// indexTokens 文档的索引词: 中文单字和二元组, 英文数字单词的全部前缀
+func indexTokens(s string) map[string]bool {
+	result := map[string]bool{}
+
+	cjk, words := runs(s)
+	for _, run := range cjk {
+		rs := []rune(run)
+		for k := range rs {
+			result[string(rs[k])] = true
+			if k+1 < len(rs) {
+				result[string(rs[k:k+2])] = true
+			}
+		}
+	}
+	for _, w := range words {
+		rs := []rune(w)
+		for n := 1; n <= len(rs) && n <= maxPrefix; n++ {
+			result[string(rs[:n])] = true
+		}
+	}
+	return result
+}
+
+// queryTokens 查询词: 中文按二元组(单字时用单字), 英文数字单词用前缀, 命中全部查询词才算匹配
+func queryTokens(q string) []string {
+	var result []string
+
+	cjk, words := runs(q)
+	for _, run := range cjk {
+		rs := []rune(run)
+		if len(rs) == 1 {
+			result = append(result, run)
+			continue
+		}
+		for k := 0; k+1 < len(rs); k++ {
+			result = append(result, string(rs[k:k+2]))
+		}
+	}
+	for _, w := range words {
+		rs := []rune(w)
+		if len(rs) > maxPrefix {
+			rs = rs[:maxPrefix]
+		}
+		result = append(result, string(rs))
+	}
+	return result
+}
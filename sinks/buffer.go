@@ -0,0 +1,109 @@
+package sinks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Sink 快照的持久化目标
+type Sink interface {
+	// Write 批量写入快照, 同一快照重复写入不产生重复数据
+	Write(ctx context.Context, snaps []globals.Snapshot) error
+}
+
+// Default 进程内的写入缓冲, 未运行时 Add 直接丢弃
+var Default = NewBuffer(globals.SinkBatchSize, globals.SinkFlushInterval)
+
+// Buffer 攒批写入 Sink, 达到 size 份或每隔 interval 写入一次, 失败的快照留到下次重试
+type Buffer struct {
+	mu       sync.Mutex
+	sink     Sink
+	size     int
+	interval time.Duration
+	pending  []globals.Snapshot
+	full     chan struct{}
+}
+
+func NewBuffer(size int, interval time.Duration) *Buffer {
+	return &Buffer{size: size, interval: interval, full: make(chan struct{}, 1)}
+}
+
+// Add 加入待写入队列, 积压超过 SinkPendingKeep 份时丢弃最旧的
+func (b *Buffer) Add(snap globals.Snapshot) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.sink == nil {
+		return
+	}
+
+	b.pending = append(b.pending, snap)
+	if n := len(b.pending) - globals.SinkPendingKeep; n > 0 {
+		globals.GoLogger.Errorf("SINK DROPPED %d SNAPSHOTS", n)
+		b.pending = b.pending[n:]
+	}
+
+	if len(b.pending) >= b.size {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run 持续写入 sink, 一般在启动时以 goroutine 运行
+func (b *Buffer) Run(sink Sink) {
+	b.mu.Lock()
+	b.sink = sink
+	b.mu.Unlock()
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.full:
+		}
+
+		if err := b.Flush(); err != nil {
+			globals.GoLogger.Errorf("SINK WRITE ERR %s", err.Error())
+		}
+	}
+}
+
+// Flush 写入当前积压的快照
+func (b *Buffer) Flush() error {
+	b.mu.Lock()
+	sink := b.sink
+	list := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if sink == nil || len(list) == 0 {
+		return nil
+	}
+
+	for len(list) > 0 {
+		n := min(len(list), b.size)
+
+		ctx, cancel := context.WithTimeout(context.Background(), globals.SinkWriteTimeout)
+		err := sink.Write(ctx, list[:n])
+		cancel()
+
+		if err != nil {
+			// 放回队首, 下次重试
+			b.mu.Lock()
+			b.pending = append(list, b.pending...)
+			b.mu.Unlock()
+			return err
+		}
+
+		globals.GoLogger.Infof("SINK WROTE %d SNAPSHOTS", n)
+		list = list[n:]
+	}
+	return nil
+}
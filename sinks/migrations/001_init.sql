@@ -0,0 +1,51 @@
+-- 每次刷新保存的快照
+CREATE TABLE hot_snapshots (
+    platform   TEXT        NOT NULL,
+    id         TEXT        NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL,
+    item_count INTEGER     NOT NULL,
+    PRIMARY KEY (platform, id)
+);
+
+CREATE INDEX hot_snapshots_fetched_at ON hot_snapshots (platform, fetched_at DESC);
+
+-- 条目按稳定ID去重, 保存最近一次上榜的内容
+CREATE TABLE hot_items (
+    id           TEXT        PRIMARY KEY,
+    platform     TEXT        NOT NULL,
+    title        TEXT        NOT NULL,
+    description  TEXT        NOT NULL DEFAULT '',
+    hot_val      TEXT        NOT NULL DEFAULT '',
+    icon         TEXT        NOT NULL DEFAULT '',
+    to_url       TEXT        NOT NULL DEFAULT '',
+    label        TEXT        NOT NULL DEFAULT '',
+    is_top       INTEGER     NOT NULL DEFAULT 0,
+    author       TEXT        NOT NULL DEFAULT '',
+    published_at TEXT        NOT NULL DEFAULT '',
+    views        BIGINT      NOT NULL DEFAULT 0,
+    likes        BIGINT      NOT NULL DEFAULT 0,
+    comments     BIGINT      NOT NULL DEFAULT 0,
+    badges       TEXT[],
+    extra        JSONB,
+    first_seen   TIMESTAMPTZ NOT NULL,
+    last_seen    TIMESTAMPTZ NOT NULL,
+    last_pos     INTEGER     NOT NULL DEFAULT 0,
+    best_pos     INTEGER     NOT NULL DEFAULT 0,
+    appearances  INTEGER     NOT NULL DEFAULT 1
+);
+
+CREATE INDEX hot_items_last_seen ON hot_items (platform, last_seen DESC);
+
+-- 快照中每个条目的排名和热度
+CREATE TABLE hot_snapshot_items (
+    platform    TEXT    NOT NULL,
+    snapshot_id TEXT    NOT NULL,
+    item_id     TEXT    NOT NULL,
+    pos         INTEGER NOT NULL,
+    hot_val     TEXT    NOT NULL DEFAULT '',
+    hot         BIGINT  NOT NULL DEFAULT 0,
+    PRIMARY KEY (platform, snapshot_id, item_id),
+    FOREIGN KEY (platform, snapshot_id) REFERENCES hot_snapshots (platform, id) ON DELETE CASCADE
+);
+
+CREATE INDEX hot_snapshot_items_item ON hot_snapshot_items (item_id);
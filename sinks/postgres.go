@@ -0,0 +1,225 @@
+package sinks
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/utils"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// 迁移时持有的 advisory lock 编号, 避免多个实例同时迁移
+const migrateLockId = 0x686f7473 + 1
+
+// Postgres 将快照和条目写入 Postgres, 表结构见 migrations
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+// OpenPostgres 连接数据库并执行未应用的迁移
+func OpenPostgres(ctx context.Context, dsn string) (*Postgres, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Postgres{pool: pool}
+	if err := p.Migrate(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Postgres) Close() {
+	p.pool.Close()
+}
+
+// Migrate 按文件名前缀的版本号依次执行 migrations 下的 SQL, 已执行的版本记录在 schema_migrations
+func (p *Postgres) Migrate(ctx context.Context) error {
+	files, err := migrations.ReadDir("migrations")
+	if err != nil {
+		return err
+	}
+
+	type migration struct {
+		version int
+		name    string
+	}
+	var list []migration
+	for _, f := range files {
+		prefix, _, _ := strings.Cut(f.Name(), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return fmt.Errorf("invalid migration name %s", f.Name())
+		}
+		list = append(list, migration{version, f.Name()})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].version < list[j].version })
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrateLockId); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER     PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return err
+	}
+
+	applied := map[int]bool{}
+	rows, err := tx.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[v] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, m := range list {
+		if applied[m.version] {
+			continue
+		}
+
+		sql, err := migrations.ReadFile("migrations/" + m.name)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, string(sql)); err != nil {
+			return fmt.Errorf("migration %s: %w", m.name, err)
+		}
+		if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.version); err != nil {
+			return err
+		}
+		globals.GoLogger.Infof("APPLIED MIGRATION %s", m.name)
+	}
+
+	return tx.Commit(ctx)
+}
+
+const insertSnapshot = `INSERT INTO hot_snapshots (platform, id, fetched_at, item_count)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING`
+
+// 条目按ID合并, 内容以最近一次上榜为准
+const upsertItem = `INSERT INTO hot_items (
+	id, platform, title, description, hot_val, icon, to_url, label, is_top,
+	author, published_at, views, likes, comments, badges, extra,
+	first_seen, last_seen, last_pos, best_pos
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $17, $18, $18)
+ON CONFLICT (id) DO UPDATE SET
+	title        = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.title ELSE hot_items.title END,
+	description  = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.description ELSE hot_items.description END,
+	hot_val      = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.hot_val ELSE hot_items.hot_val END,
+	icon         = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.icon ELSE hot_items.icon END,
+	to_url       = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.to_url ELSE hot_items.to_url END,
+	label        = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.label ELSE hot_items.label END,
+	is_top       = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.is_top ELSE hot_items.is_top END,
+	author       = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.author ELSE hot_items.author END,
+	published_at = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.published_at ELSE hot_items.published_at END,
+	views        = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.views ELSE hot_items.views END,
+	likes        = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.likes ELSE hot_items.likes END,
+	comments     = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.comments ELSE hot_items.comments END,
+	badges       = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.badges ELSE hot_items.badges END,
+	extra        = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.extra ELSE hot_items.extra END,
+	last_pos     = CASE WHEN EXCLUDED.last_seen >= hot_items.last_seen THEN EXCLUDED.last_pos ELSE hot_items.last_pos END,
+	first_seen   = LEAST(hot_items.first_seen, EXCLUDED.first_seen),
+	last_seen    = GREATEST(hot_items.last_seen, EXCLUDED.last_seen),
+	best_pos     = CASE WHEN hot_items.best_pos <= 0 OR (EXCLUDED.best_pos > 0 AND EXCLUDED.best_pos < hot_items.best_pos)
+	               THEN EXCLUDED.best_pos ELSE hot_items.best_pos END,
+	appearances  = hot_items.appearances + 1`
+
+const insertSnapshotItem = `INSERT INTO hot_snapshot_items (platform, snapshot_id, item_id, pos, hot_val, hot)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT DO NOTHING`
+
+// Write 在一个事务中写入快照; 已写入过的快照跳过, 其余快照的条目一次批量发送
+func (p *Postgres) Write(ctx context.Context, snaps []globals.Snapshot) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, snap := range snaps {
+		tag, err := tx.Exec(ctx, insertSnapshot, snap.Platform, snap.Id, snap.FetchedAt, len(snap.Data))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, v := range snap.Data {
+			if v.Id == "" || seen[v.Id] {
+				continue
+			}
+			seen[v.Id] = true
+
+			var extra interface{}
+			if len(v.Extra) > 0 {
+				extra = v.Extra
+			}
+			batch.Queue(upsertItem,
+				v.Id, snap.Platform, v.Title, v.Desc, v.HotVal, v.Icon, v.ToUrl, v.Lab, v.IsTop,
+				v.Author, v.PublishedAt, v.Views, v.Likes, v.Comments, v.Badges, extra,
+				snap.FetchedAt, v.Pos)
+			batch.Queue(insertSnapshotItem, snap.Platform, snap.Id, v.Id, v.Pos, v.HotVal, utils.ParseCount(v.HotVal))
+		}
+	}
+
+	if batch.Len() > 0 {
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+const selectItems = `SELECT platform, id, title, description, hot_val, to_url, first_seen, last_seen, best_pos, appearances
+FROM hot_items ORDER BY first_seen`
+
+// Items 按首次上榜时间依次读取全部条目的累计记录, 用于重启后重建搜索索引
+func (p *Postgres) Items(ctx context.Context, fn func(globals.ItemRecord)) error {
+	rows, err := p.pool.Query(ctx, selectItems)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r globals.ItemRecord
+		if err := rows.Scan(&r.Platform, &r.Item.Id, &r.Item.Title, &r.Item.Desc, &r.Item.HotVal, &r.Item.ToUrl,
+			&r.FirstSeen, &r.LastSeen, &r.BestPos, &r.Appearances); err != nil {
+			return err
+		}
+		fn(r)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,151 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// openTestPostgres 需要设置 DATABASE_URL, 每个测试使用单独的 schema, 结束后删除
+func openTestPostgres(t *testing.T) *Postgres {
+	t.Helper()
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+
+	ctx := context.Background()
+	admin, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(admin.Close)
+
+	schema := fmt.Sprintf("hots_test_%d", rand.Uint32())
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE") })
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Postgres{pool: pool}
+	t.Cleanup(p.Close)
+
+	if err := p.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func count(t *testing.T, p *Postgres, table string) int {
+	t.Helper()
+	var n int
+	if err := p.pool.QueryRow(context.Background(), "SELECT count(*) FROM "+table).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestMigrateIdempotent(t *testing.T) {
+	p := openTestPostgres(t)
+
+	if err := p.Migrate(context.Background()); err != nil {
+		t.Fatalf("second migrate: %s", err)
+	}
+	files, _ := migrations.ReadDir("migrations")
+	if n := count(t, p, "schema_migrations"); n != len(files) {
+		t.Errorf("schema_migrations has %d rows, want %d", n, len(files))
+	}
+}
+
+func TestWriteSnapshotTwice(t *testing.T) {
+	p := openTestPostgres(t)
+	ctx := context.Background()
+
+	snap := globals.Snapshot{
+		Id:        "s1",
+		Platform:  globals.WeiboFlag,
+		FetchedAt: time.Now(),
+		Data: []globals.GblRespData{
+			{Id: "weibo-1", Title: "苹果发布会", HotVal: "1.2万", Pos: 1},
+			{Id: "weibo-2", Title: "天气", HotVal: "300", Pos: 2},
+			// 同一快照中重复的ID只写一次
+			{Id: "weibo-1", Title: "苹果发布会", HotVal: "1.2万", Pos: 3},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		if err := p.Write(ctx, []globals.Snapshot{snap}); err != nil {
+			t.Fatalf("write %d: %s", i, err)
+		}
+	}
+
+	if n := count(t, p, "hot_snapshots"); n != 1 {
+		t.Errorf("hot_snapshots has %d rows, want 1", n)
+	}
+	if n := count(t, p, "hot_snapshot_items"); n != 2 {
+		t.Errorf("hot_snapshot_items has %d rows, want 2", n)
+	}
+	var appearances int
+	if err := p.pool.QueryRow(ctx, "SELECT appearances FROM hot_items WHERE id = 'weibo-1'").Scan(&appearances); err != nil {
+		t.Fatal(err)
+	}
+	if appearances != 1 {
+		t.Errorf("appearances = %d after writing the same snapshot twice, want 1", appearances)
+	}
+}
+
+func TestUpsertItem(t *testing.T) {
+	p := openTestPostgres(t)
+	ctx := context.Background()
+	base := time.Now().Truncate(time.Second)
+
+	snap := func(id string, at time.Time, title string, pos int) globals.Snapshot {
+		return globals.Snapshot{
+			Id: id, Platform: globals.WeiboFlag, FetchedAt: at,
+			Data: []globals.GblRespData{{Id: "weibo-1", Title: title, Pos: pos}},
+		}
+	}
+	// 晚到的旧快照不覆盖内容, 只更新首次上榜时间和最高排名
+	for _, s := range []globals.Snapshot{
+		snap("s2", base.Add(time.Minute), "新标题", 5),
+		snap("s3", base.Add(2*time.Minute), "最新标题", 4),
+		snap("s1", base, "旧标题", 2),
+	} {
+		if err := p.Write(ctx, []globals.Snapshot{s}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var records []globals.ItemRecord
+	if err := p.Items(ctx, func(r globals.ItemRecord) { records = append(records, r) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d items, want 1", len(records))
+	}
+	r := records[0]
+	if r.Item.Title != "最新标题" || r.BestPos != 2 || r.Appearances != 3 {
+		t.Errorf("item = %+v", r)
+	}
+	if !r.FirstSeen.Equal(base) || !r.LastSeen.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("seen %s ~ %s", r.FirstSeen, r.LastSeen)
+	}
+}
@@ -0,0 +1,108 @@
+package snapshots
+
+import (
+	"sort"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// Find 先按快照ID查找, 否则按时间取当时的快照; 早于保留的第一份快照时返回未找到
+// s 可以是快照ID、Unix 时间戳、RFC3339 时间或 30m 这样相对 now 的时长
+func Find(store globals.SnapshotStore, platform, s string, now time.Time) (globals.Snapshot, bool) {
+	if snap, found := store.Get(platform, s); found {
+		return snap, true
+	}
+
+	t, err := utils.ParseTime(s, now)
+	if err != nil {
+		return globals.Snapshot{}, false
+	}
+
+	return store.At(platform, t)
+}
+
+// Diff 对比两份快照, 按条目ID匹配: 新上榜、下榜、排名上升/下降、热度变化
+func Diff(from, to globals.Snapshot) globals.BoardDiff {
+	result := globals.BoardDiff{
+		Platform: to.Platform,
+		From:     from.Id,
+		To:       to.Id,
+		FromTime: from.FetchedAt,
+		ToTime:   to.FetchedAt,
+	}
+
+	old := map[string]globals.GblRespData{}
+	for _, v := range from.Data {
+		old[itemKey(from.Platform, v)] = v
+	}
+
+	seen := map[string]bool{}
+	for _, v := range to.Data {
+		k := itemKey(to.Platform, v)
+		seen[k] = true
+
+		o, found := old[k]
+		if !found {
+			result.Entered = append(result.Entered, diffEntry(k, globals.GblRespData{}, v))
+			continue
+		}
+
+		e := diffEntry(k, o, v)
+		switch {
+		case e.NewPos < e.OldPos:
+			result.MovedUp = append(result.MovedUp, e)
+		case e.NewPos > e.OldPos:
+			result.MovedDown = append(result.MovedDown, e)
+		}
+		if e.HotDelta != 0 {
+			result.HeatChanged = append(result.HeatChanged, e)
+		}
+	}
+
+	for _, v := range from.Data {
+		k := itemKey(from.Platform, v)
+		if !seen[k] {
+			result.Left = append(result.Left, diffEntry(k, v, globals.GblRespData{}))
+		}
+	}
+
+	sort.SliceStable(result.HeatChanged, func(i, j int) bool {
+		return abs(result.HeatChanged[i].HotDelta) > abs(result.HeatChanged[j].HotDelta)
+	})
+
+	return result
+}
+
+// itemKey 旧快照可能没有ID, 按标题补齐
+func itemKey(platform string, v globals.GblRespData) string {
+	return utils.GetItemId(platform, v.Id, v.Title)
+}
+
+func diffEntry(id string, o, n globals.GblRespData) globals.DiffEntry {
+	e := globals.DiffEntry{
+		Id:        id,
+		Title:     n.Title,
+		ToUrl:     n.ToUrl,
+		OldPos:    o.Pos,
+		NewPos:    n.Pos,
+		OldHotVal: o.HotVal,
+		NewHotVal: n.HotVal,
+	}
+	if e.Title == "" {
+		e.Title = o.Title
+		e.ToUrl = o.ToUrl
+	}
+	if o.Title != "" && n.Title != "" {
+		e.HotDelta = utils.ParseCount(n.HotVal) - utils.ParseCount(o.HotVal)
+	}
+	return e
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
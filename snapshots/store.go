@@ -0,0 +1,298 @@
+package snapshots
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/utils"
+)
+
+// Retention 按时间保留快照: 最近 Full 内全部保留, 更早的每 Interval 保留一份, 早于 Keep 的删除; 零值表示不限制
+type Retention struct {
+	Keep     time.Duration
+	Full     time.Duration
+	Interval time.Duration
+}
+
+// DefaultRetention 由 globals 中的配置生成
+func DefaultRetention() Retention {
+	return Retention{Keep: globals.SnapshotKeep, Full: globals.SnapshotFull, Interval: globals.SnapshotInterval}
+}
+
+// prune 清理 list(按时间升序)中超出保留策略的快照, 原地修改
+func (r Retention) prune(list []globals.Snapshot, now time.Time) []globals.Snapshot {
+	result := list[:0]
+	for _, v := range list {
+		age := now.Sub(v.FetchedAt)
+		if r.Keep > 0 && age > r.Keep {
+			continue
+		}
+		// 同一个时段只保留最早的一份
+		if r.Interval > 0 && age > r.Full && len(result) > 0 {
+			prev := result[len(result)-1]
+			if now.Sub(prev.FetchedAt) > r.Full && prev.FetchedAt.Truncate(r.Interval).Equal(v.FetchedAt.Truncate(r.Interval)) {
+				continue
+			}
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// Store 内存快照存储, 按 Retention 保留; dir 非空时同时追加写入 dir/<platform>.jsonl, 重启后自动加载
+type Store struct {
+	mu        sync.RWMutex
+	dir       string
+	retention Retention
+	data      map[string][]globals.Snapshot
+	lines     map[string]int // 文件中的行数, 超过保留数量两倍时重写文件并清理 seen
+	seen      map[string]map[string]time.Time
+}
+
+func NewStore(dir string, retention Retention) (*Store, error) {
+	s := &Store{dir: dir, retention: retention, data: map[string][]globals.Snapshot{}, lines: map[string]int{}, seen: map[string]map[string]time.Time{}}
+	if dir == "" {
+		return s, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if err := s.load(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// NewId 快照ID, 同一平台内按时间有序
+func NewId(t time.Time) string {
+	return t.UTC().Format("20060102T150405.000Z")
+}
+
+func (s *Store) Save(snap globals.Snapshot) error {
+	if snap.Id == "" {
+		snap.Id = NewId(snap.FetchedAt)
+	}
+
+	// 追加和重写文件都在写锁内进行, 避免重写时丢失并发追加的快照
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := s.retention.prune(append(s.data[snap.Platform], snap), snap.FetchedAt)
+	s.data[snap.Platform] = list
+	s.lines[snap.Platform]++
+	compact := s.lines[snap.Platform] > 2*len(list)
+	s.markSeen(snap)
+	if compact {
+		s.pruneSeen(snap.Platform)
+		s.lines[snap.Platform] = len(list)
+	}
+
+	if s.dir == "" {
+		return nil
+	}
+	if compact {
+		return s.rewrite(snap.Platform)
+	}
+	return s.append(snap)
+}
+
+func (s *Store) Get(platform, id string) (globals.Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, v := range s.data[platform] {
+		if v.Id == id {
+			return v, true
+		}
+	}
+	return globals.Snapshot{}, false
+}
+
+func (s *Store) Latest(platform string) (globals.Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.data[platform]
+	if len(list) == 0 {
+		return globals.Snapshot{}, false
+	}
+	return list[len(list)-1], true
+}
+
+func (s *Store) At(platform string, t time.Time) (globals.Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.data[platform]
+	i := sort.Search(len(list), func(i int) bool { return list[i].FetchedAt.After(t) })
+	if i == 0 {
+		return globals.Snapshot{}, false
+	}
+	return list[i-1], true
+}
+
+func (s *Store) List(platform string, from, to time.Time) []globals.Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []globals.Snapshot
+	for _, v := range s.data[platform] {
+		if !from.IsZero() && v.FetchedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && v.FetchedAt.After(to) {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+func (s *Store) FirstSeen(platform, itemId string) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, found := s.seen[platform][itemId]
+	return t, found
+}
+
+// markSeen 记录条目第一次出现的时间, 调用方持有写锁
+func (s *Store) markSeen(snap globals.Snapshot) {
+	seen := s.seen[snap.Platform]
+	if seen == nil {
+		seen = map[string]time.Time{}
+		s.seen[snap.Platform] = seen
+	}
+
+	for _, v := range snap.Data {
+		id := utils.GetItemId(snap.Platform, v.Id, v.Title)
+		if t, found := seen[id]; !found || snap.FetchedAt.Before(t) {
+			seen[id] = snap.FetchedAt
+		}
+	}
+}
+
+// pruneSeen 清理已不在保留快照中的条目, 调用方持有写锁
+func (s *Store) pruneSeen(platform string) {
+	keepIds := map[string]bool{}
+	for _, snap := range s.data[platform] {
+		for _, v := range snap.Data {
+			keepIds[utils.GetItemId(platform, v.Id, v.Title)] = true
+		}
+	}
+
+	for id := range s.seen[platform] {
+		if !keepIds[id] {
+			delete(s.seen[platform], id)
+		}
+	}
+}
+
+// Platforms 有快照的平台
+func (s *Store) Platforms() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []string
+	for k := range s.data {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (s *Store) file(platform string) string {
+	return filepath.Join(s.dir, platform+".jsonl")
+}
+
+// append 调用方持有写锁
+func (s *Store) append(snap globals.Snapshot) error {
+	line, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.file(snap.Platform), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// rewrite 文件行数超过保留数量两倍后重写文件, 避免无限增长; 调用方持有写锁
+func (s *Store) rewrite(platform string) error {
+	list := s.data[platform]
+
+	tmp := s.file(platform) + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, v := range list {
+		if err := enc.Encode(v); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.file(platform))
+}
+
+func (s *Store) load(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var snap globals.Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			// 跳过写入中断产生的半行
+			continue
+		}
+		s.data[snap.Platform] = append(s.data[snap.Platform], snap)
+		s.lines[snap.Platform]++
+	}
+
+	now := time.Now()
+	for k, list := range s.data {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].FetchedAt.Before(list[j].FetchedAt) })
+		s.data[k] = s.retention.prune(list, now)
+		for _, snap := range s.data[k] {
+			s.markSeen(snap)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,121 @@
+package snapshots
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+func TestRetention(t *testing.T) {
+	retention := Retention{Keep: 8 * 24 * time.Hour, Full: 12 * time.Hour, Interval: time.Hour}
+	s, err := NewStore(t.TempDir(), retention)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 每分钟一份, 共 10 天
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var now time.Time
+	for i := 0; i < 10*24*60; i++ {
+		now = start.Add(time.Duration(i) * time.Minute)
+		snap := globals.Snapshot{Platform: "weibo", FetchedAt: now, Data: []globals.GblRespData{{Id: "1", Title: "a", Pos: 1}}}
+		if err := s.Save(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list := s.List("weibo", time.Time{}, time.Time{})
+	if first := list[0].FetchedAt; now.Sub(first) > retention.Keep || now.Sub(first) < retention.Keep-time.Hour {
+		t.Errorf("oldest snapshot is %s old, want about %s", now.Sub(first), retention.Keep)
+	}
+
+	// 最近 12 小时每分钟一份, 更早的每小时一份
+	var full, sparse int
+	hours := map[time.Time]int{}
+	for _, v := range list {
+		if now.Sub(v.FetchedAt) <= retention.Full {
+			full++
+			continue
+		}
+		sparse++
+		hours[v.FetchedAt.Truncate(time.Hour)]++
+	}
+	if full != 12*60+1 {
+		t.Errorf("got %d snapshots in the last 12h, want %d", full, 12*60+1)
+	}
+	for h, n := range hours {
+		if n != 1 {
+			t.Errorf("got %d snapshots in hour %s, want 1", n, h)
+		}
+	}
+	if want := int((retention.Keep - retention.Full) / time.Hour); sparse < want-1 || sparse > want+1 {
+		t.Errorf("got %d sparse snapshots, want about %d", sparse, want)
+	}
+
+	// 重新加载后保留策略不变
+	reloaded, err := NewStore(s.dir, Retention{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(reloaded.List("weibo", time.Time{}, time.Time{})); got > 2*len(list) {
+		t.Errorf("file has %d snapshots, want at most %d", got, 2*len(list))
+	}
+}
+
+func TestConcurrentSave(t *testing.T) {
+	// 只保留 20ms 内的快照, 保存时频繁重写文件, 重写不能丢掉其他 goroutine 同时追加的快照
+	s, err := NewStore(t.TempDir(), Retention{Keep: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				at := start.Add(time.Duration(i*8+g) * time.Millisecond)
+				if err := s.Save(globals.Snapshot{Platform: "weibo", FetchedAt: at}); err != nil {
+					t.Error(err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	reloaded, err := NewStore(s.dir, Retention{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range s.List("weibo", time.Time{}, time.Time{}) {
+		if _, found := reloaded.Get("weibo", v.Id); !found {
+			t.Errorf("snapshot %s is missing from the file", v.Id)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	s, err := NewStore("", Retention{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 3; i > 0; i-- {
+		s.Save(globals.Snapshot{Platform: "weibo", FetchedAt: now.Add(-time.Duration(i) * time.Hour)})
+	}
+
+	if snap, found := Find(s, "weibo", "150m", now); !found || !snap.FetchedAt.Equal(now.Add(-3*time.Hour)) {
+		t.Errorf("150m = %v, %v", snap.FetchedAt, found)
+	}
+	if snap, found := Find(s, "weibo", NewId(now.Add(-time.Hour)), now); !found || !snap.FetchedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("by id = %v, %v", snap.FetchedAt, found)
+	}
+	// 早于保留的历史时返回未找到, 不取最早的一份
+	if snap, found := Find(s, "weibo", "4h", now); found {
+		t.Errorf("4h = %v, want not found", snap.FetchedAt)
+	}
+}
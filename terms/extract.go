@@ -0,0 +1,74 @@
+package terms
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/turbo-uid/hots/search"
+)
+
+// 中文候选词的长度范围
+const (
+	minGram = 2
+	maxGram = 6
+)
+
+// Candidates 标题中的候选词(去重): 中文 2~6 字片段, 英文单词; 片段不跨过分隔字, 去掉停用词、首尾为虚字的片段和纯数字
+func Candidates(title string) map[string]bool {
+	result := map[string]bool{}
+
+	var run []rune
+	flushCJK := func() {
+		for n := minGram; n <= maxGram; n++ {
+			for k := 0; k+n <= len(run); k++ {
+				gram := run[k : k+n]
+				if stopChars[string(gram[0])] || stopChars[string(gram[n-1])] {
+					continue
+				}
+				if s := string(gram); !stopWords[s] {
+					result[s] = true
+				}
+			}
+		}
+		run = run[:0]
+	}
+
+	var word []rune
+	flushWord := func() {
+		if w := string(word); len(word) >= 2 && !stopWords[w] && strings.IndexFunc(w, unicode.IsLetter) >= 0 {
+			result[w] = true
+		}
+		word = word[:0]
+	}
+
+	for _, r := range search.Normalize(title) {
+		switch {
+		case breakChars[string(r)]:
+			flushCJK()
+			flushWord()
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			run = append(run, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushCJK()
+			flushWord()
+		}
+	}
+	flushCJK()
+	flushWord()
+	return result
+}
+
+// docFreq 每个候选词出现在多少个标题中
+func docFreq(titles []string) map[string]int {
+	result := map[string]int{}
+	for _, t := range titles {
+		for term := range Candidates(t) {
+			result[term]++
+		}
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package terms
+
+import "testing"
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		title   string
+		want    []string
+		notWant []string
+	}{
+		// 中文 2~6 字片段, 停用词不计入
+		{"苹果发布会", []string{"苹果", "发布会", "苹果发布会"}, []string{"发布", "苹"}},
+		// 片段不跨过分隔字
+		{"小米的新车", []string{"小米", "新车"}, []string{"米的", "米的新", "小米的新车"}},
+		// 首尾为虚字的片段去掉
+		{"我爱北京", []string{"爱北", "北京", "爱北京"}, []string{"我爱", "我爱北京"}},
+		// 超过 6 字的片段不计入
+		{"中华民族伟大复兴梦", []string{"中华民族伟大", "民族伟大复兴"}, []string{"中华民族伟大复"}},
+		// 英文转小写, 去掉停用词、单个字母和纯数字; 全角转为半角
+		{"The iPhone 16 Pro 发布", []string{"iphone", "pro"}, []string{"the", "16"}},
+		{"ＧＰＴ-5 上线", []string{"gpt", "上线"}, []string{"5"}},
+	}
+	for _, tt := range tests {
+		got := Candidates(tt.title)
+		for _, v := range tt.want {
+			if !got[v] {
+				t.Errorf("Candidates(%q) missing %q", tt.title, v)
+			}
+		}
+		for _, v := range tt.notWant {
+			if got[v] {
+				t.Errorf("Candidates(%q) contains %q", tt.title, v)
+			}
+		}
+	}
+}
+
+func TestDocFreq(t *testing.T) {
+	// 同一标题中重复出现只计一次
+	got := docFreq([]string{"台风台风来了", "台风登陆", "暴雨"})
+	if got["台风"] != 2 || got["暴雨"] != 1 || got["登陆"] != 1 {
+		t.Errorf("docFreq = %v", got)
+	}
+}
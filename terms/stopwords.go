@@ -0,0 +1,30 @@
+package terms
+
+// 首尾为这些字的片段不作为词
+var stopChars = toSet([]string{
+	"的", "了", "是", "在", "和", "与", "及", "或", "被", "把", "将", "对", "为", "从", "向", "于",
+	"我", "你", "他", "她", "它", "们", "这", "那", "哪", "谁", "啥", "有", "没", "不", "就", "也",
+	"都", "又", "还", "再", "很", "太", "更", "最", "吗", "呢", "吧", "啊", "呀", "哦", "嘛", "之",
+	"其", "个", "着", "过", "得", "地", "让", "给", "等", "却", "而", "但", "并", "且", "如", "若",
+})
+
+// 这些字作为分隔符, 候选词不跨过它们
+var breakChars = toSet([]string{
+	"的", "了", "是", "在", "和", "与", "及", "或", "被", "把", "吗", "呢", "吧", "啊", "呀",
+})
+
+// 热榜标题中常见但没有信息量的词
+var stopWords = toSet([]string{
+	"什么", "怎么", "为什么", "如何", "哪些", "这个", "那个", "一个", "一些", "没有", "不是", "可以",
+	"已经", "今天", "昨天", "明天", "现在", "目前", "最新", "回应", "网友", "官方", "曝光", "热搜",
+	"视频", "消息", "表示", "发布", "进行", "出现", "真的", "竟然", "终于", "原来", "事件", "问题",
+	"the", "and", "for", "with", "you", "are", "from", "this", "that", "new", "how", "what", "why",
+})
+
+func toSet(list []string) map[string]bool {
+	result := map[string]bool{}
+	for _, v := range list {
+		result[v] = true
+	}
+	return result
+}
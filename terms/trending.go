@@ -0,0 +1,124 @@
+package terms
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// Options Window 为统计窗口, Baseline 为窗口之前用于对比的时段
+// Platforms 为空时统计全部平台, 只出现在不足 MinCount 个条目中的词不计入
+type Options struct {
+	Window    time.Duration
+	Baseline  time.Duration
+	Platforms []string
+	Top       int
+	MinCount  int
+}
+
+// titles [from, to] 内快照的标题, 同一条目只计一次
+func titles(store globals.SnapshotStore, flag string, from, to time.Time) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, snap := range store.List(flag, from, to) {
+		for _, v := range snap.Data {
+			if !seen[v.Id] {
+				seen[v.Id] = true
+				result = append(result, v.Title)
+			}
+		}
+	}
+	return result
+}
+
+// Trending 以对比时段为语料计算 IDF, 窗口内出现多、对比时段出现少的词得分高
+func Trending(store globals.SnapshotStore, opts Options, now time.Time) globals.TermsData {
+	from := now.Add(-opts.Window)
+	result := globals.TermsData{
+		Window:    opts.Window.String(),
+		Baseline:  opts.Baseline.String(),
+		From:      from,
+		To:        now,
+		Platforms: map[string][]globals.Term{},
+	}
+
+	platforms := opts.Platforms
+	if len(platforms) == 0 {
+		platforms = store.Platforms()
+	}
+
+	var allCurrent, allBaseline []string
+	for _, flag := range platforms {
+		current := titles(store, flag, from, now)
+		if len(current) == 0 {
+			continue
+		}
+		// 对比时段截止到窗口开始前 1 纳秒, 避免与窗口重叠
+		baseline := titles(store, flag, from.Add(-opts.Baseline), from.Add(-time.Nanosecond))
+
+		if list := rank(current, baseline, opts); len(list) > 0 {
+			result.Platforms[flag] = list
+		}
+		allCurrent = append(allCurrent, current...)
+		allBaseline = append(allBaseline, baseline...)
+	}
+	result.Overall = rank(allCurrent, allBaseline, opts)
+
+	return result
+}
+
+// rank 计算 TF-IDF 并去掉被更长的词覆盖的片段
+func rank(current, baseline []string, opts Options) []globals.Term {
+	counts := docFreq(current)
+	baseCounts := docFreq(baseline)
+
+	var list []globals.Term
+	for term, count := range counts {
+		if count < opts.MinCount {
+			continue
+		}
+		idf := math.Log(float64(len(baseline)+1)/float64(baseCounts[term]+1)) + 1
+		list = append(list, globals.Term{
+			Term:     term,
+			Count:    count,
+			Baseline: baseCounts[term],
+			Score:    float64(count) * idf,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		if len(list[i].Term) != len(list[j].Term) {
+			return len(list[i].Term) > len(list[j].Term)
+		}
+		return list[i].Term < list[j].Term
+	})
+
+	// 子串的出现次数不明显多于已选的长词时, 视为长词的一部分
+	var result []globals.Term
+	for _, t := range list {
+		if len(result) >= opts.Top {
+			break
+		}
+		covered := false
+		for _, s := range result {
+			if strings.Contains(s.Term, t.Term) && float64(t.Count) < 1.5*float64(s.Count) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			result = append(result, t)
+		}
+	}
+
+	for k := range result {
+		result[k].Score = math.Round(result[k].Score*1000) / 1000
+		result[k].Weight = int(math.Max(1, math.Round(100*result[k].Score/result[0].Score)))
+	}
+	return result
+}
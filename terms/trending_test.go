@@ -0,0 +1,77 @@
+package terms
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/snapshots"
+)
+
+func item(id int, title string) globals.GblRespData {
+	return globals.GblRespData{Id: strconv.Itoa(id), Title: title, Pos: id}
+}
+
+func TestTrending(t *testing.T) {
+	store, err := snapshots.NewStore("", snapshots.Retention{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// 对比时段里春节一直在榜, 台风没有出现过
+	var baseline []globals.GblRespData
+	for k := 0; k < 10; k++ {
+		baseline = append(baseline, item(100+k, "春节安排"))
+	}
+	store.Save(globals.Snapshot{Platform: "weibo", FetchedAt: now.Add(-5 * time.Hour), Data: baseline})
+	store.Save(globals.Snapshot{Platform: "weibo", FetchedAt: now.Add(-30 * time.Minute), Data: []globals.GblRespData{
+		item(1, "台风登陆浙江"), item(2, "台风影响航班"), item(3, "台风停课通知"),
+		item(4, "春节档票房"), item(5, "春节返程高峰"), item(6, "春节天气预报"),
+		item(7, "独家专访"),
+	}})
+	// 同一条目在多份快照中只计一次
+	store.Save(globals.Snapshot{Platform: "weibo", FetchedAt: now.Add(-10 * time.Minute), Data: []globals.GblRespData{item(1, "台风登陆浙江")}})
+	store.Save(globals.Snapshot{Platform: "zhihu", FetchedAt: now.Add(-20 * time.Minute), Data: []globals.GblRespData{item(1, "台风路径预测"), item(2, "台风天注意事项")}})
+
+	opts := Options{Window: time.Hour, Baseline: 24 * time.Hour, Top: 10, MinCount: 2}
+	got := Trending(store, opts, now)
+
+	weibo := got.Platforms["weibo"]
+	if len(weibo) != 2 || weibo[0].Term != "台风" || weibo[1].Term != "春节" {
+		t.Fatalf("weibo = %+v, want [台风 春节]", weibo)
+	}
+	// 出现次数相同时, 对比时段中少见的词得分高
+	if weibo[0].Count != 3 || weibo[0].Baseline != 0 || weibo[1].Count != 3 || weibo[1].Baseline != 10 {
+		t.Errorf("weibo = %+v", weibo)
+	}
+	if weibo[0].Weight != 100 || weibo[1].Weight >= weibo[0].Weight || weibo[1].Score != 3 {
+		t.Errorf("weibo = %+v", weibo)
+	}
+
+	if len(got.Overall) == 0 || got.Overall[0].Term != "台风" || got.Overall[0].Count != 5 {
+		t.Errorf("overall = %+v", got.Overall)
+	}
+
+	// 只统计指定的平台
+	opts.Platforms = []string{"zhihu"}
+	got = Trending(store, opts, now)
+	if _, ok := got.Platforms["weibo"]; ok || len(got.Overall) != 1 || got.Overall[0].Term != "台风" {
+		t.Errorf("zhihu only = %+v", got)
+	}
+}
+
+func TestRankCovered(t *testing.T) {
+	// 子串出现次数与长词相同时视为长词的一部分, 明显更多时单独保留
+	current := []string{"杜苏芮台风", "杜苏芮台风", "杜苏芮台风", "台风预警", "台风停课", "台风航班"}
+	got := rank(current, nil, Options{Top: 10, MinCount: 3})
+	if len(got) != 2 || got[0].Term != "台风" || got[1].Term != "杜苏芮台风" {
+		t.Errorf("rank = %+v, want [台风 杜苏芮台风]", got)
+	}
+
+	got = rank(current, nil, Options{Top: 1, MinCount: 1})
+	if len(got) != 1 {
+		t.Errorf("rank with top 1 = %+v", got)
+	}
+}
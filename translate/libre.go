@@ -0,0 +1,48 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// LibreTranslate 自建或公共的 LibreTranslate 服务, q 传数组时一次翻译多条
+type LibreTranslate struct {
+	BaseUrl string
+	ApiKey  string
+	Client  *http.Client
+}
+
+type libreRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	ApiKey string   `json:"api_key,omitempty"`
+}
+
+type libreResponse struct {
+	TranslatedText []string `json:"translatedText"`
+}
+
+func (l *LibreTranslate) Translate(ctx context.Context, texts []string, source, target string) ([]string, error) {
+	if source == "" {
+		source = "auto"
+	}
+
+	var resp libreResponse
+	err := postJSON(ctx, l.Client, l.BaseUrl+"/translate", nil, libreRequest{
+		Q:      texts,
+		Source: source,
+		Target: target,
+		Format: "text",
+		ApiKey: l.ApiKey,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.TranslatedText) != len(texts) {
+		return nil, fmt.Errorf("got %d translations for %d texts", len(resp.TranslatedText), len(texts))
+	}
+	return resp.TranslatedText, nil
+}
@@ -0,0 +1,88 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OpenAI 通过 Chat Completions 接口翻译, 文本以 JSON 数组传入并要求按同样格式返回
+type OpenAI struct {
+	BaseUrl string
+	ApiKey  string
+	Model   string
+	Client  *http.Client
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Temperature float64         `json:"temperature"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (o *OpenAI) Translate(ctx context.Context, texts []string, source, target string) ([]string, error) {
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, err
+	}
+
+	from := ""
+	if source != "" && source != "auto" {
+		from = " from " + source
+	}
+	prompt := fmt.Sprintf("Translate each string in the JSON array%s into the language with code %q. "+
+		"These are trending topic titles: keep names, numbers and hashtags, and do not add explanations. "+
+		"Reply with only a JSON array of strings of the same length and order.", from, target)
+
+	header := http.Header{}
+	if o.ApiKey != "" {
+		header.Set("Authorization", "Bearer "+o.ApiKey)
+	}
+
+	var resp openAIResponse
+	err = postJSON(ctx, o.Client, o.BaseUrl+"/chat/completions", header, openAIRequest{
+		Model: o.Model,
+		Messages: []openAIMessage{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: string(input)},
+		},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("empty choices")
+	}
+
+	// 模型可能用代码块包裹结果
+	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if i := strings.Index(content, "["); i > 0 {
+		content = content[i:]
+	}
+	if i := strings.LastIndex(content, "]"); i >= 0 {
+		content = content[:i+1]
+	}
+
+	var result []string
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("parse translation: %w", err)
+	}
+	if len(result) != len(texts) {
+		return nil, fmt.Errorf("got %d translations for %d texts", len(result), len(texts))
+	}
+	return result, nil
+}
@@ -0,0 +1,308 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/utils"
+	"golang.org/x/sync/singleflight"
+)
+
+// Config 翻译配置, Source 为标题的原语言, 默认 zh
+type Config struct {
+	Source    string          `json:"source,omitempty"`
+	Backends  []BackendConfig `json:"backends"`
+	Languages []Language      `json:"languages"`
+}
+
+// Language 一种目标语言
+// Lang 为 ?lang= 的取值, Target 为后端使用的语言代码, 默认与 Lang 相同
+// Prefetch 为 true 时每次刷新后立即翻译, 否则在首次请求时翻译
+type Language struct {
+	Lang     string `json:"lang"`
+	Target   string `json:"target,omitempty"`
+	Backend  string `json:"backend"`
+	Batch    int    `json:"batch,omitempty"`
+	Prefetch bool   `json:"prefetch,omitempty"`
+}
+
+type language struct {
+	Language
+	translator Translator
+}
+
+// Default 进程内的翻译流水线, 未配置时不支持任何语言
+var Default = NewPipeline()
+
+// Pipeline 按语言翻译标题, 译文按 语言+标题哈希 缓存在 GoCache, 标题不变时不会重复翻译
+// 同时发起的相同批次只请求一次后端, 失败的文本短时间内不再重试
+type Pipeline struct {
+	mu     sync.RWMutex
+	source string
+	langs  map[string]language
+	queue  chan globals.Snapshot
+	group  singleflight.Group
+	failed *cache.Cache
+}
+
+func NewPipeline() *Pipeline {
+	return &Pipeline{
+		langs:  map[string]language{},
+		queue:  make(chan globals.Snapshot, 64),
+		failed: cache.New(cache.NoExpiration, 10*time.Minute),
+	}
+}
+
+// Load 从 JSON 文件读取配置
+func (p *Pipeline) Load(file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return fmt.Errorf("parse %s: %w", file, err)
+	}
+	return p.Configure(cfg)
+}
+
+// Configure 替换全部后端和语言
+func (p *Pipeline) Configure(cfg Config) error {
+	backends := map[string]Translator{}
+	for _, b := range cfg.Backends {
+		t, err := New(b)
+		if err != nil {
+			return fmt.Errorf("translator %s: %w", b.Name, err)
+		}
+		backends[b.Name] = t
+	}
+
+	langs := map[string]language{}
+	for _, l := range cfg.Languages {
+		if l.Lang == "" {
+			return errors.New("lang is required")
+		}
+		t, ok := backends[l.Backend]
+		if !ok {
+			return fmt.Errorf("lang %s: unknown backend %s", l.Lang, l.Backend)
+		}
+		if l.Target == "" {
+			l.Target = l.Lang
+		}
+		if l.Batch <= 0 {
+			l.Batch = globals.TranslateBatch
+		}
+		langs[l.Lang] = language{Language: l, translator: t}
+	}
+
+	source := cfg.Source
+	if source == "" {
+		source = "zh"
+	}
+
+	p.mu.Lock()
+	p.source = source
+	p.langs = langs
+	p.mu.Unlock()
+	// 换了后端后立即重试之前失败的文本
+	p.failed.Flush()
+	return nil
+}
+
+// Langs 已配置的语言
+func (p *Pipeline) Langs() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var result []string
+	for k := range p.langs {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (p *Pipeline) Supported(lang string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, ok := p.langs[lang]
+	return ok
+}
+
+// Translate 翻译一组文本, 先读缓存, 未命中的去重后按批请求后端
+// 部分批次失败或文本最近翻译失败时对应位置保留原文并返回错误
+func (p *Pipeline) Translate(ctx context.Context, lang string, texts []string) ([]string, error) {
+	p.mu.RLock()
+	l, ok := p.langs[lang]
+	source := p.source
+	p.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported lang: %s", lang)
+	}
+
+	result := make([]string, len(texts))
+	missing := map[string][]int{}
+	var pending []string
+	skipped := map[string]bool{}
+	for i, text := range texts {
+		if text == "" {
+			continue
+		}
+		key := utils.GetTranslateKey(lang, text)
+		var cached string
+		if globals.GoCache.Get(key, &cached) {
+			result[i] = cached
+			continue
+		}
+		result[i] = text
+		if _, failed := p.failed.Get(key); failed {
+			skipped[text] = true
+			continue
+		}
+		if _, found := missing[text]; !found {
+			pending = append(pending, text)
+		}
+		missing[text] = append(missing[text], i)
+	}
+
+	var errs []error
+	if len(skipped) > 0 {
+		errs = append(errs, fmt.Errorf("%d texts failed recently", len(skipped)))
+	}
+	for start := 0; start < len(pending); start += l.Batch {
+		batch := pending[start:min(start+l.Batch, len(pending))]
+		translated, err := p.translateBatch(ctx, l, source, batch)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for k, text := range batch {
+			for _, i := range missing[text] {
+				result[i] = translated[k]
+			}
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// translateBatch 请求后端翻译一批文本并写入缓存, 正在进行的相同批次直接等待其结果; 返回的切片不可修改
+// 批次由多个调用方共享, 不随某个调用方取消, 超时按 TranslateTimeout 计算, 调用方超时后结果仍会写入缓存
+func (p *Pipeline) translateBatch(ctx context.Context, l language, source string, batch []string) ([]string, error) {
+	flight := utils.GetTranslateKey(l.Lang, strings.Join(batch, "\n"))
+	ch := p.group.DoChan(flight, func() (interface{}, error) {
+		batchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), globals.TranslateTimeout)
+		defer cancel()
+
+		translated, err := l.translator.Translate(batchCtx, batch, source, l.Target)
+		if err != nil {
+			for _, text := range batch {
+				p.failed.Set(utils.GetTranslateKey(l.Lang, text), true, globals.TranslateFailedExpired)
+			}
+			return nil, err
+		}
+
+		globals.GoLogger.Infof("TRANSLATE %s %d TEXTS", l.Lang, len(batch))
+		for k, text := range batch {
+			globals.GoCache.Set(utils.GetTranslateKey(l.Lang, text), translated[k], globals.TranslateCacheExpired)
+		}
+		return translated, nil
+	})
+
+	select {
+	case r := <-ch:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Val.([]string), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Apply 将榜单标题替换为译文, 原标题保存在 extra.original_title; 不修改传入的数据
+func (p *Pipeline) Apply(ctx context.Context, resultResp globals.GblResp, lang string) (globals.GblResp, error) {
+	titles := make([]string, len(resultResp.Data))
+	for k, v := range resultResp.Data {
+		titles[k] = v.Title
+	}
+
+	translated, err := p.Translate(ctx, lang, titles)
+	if translated == nil {
+		return resultResp, err
+	}
+
+	data := make([]globals.GblRespData, len(resultResp.Data))
+	for k, v := range resultResp.Data {
+		if translated[k] != v.Title {
+			extra := globals.GblExtra{}
+			for ek, ev := range v.Extra {
+				extra[ek] = ev
+			}
+			extra[globals.ExtraOriginalTitle] = v.Title
+			v.Extra = extra
+			v.Title = translated[k]
+		}
+		data[k] = v
+	}
+	resultResp.Data = data
+	return resultResp, err
+}
+
+// Add 刷新后提交快照, 由 Run 在后台翻译 Prefetch 语言; 队列满时丢弃, 首次请求时再翻译
+func (p *Pipeline) Add(snap globals.Snapshot) {
+	if len(p.prefetchLangs()) == 0 {
+		return
+	}
+
+	select {
+	case p.queue <- snap:
+	default:
+		globals.GoLogger.Warnf("TRANSLATE QUEUE FULL, SKIP %s", snap.Platform)
+	}
+}
+
+// Run 处理 Add 提交的快照, 一般在启动时以 goroutine 运行
+func (p *Pipeline) Run() {
+	for snap := range p.queue {
+		langs := p.prefetchLangs()
+
+		titles := make([]string, len(snap.Data))
+		for k, v := range snap.Data {
+			titles[k] = v.Title
+		}
+
+		for _, lang := range langs {
+			ctx, cancel := context.WithTimeout(context.Background(), globals.TranslateTimeout)
+			if _, err := p.Translate(ctx, lang, titles); err != nil {
+				globals.GoLogger.Errorf("TRANSLATE %s %s ERR %s", snap.Platform, lang, err.Error())
+			}
+			cancel()
+		}
+	}
+}
+
+func (p *Pipeline) prefetchLangs() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var result []string
+	for k, l := range p.langs {
+		if l.Prefetch {
+			result = append(result, k)
+		}
+	}
+	return result
+}
@@ -0,0 +1,319 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/caches"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/utils"
+)
+
+func setup(t *testing.T) {
+	t.Helper()
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+	globals.GoCache = caches.NewMemory(cache.New(time.Hour, time.Hour))
+	t.Cleanup(func() { globals.GoCache = nil })
+}
+
+// fakeLibre 模拟 LibreTranslate, 译文为 "target:原文", 记录每次请求的条数
+type fakeLibre struct {
+	mu      sync.Mutex
+	batches [][]string
+	fail    bool
+	// 不为空时等待关闭后再返回
+	hold chan struct{}
+}
+
+func (f *fakeLibre) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req libreRequest
+	if r.URL.Path != "/translate" || json.NewDecoder(r.Body).Decode(&req) != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	f.batches = append(f.batches, req.Q)
+	f.mu.Unlock()
+
+	if f.hold != nil {
+		<-f.hold
+	}
+	if f.fail {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		return
+	}
+
+	var resp libreResponse
+	for _, q := range req.Q {
+		resp.TranslatedText = append(resp.TranslatedText, req.Target+":"+q)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeLibre) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.batches)
+}
+
+func newLibrePipeline(t *testing.T, f *fakeLibre, batch int) *Pipeline {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	p := NewPipeline()
+	err := p.Configure(Config{
+		Backends:  []BackendConfig{{Name: "libre", Type: TypeLibreTranslate, BaseUrl: srv.URL}},
+		Languages: []Language{{Lang: "en", Backend: "libre", Batch: batch}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestTranslateBatchAndCache(t *testing.T) {
+	setup(t)
+	f := &fakeLibre{}
+	p := newLibrePipeline(t, f, 2)
+
+	texts := []string{"一", "二", "", "三", "一", "四", "五"}
+	got, err := p.Translate(context.Background(), "en", texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"en:一", "en:二", "", "en:三", "en:一", "en:四", "en:五"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// 去重后 5 条, 每批 2 条
+	if n := f.calls(); n != 3 {
+		t.Fatalf("backend called %d times, want 3", n)
+	}
+	for k, batch := range f.batches {
+		if len(batch) > 2 {
+			t.Errorf("batch %d has %d texts", k, len(batch))
+		}
+	}
+
+	// 已缓存的不再请求后端
+	got, err = p.Translate(context.Background(), "en", []string{"三", "一", "六"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "en:三,en:一,en:六" {
+		t.Errorf("got %q", got)
+	}
+	if n := f.calls(); n != 4 || len(f.batches[3]) != 1 || f.batches[3][0] != "六" {
+		t.Errorf("after cache hits batches = %q, want one more batch with 六", f.batches)
+	}
+}
+
+func TestTranslateFailureCached(t *testing.T) {
+	setup(t)
+	f := &fakeLibre{fail: true}
+	p := newLibrePipeline(t, f, 50)
+
+	got, err := p.Translate(context.Background(), "en", []string{"一", "二"})
+	if err == nil {
+		t.Fatal("want error from failing backend")
+	}
+	if strings.Join(got, ",") != "一,二" {
+		t.Errorf("got %q, want originals", got)
+	}
+
+	// 失败后短时间内不再请求后端
+	if _, err := p.Translate(context.Background(), "en", []string{"一", "二"}); err == nil {
+		t.Error("want error for recently failed texts")
+	}
+	if n := f.calls(); n != 1 {
+		t.Errorf("backend called %d times, want 1", n)
+	}
+
+	// 过期后重试
+	f.fail = false
+	p.failed.Flush()
+	if _, err := p.Translate(context.Background(), "en", []string{"一", "二"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := f.calls(); n != 2 {
+		t.Errorf("backend called %d times, want 2", n)
+	}
+}
+
+func TestTranslateSingleflight(t *testing.T) {
+	setup(t)
+	f := &fakeLibre{hold: make(chan struct{})}
+	p := newLibrePipeline(t, f, 50)
+
+	texts := []string{"一", "二"}
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := p.Translate(context.Background(), "en", texts)
+			if err != nil || strings.Join(got, ",") != "en:一,en:二" {
+				failed.Add(1)
+			}
+		}()
+	}
+
+	// 等第一个请求到达后端, 其余请求加入同一批次后再返回
+	for f.calls() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(f.hold)
+	wg.Wait()
+
+	if n := failed.Load(); n > 0 {
+		t.Errorf("%d translations failed", n)
+	}
+	if n := f.calls(); n != 1 {
+		t.Errorf("backend called %d times, want 1", n)
+	}
+}
+
+// TestTranslateCallerCancel 调用方取消或超时不影响共享批次, 后端返回后仍写入缓存
+func TestTranslateCallerCancel(t *testing.T) {
+	setup(t)
+	f := &fakeLibre{hold: make(chan struct{})}
+	p := newLibrePipeline(t, f, 50)
+	texts := []string{"一", "二"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := p.Translate(ctx, "en", texts)
+		first <- err
+	}()
+	for f.calls() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	second := make(chan []string, 1)
+	go func() {
+		got, _ := p.Translate(context.Background(), "en", texts)
+		second <- got
+	}()
+
+	// 第一个调用方取消后立即返回, 第二个继续等待同一批次
+	cancel()
+	select {
+	case err := <-first:
+		if err == nil {
+			t.Error("cancelled caller got no error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancelled caller still waiting")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(f.hold)
+	if got := <-second; strings.Join(got, ",") != "en:一,en:二" {
+		t.Errorf("second caller got %q", got)
+	}
+
+	// 超时的调用方拿到原文, 后端返回后译文写入缓存, 之后不再请求后端
+	f.hold = make(chan struct{})
+	slow := []string{"三"}
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	got, err := p.Translate(ctx, "en", slow)
+	if err == nil || strings.Join(got, ",") != "三" {
+		t.Errorf("timed out caller got %q, %v", got, err)
+	}
+	close(f.hold)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		var cached string
+		if globals.GoCache.Get(utils.GetTranslateKey("en", "三"), &cached) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("slow batch not cached")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	calls := f.calls()
+	if got, err := p.Translate(context.Background(), "en", slow); err != nil || got[0] != "en:三" || f.calls() != calls {
+		t.Errorf("after slow batch got %q, %v, %d backend calls", got, err, f.calls()-calls)
+	}
+}
+
+func TestOpenAI(t *testing.T) {
+	setup(t)
+	var got openAIRequest
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+
+		var texts []string
+		json.Unmarshal([]byte(got.Messages[1].Content), &texts)
+		for k := range texts {
+			texts[k] = "EN " + texts[k]
+		}
+		content, _ := json.Marshal(texts)
+		// 模型常用代码块包裹结果
+		reply := "```json\n" + string(content) + "\n```"
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []interface{}{map[string]interface{}{"message": openAIMessage{Role: "assistant", Content: reply}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewPipeline()
+	err := p.Configure(Config{
+		Backends:  []BackendConfig{{Name: "llm", Type: TypeOpenAI, BaseUrl: srv.URL + "/v1/", ApiKey: "sk-test", Model: "gpt-test"}},
+		Languages: []Language{{Lang: "en", Backend: "llm"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := p.Translate(context.Background(), "en", []string{"苹果发布会", "天气"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(result, ",") != "EN 苹果发布会,EN 天气" {
+		t.Errorf("got %q", result)
+	}
+	if auth != "Bearer sk-test" || got.Model != "gpt-test" || len(got.Messages) != 2 {
+		t.Errorf("auth = %q, request = %+v", auth, got)
+	}
+	if !strings.Contains(got.Messages[0].Content, `from zh into the language with code "en"`) {
+		t.Errorf("prompt = %q", got.Messages[0].Content)
+	}
+}
+
+func TestOpenAILengthMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"choices":[{"message":{"role":"assistant","content":"[\"only one\"]"}}]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	o := &OpenAI{BaseUrl: srv.URL, Model: "m", Client: srv.Client()}
+	if _, err := o.Translate(context.Background(), []string{"a", "b"}, "zh", "en"); err == nil || !strings.Contains(err.Error(), "1 translations for 2 texts") {
+		t.Errorf("err = %v", err)
+	}
+}
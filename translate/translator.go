@@ -0,0 +1,89 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 翻译后端类型
+const (
+	TypeOpenAI         = "openai"
+	TypeLibreTranslate = "libretranslate"
+)
+
+// Translator 翻译后端, 一次翻译一批文本, 结果与输入一一对应
+type Translator interface {
+	Translate(ctx context.Context, texts []string, source, target string) ([]string, error)
+}
+
+// BackendConfig 一个翻译后端, Name 供语言配置引用
+// openai 类型兼容 OpenAI Chat Completions 接口, BaseUrl 形如 https://api.openai.com/v1
+type BackendConfig struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	BaseUrl string `json:"base_url"`
+	ApiKey  string `json:"api_key,omitempty"`
+	Model   string `json:"model,omitempty"`
+}
+
+// New 按类型创建翻译后端
+func New(cfg BackendConfig) (Translator, error) {
+	if cfg.Name == "" || cfg.BaseUrl == "" {
+		return nil, errors.New("name and base_url are required")
+	}
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	baseUrl := strings.TrimRight(cfg.BaseUrl, "/")
+	switch cfg.Type {
+	case TypeOpenAI:
+		if cfg.Model == "" {
+			return nil, errors.New("model is required")
+		}
+		return &OpenAI{BaseUrl: baseUrl, ApiKey: cfg.ApiKey, Model: cfg.Model, Client: client}, nil
+	case TypeLibreTranslate:
+		return &LibreTranslate{BaseUrl: baseUrl, ApiKey: cfg.ApiKey, Client: client}, nil
+	}
+	return nil, fmt.Errorf("unknown translator type: %s", cfg.Type)
+}
+
+// postJSON 发送 JSON 请求并解析 JSON 响应, 非 200 时返回响应内容
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(content) > 512 {
+			content = content[:512]
+		}
+		return fmt.Errorf("%s: %s", resp.Status, content)
+	}
+	return json.Unmarshal(content, result)
+}
@@ -0,0 +1,182 @@
+package upstream
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 录制模式
+const (
+	// ModeRecord 请求上游并把响应保存为 fixture
+	ModeRecord = "record"
+	// ModeReplay 只从 fixture 返回响应, 不访问网络
+	ModeReplay = "replay"
+)
+
+// Fixture 一次录制的请求和响应
+// 响应内容为合法 UTF-8 时以文本保存, 便于查看和手工编辑, 否则以 base64 保存在 body_base64
+// 不记录录制时间: fixture 也可能来自 cmd/mockupstream 或手写, 来源以提交记录为准
+type Fixture struct {
+	Method     string            `json:"method"`
+	Url        string            `json:"url"`
+	Status     int               `json:"status"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	BodyBase64 string            `json:"body_base64,omitempty"`
+}
+
+// Recorder 按 方法+URL 录制或回放上游响应, fixture 保存在 dir/<host>/<hash>.json
+// 查询参数按名称排序后参与匹配, 请求体不参与匹配
+type Recorder struct {
+	mu      sync.Mutex
+	mode    string
+	dir     string
+	base    http.RoundTripper
+	missing []string
+}
+
+// NewRecorder base 为录制时实际请求上游的实现, 为 nil 时使用 http.DefaultTransport
+func NewRecorder(mode, dir string, base http.RoundTripper) (*Recorder, error) {
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil, fmt.Errorf("unknown mode: %s", mode)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Recorder{mode: mode, dir: dir, base: base}, nil
+}
+
+// FixturePath 请求对应的 fixture 文件
+func (r *Recorder) FixturePath(method string, u *url.URL) string {
+	key := method + " " + canonicalUrl(u)
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(r.dir, u.Hostname(), hex.EncodeToString(sum[:8])+".json")
+}
+
+func canonicalUrl(u *url.URL) string {
+	c := *u
+	c.RawQuery = u.Query().Encode()
+	c.Fragment = ""
+	return c.String()
+}
+
+// TakeMissing 返回上次调用以来回放时找不到 fixture 的请求
+func (r *Recorder) TakeMissing() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := r.missing
+	r.missing = nil
+	return result
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := r.FixturePath(req.Method, req.URL)
+	if r.mode == ModeReplay {
+		return r.replay(req, path)
+	}
+	return r.record(req, path)
+}
+
+func (r *Recorder) replay(req *http.Request, path string) (*http.Response, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			r.mu.Lock()
+			r.missing = append(r.missing, req.Method+" "+req.URL.String())
+			r.mu.Unlock()
+			return nil, fmt.Errorf("no fixture for %s %s", req.Method, req.URL)
+		}
+		return nil, err
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf("parse fixture %s: %w", path, err)
+	}
+
+	body := []byte(f.Body)
+	if f.BodyBase64 != "" {
+		if body, err = base64.StdEncoding.DecodeString(f.BodyBase64); err != nil {
+			return nil, fmt.Errorf("parse fixture %s: %w", path, err)
+		}
+	}
+
+	header := http.Header{}
+	for k, v := range f.Header {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, path string) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	f := Fixture{
+		Method: req.Method,
+		Url:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: map[string]string{},
+	}
+	// 只保存影响解析的响应头
+	for _, k := range []string{"Content-Type", "Content-Encoding", "Location"} {
+		if v := resp.Header.Get(k); v != "" {
+			f.Header[k] = v
+		}
+	}
+	if utf8.Valid(body) && !strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		f.Body = string(body)
+	} else {
+		f.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return nil, err
+	}
+	content := buf.Bytes()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,164 @@
+package upstream
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func get(t *testing.T, rt http.RoundTripper, rawUrl string) (*http.Response, []byte, error) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, rawUrl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, body, nil
+}
+
+func TestRecordReplay(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Ignored", "1")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"title":"热搜"}`)
+	}))
+	t.Cleanup(srv.Close)
+	dir := t.TempDir()
+
+	recorder, err := NewRecorder(ModeRecord, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, body, err := get(t, recorder, srv.URL+"/list?b=2&a=1"); err != nil || string(body) != `{"title":"热搜"}` {
+		t.Fatalf("record body = %s, err = %v", body, err)
+	}
+
+	// 文本响应以 body 保存, 只保留影响解析的响应头
+	u, _ := url.Parse(srv.URL + "/list?b=2&a=1")
+	content, err := os.ReadFile(recorder.FixturePath(http.MethodGet, u))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f Fixture
+	if err := json.Unmarshal(content, &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Body != `{"title":"热搜"}` || f.BodyBase64 != "" || f.Status != http.StatusCreated || len(f.Header) != 1 || f.Header["Content-Type"] != "application/json" {
+		t.Errorf("fixture = %+v", f)
+	}
+
+	// 回放不访问上游, 查询参数顺序和 fragment 不影响匹配
+	replay, err := NewRecorder(ModeReplay, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, body, err := get(t, replay, srv.URL+"/list?a=1&b=2#top")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("Content-Type") != "application/json" || string(body) != `{"title":"热搜"}` {
+		t.Errorf("replay = %d %v %s", resp.StatusCode, resp.Header, body)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("upstream called %d times, want 1", calls.Load())
+	}
+}
+
+func TestRecordBinary(t *testing.T) {
+	// GBK 编码的 "你好", 不是合法的 UTF-8
+	gbk := []byte{0xc4, 0xe3, 0xba, 0xc3}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=gbk")
+		w.Write(gbk)
+	}))
+	t.Cleanup(srv.Close)
+	dir := t.TempDir()
+
+	recorder, _ := NewRecorder(ModeRecord, dir, nil)
+	if _, _, err := get(t, recorder, srv.URL+"/gbk"); err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(srv.URL + "/gbk")
+	content, err := os.ReadFile(recorder.FixturePath(http.MethodGet, u))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f Fixture
+	json.Unmarshal(content, &f)
+	if f.Body != "" || f.BodyBase64 != "xOO6ww==" {
+		t.Errorf("fixture = %+v", f)
+	}
+
+	replay, _ := NewRecorder(ModeReplay, dir, nil)
+	if _, body, err := get(t, replay, srv.URL+"/gbk"); err != nil || string(body) != string(gbk) {
+		t.Errorf("replay body = %x, err = %v", body, err)
+	}
+}
+
+func TestFixturePath(t *testing.T) {
+	r, _ := NewRecorder(ModeReplay, "fixtures", nil)
+	path := func(method, rawUrl string) string {
+		u, err := url.Parse(rawUrl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.FixturePath(method, u)
+	}
+
+	base := path(http.MethodGet, "https://weibo.com/ajax?a=1&b=2")
+	if !strings.HasPrefix(base, "fixtures/weibo.com/") || !strings.HasSuffix(base, ".json") {
+		t.Errorf("path = %s", base)
+	}
+	for _, v := range []string{"https://weibo.com/ajax?b=2&a=1", "https://weibo.com/ajax?a=1&b=2#x", "https://weibo.com/ajax?b=2&a=%31"} {
+		if got := path(http.MethodGet, v); got != base {
+			t.Errorf("%s -> %s, want %s", v, got, base)
+		}
+	}
+	for _, v := range []string{"https://weibo.com/ajax?a=1&b=3", "https://weibo.com/ajax?a=1", "https://weibo.com/other?a=1&b=2"} {
+		if got := path(http.MethodGet, v); got == base {
+			t.Errorf("%s matches %s", v, base)
+		}
+	}
+	if path(http.MethodPost, "https://weibo.com/ajax?a=1&b=2") == base {
+		t.Error("POST matches GET")
+	}
+}
+
+func TestTakeMissing(t *testing.T) {
+	r, _ := NewRecorder(ModeReplay, t.TempDir(), nil)
+	if _, _, err := get(t, r, "https://weibo.com/nope?x=1"); err == nil || !strings.Contains(err.Error(), "no fixture") {
+		t.Fatalf("err = %v", err)
+	}
+	get(t, r, "https://zhihu.com/nope")
+
+	got := r.TakeMissing()
+	if len(got) != 2 || got[0] != "GET https://weibo.com/nope?x=1" || got[1] != "GET https://zhihu.com/nope" {
+		t.Errorf("missing = %v", got)
+	}
+	if got := r.TakeMissing(); len(got) != 0 {
+		t.Errorf("second take = %v", got)
+	}
+}
+
+func TestNewRecorderMode(t *testing.T) {
+	if _, err := NewRecorder("live", "", nil); err == nil {
+		t.Error("unknown mode accepted")
+	}
+}
@@ -0,0 +1,40 @@
+package upstream
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// HeaderHost 改发到 mock 服务时, 原始上游的主机名放在该请求头中
+const HeaderHost = "X-Upstream-Host"
+
+// Redirect 把所有上游请求改发到 target (如本地运行的 cmd/mockupstream), 路径和查询参数保持不变
+type Redirect struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+// NewRedirect target 为 mock 服务地址, 如 http://127.0.0.1:9090; base 为 nil 时使用 http.DefaultTransport
+func NewRedirect(target string, base http.RoundTripper) (*Redirect, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid mock url: %s", target)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Redirect{target: u, base: base}, nil
+}
+
+func (r *Redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.URL.Scheme = r.target.Scheme
+	out.URL.Host = r.target.Host
+	out.Host = r.target.Host
+	out.Header.Set(HeaderHost, req.URL.Hostname())
+	return r.base.RoundTrip(out)
+}
@@ -0,0 +1,46 @@
+package upstream
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirect(t *testing.T) {
+	var host, uri string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, uri = r.Header.Get(HeaderHost), r.URL.RequestURI()
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+
+	r, err := NewRedirect(srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://weibo.com:443/ajax/side/hotSearch?a=1", nil)
+	resp, err := r.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	// 路径和查询参数不变, 原始主机名(不含端口)放在请求头中
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" || host != "weibo.com" || uri != "/ajax/side/hotSearch?a=1" {
+		t.Errorf("got %d %s, host %q, uri %q", resp.StatusCode, body, host, uri)
+	}
+	// 不修改原请求
+	if req.URL.Host != "weibo.com:443" || req.Header.Get(HeaderHost) != "" {
+		t.Errorf("request modified: %s %v", req.URL, req.Header)
+	}
+}
+
+func TestNewRedirectInvalid(t *testing.T) {
+	for _, v := range []string{"127.0.0.1:9090", "ftp://127.0.0.1", "http://", "http://[::1"} {
+		if _, err := NewRedirect(v, nil); err == nil {
+			t.Errorf("%q accepted", v)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package upstream
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Transport 所有上游请求共用的 RoundTripper, 录制或回放时通过 Use 替换实际的实现
+var Transport = &transport{rt: http.DefaultTransport}
+
+// Client 请求上游榜单使用的客户端, 自定义超时等设置时用 Transport 创建新客户端
+var Client = &http.Client{Transport: Transport}
+
+// Get 同 http.Get, 经过 Transport
+func Get(url string) (*http.Response, error) {
+	return Client.Get(url)
+}
+
+// Use 替换上游请求的实现, 如 Recorder
+func Use(rt http.RoundTripper) {
+	Transport.mu.Lock()
+	Transport.rt = rt
+	Transport.mu.Unlock()
+}
+
+type transport struct {
+	mu sync.RWMutex
+	rt http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	rt := t.rt
+	t.mu.RUnlock()
+	return rt.RoundTrip(req)
+}
@@ -1,9 +1,14 @@
 package utils
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 func GetHotCacheKey(flag string) string {
@@ -14,6 +19,63 @@ func GetHotCacheKey(flag string) string {
 	return k
 }
 
+// GetHotStaleKey 上游失败时返回的旧榜单
+func GetHotStaleKey(flag string) string {
+	return "hot_stale_" + flag
+}
+
+// GetHotLockKey 刷新锁
+func GetHotLockKey(flag string) string {
+	return "hot_lock_" + flag
+}
+
+// GetHotSnapshotKey leader 最新保存的快照
+func GetHotSnapshotKey(flag string) string {
+	return "hot_snapshot_" + flag
+}
+
+// GetAlertFiredKey 关注规则对某一条目最近一次告警的时间, 用于多实例和重启后去重
+func GetAlertFiredKey(ruleId, itemId string) string {
+	return "alert_fired_" + ruleId + "_" + itemId
+}
+
+// GetApiKeysKey leader 最新的 API Key 列表, 供从实例同步
+func GetApiKeysKey() string {
+	return "api_keys"
+}
+
+// GetTranslateKey 译文缓存, 按语言和原文哈希
+func GetTranslateKey(lang, text string) string {
+	sum := sha1.Sum([]byte(text))
+	return "tr_" + lang + "_" + hex.EncodeToString(sum[:])
+}
+
+// GetItemId 生成稳定的条目ID: 有上游ID时为 "平台:上游ID", 否则为 "平台:t" + 规范化标题的哈希
+func GetItemId(flag, upstreamId, title string) string {
+	prefix := flag + ":"
+	if strings.HasPrefix(upstreamId, prefix) {
+		return upstreamId
+	}
+	if upstreamId != "" {
+		return prefix + upstreamId
+	}
+
+	sum := sha1.Sum([]byte(NormalizeTitle(title)))
+	return prefix + "t" + hex.EncodeToString(sum[:])[:16]
+}
+
+// NormalizeTitle 去掉空白、话题符号并统一大小写, 用于判断是否为同一条目
+func NormalizeTitle(title string) string {
+	var result strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsSpace(r) || r == '#' {
+			continue
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
 func GetTimestamp() int64 {
 	t := time.Now().Unix()
 
@@ -29,3 +91,71 @@ func RemoveChar(str, charToRemove string) string {
 	}
 	return result.String()
 }
+
+// 整个文本中只能有一个数字, 前后可以有不含数字的文字
+var countRex = regexp.MustCompile(`^\D*?(\d+(?:\.\d+)?)\s*(亿|万|w|W)?\D*$`)
+
+// ParseCount 解析上游返回的计数或热度文本, 如 "1234"、"1,234"、"1.2万"、"热1234"、"345 万热度"
+// 无法解析或含有多个数字(如 "01-15")时返回 0
+func ParseCount(str string) int64 {
+	match := countRex.FindStringSubmatch(strings.ReplaceAll(str, ",", ""))
+	if match == nil {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0
+	}
+
+	switch match[2] {
+	case "亿":
+		f *= 1e8
+	case "万", "w", "W":
+		f *= 1e4
+	}
+	return int64(f)
+}
+
+// ParseTime 解析查询参数中的时间: 相对时长(如 30m、2h, 表示 now 之前)、Unix 秒/毫秒时间戳或 RFC3339
+func ParseTime(str string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(str); err == nil {
+		return now.Add(-d), nil
+	}
+
+	if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+		if n > 1e12 {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+
+	return time.Parse(time.RFC3339, str)
+}
+
+var relativeTimeRex = regexp.MustCompile(`^(\d+)\s*(秒|分钟|小时|天)前$`)
+
+// 上游发布时间的绝对格式, 不带年份的 "01-15" 无法确定年份, 不解析
+var absoluteTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// ParseRelativeTime 解析上游返回的发布时间, 支持 "刚刚"、"3小时前" 这样的相对时间和 absoluteTimeLayouts 中的日期
+// 不接受 ParseTime 的时长和时间戳, "15"、"2h" 这样的文本返回错误
+func ParseRelativeTime(str string, now time.Time) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	if str == "刚刚" {
+		return now, nil
+	}
+
+	if match := relativeTimeRex.FindStringSubmatch(str); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		unit := map[string]time.Duration{"秒": time.Second, "分钟": time.Minute, "小时": time.Hour, "天": 24 * time.Hour}[match[2]]
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+
+	for _, layout := range absoluteTimeLayouts {
+		if t, err := time.ParseInLocation(layout, str, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s", str)
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := map[string]int64{
+		"1234":    1234,
+		"1,234":   1234,
+		"1.2万":    12000,
+		"3.5亿":    350000000,
+		"12w":     120000,
+		"热1234":   1234,
+		"345 万热度": 3450000,
+		"评论 12 条": 12,
+		"":        0,
+		"热":       0,
+		"01-15":   0,
+		"1.2.3":   0,
+		"12 / 34": 0,
+		"第3名 100": 0,
+	}
+	for in, want := range tests {
+		if got := ParseCount(in); got != want {
+			t.Errorf("ParseCount(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, loc)
+
+	tests := map[string]time.Time{
+		"刚刚":                        now,
+		"30秒前":                      now.Add(-30 * time.Second),
+		"3小时前":                      now.Add(-3 * time.Hour),
+		"2 天前":                      now.Add(-48 * time.Hour),
+		"2026-03-09T08:00:00+08:00": time.Date(2026, 3, 9, 8, 0, 0, 0, loc),
+		"2026-03-09 08:30:15":       time.Date(2026, 3, 9, 8, 30, 15, 0, loc),
+		"2026-03-09 08:30":          time.Date(2026, 3, 9, 8, 30, 0, 0, loc),
+		"2026-03-09":                time.Date(2026, 3, 9, 0, 0, 0, 0, loc),
+	}
+	for in, want := range tests {
+		got, err := ParseRelativeTime(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseRelativeTime(%q) = %s, %v, want %s", in, got, err, want)
+		}
+	}
+
+	// 查询参数中的时长和时间戳不是发布时间, 不带年份的日期同样不解析
+	for _, in := range []string{"15", "2h", "1700000000", "01-15", "昨天", "3小时后"} {
+		if got, err := ParseRelativeTime(in, now); err == nil {
+			t.Errorf("ParseRelativeTime(%q) = %s, want error", in, got)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"30m":                  now.Add(-30 * time.Minute),
+		"1700000000":           time.Unix(1700000000, 0),
+		"1700000000123":        time.UnixMilli(1700000000123),
+		"2026-03-09T08:00:00Z": time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		if got, err := ParseTime(in, now); err != nil || !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %s, %v, want %s", in, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/turbo-uid/hots/events"
+	"github.com/turbo-uid/hots/globals"
+	"github.com/turbo-uid/hots/leader"
+)
+
+// Default 进程内的 Webhook 投递器, 不会投递到内网地址
+var Default = NewDispatcher(newClient())
+
+var eventTypes = map[string]bool{
+	globals.WebhookBoardUpdated: true,
+	globals.WebhookNewEntry:     true,
+	globals.WebhookAlertFired:   true,
+	globals.WebhookProviderDown: true,
+}
+
+// Dispatcher 保存 Webhook, 将事件签名后投递并记录每次尝试
+type Dispatcher struct {
+	mu         sync.Mutex
+	client     *http.Client
+	file       string
+	hooks      []globals.Webhook
+	deliveries []*globals.Delivery
+}
+
+func NewDispatcher(client *http.Client) *Dispatcher {
+	return &Dispatcher{client: client}
+}
+
+func newId() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Load 从 file 读取 Webhook, 之后的变化都会写回 file; file 不存在时视为没有 Webhook
+func (d *Dispatcher) Load(file string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.file = file
+
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// 解析失败时保留之前的 Webhook
+	var hooks []globals.Webhook
+	if err := json.Unmarshal(content, &hooks); err != nil {
+		return fmt.Errorf("parse %s: %w", file, err)
+	}
+	d.hooks = hooks
+	return nil
+}
+
+// save 调用方需持有锁
+func (d *Dispatcher) save() {
+	if d.file == "" {
+		return
+	}
+
+	content, err := json.MarshalIndent(d.hooks, "", "  ")
+	if err == nil {
+		tmp := d.file + ".tmp"
+		if err = os.WriteFile(tmp, content, 0600); err == nil {
+			err = os.Rename(tmp, d.file)
+		}
+	}
+	if err != nil {
+		globals.GoLogger.Errorf("SAVE WEBHOOKS ERR %s", err.Error())
+	}
+}
+
+// Add 新增 Webhook, 未指定 Secret 时随机生成; 返回值带 Secret, 之后的查询不再返回
+func (d *Dispatcher) Add(h globals.Webhook) (globals.Webhook, error) {
+	u, err := url.Parse(h.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return h, errors.New("url must be an absolute http(s) url")
+	}
+	if err := checkTarget(u.Hostname()); err != nil {
+		return h, err
+	}
+	for _, v := range h.Events {
+		if !eventTypes[v] {
+			return h, fmt.Errorf("unknown event: %s", v)
+		}
+	}
+
+	h.Id = newId()
+	h.CreatedAt = time.Now()
+	if h.Secret == "" {
+		h.Secret = newId() + newId()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.hooks = append(d.hooks, h)
+	d.save()
+	return h, nil
+}
+
+// Remove 删除 Webhook, 投递记录保留
+func (d *Dispatcher) Remove(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for k, h := range d.hooks {
+		if h.Id == id {
+			d.hooks = append(d.hooks[:k:k], d.hooks[k+1:]...)
+			d.save()
+			return true
+		}
+	}
+	return false
+}
+
+// hook 调用方需持有锁
+func (d *Dispatcher) hook(id string) (globals.Webhook, bool) {
+	for _, h := range d.hooks {
+		if h.Id == id {
+			return h, true
+		}
+	}
+	return globals.Webhook{}, false
+}
+
+// Get 查询 Webhook, 不含 Secret
+func (d *Dispatcher) Get(id string) (globals.Webhook, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	h, found := d.hook(id)
+	h.Secret = ""
+	return h, found
+}
+
+// List 全部 Webhook, 不含 Secret
+func (d *Dispatcher) List() []globals.Webhook {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result []globals.Webhook
+	for _, h := range d.hooks {
+		h.Secret = ""
+		result = append(result, h)
+	}
+	return result
+}
+
+// Deliveries 按时间倒序列出投递记录, webhookId 为空时不过滤, limit<=0 不限制数量
+func (d *Dispatcher) Deliveries(webhookId string, limit int) []globals.Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result []globals.Delivery
+	for k := len(d.deliveries) - 1; k >= 0; k-- {
+		v := d.deliveries[k]
+		if webhookId != "" && v.WebhookId != webhookId {
+			continue
+		}
+		result = append(result, copyDelivery(v))
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+func (d *Dispatcher) Delivery(id string) (globals.Delivery, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, v := range d.deliveries {
+		if v.Id == id {
+			return copyDelivery(v), true
+		}
+	}
+	return globals.Delivery{}, false
+}
+
+func copyDelivery(v *globals.Delivery) globals.Delivery {
+	result := *v
+	result.Attempts = append([]globals.DeliveryAttempt{}, v.Attempts...)
+	return result
+}
+
+// Replay 用原请求体重新投递, 生成新的投递记录
+func (d *Dispatcher) Replay(deliveryId string) (globals.Delivery, error) {
+	d.mu.Lock()
+
+	var origin *globals.Delivery
+	for _, v := range d.deliveries {
+		if v.Id == deliveryId {
+			origin = v
+		}
+	}
+	if origin == nil {
+		d.mu.Unlock()
+		return globals.Delivery{}, errors.New("unknown delivery")
+	}
+	h, found := d.hook(origin.WebhookId)
+	if !found {
+		d.mu.Unlock()
+		return globals.Delivery{}, errors.New("webhook has been removed")
+	}
+
+	delivery := d.newDelivery(h, origin.Event, origin.Payload)
+	delivery.ReplayOf = origin.Id
+	result := copyDelivery(delivery)
+	d.mu.Unlock()
+
+	go d.deliver(delivery)
+	return result, nil
+}
+
+// newDelivery 调用方需持有锁
+func (d *Dispatcher) newDelivery(h globals.Webhook, event string, payload []byte) *globals.Delivery {
+	delivery := &globals.Delivery{
+		Id:        newId(),
+		WebhookId: h.Id,
+		Event:     event,
+		Status:    globals.DeliveryPending,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}
+
+	d.deliveries = append(d.deliveries, delivery)
+	if len(d.deliveries) > globals.DeliveryKeep {
+		d.deliveries = d.deliveries[len(d.deliveries)-globals.DeliveryKeep:]
+	}
+	return delivery
+}
+
+// Emit 向订阅了该事件和平台的 Webhook 投递, 返回投递记录数
+func (d *Dispatcher) Emit(event, platform string, data interface{}) int {
+	payload, err := json.Marshal(globals.WebhookPayload{
+		Id:       newId(),
+		Event:    event,
+		Platform: platform,
+		Time:     time.Now(),
+		Data:     data,
+	})
+	if err != nil {
+		globals.GoLogger.Errorf("WEBHOOK MARSHAL %s ERR %s", event, err.Error())
+		return 0
+	}
+
+	d.mu.Lock()
+	var list []*globals.Delivery
+	for _, h := range d.hooks {
+		if subscribed(h, event, platform) {
+			list = append(list, d.newDelivery(h, event, payload))
+		}
+	}
+	d.mu.Unlock()
+
+	for _, v := range list {
+		go d.deliver(v)
+	}
+	return len(list)
+}
+
+func subscribed(h globals.Webhook, event, platform string) bool {
+	return contains(h.Events, event) && (platform == "" || contains(h.Platforms, platform))
+}
+
+// contains 列表为空时视为全部
+func contains(list []string, s string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver 失败时按指数退避重试, Webhook 被删除后停止
+func (d *Dispatcher) deliver(delivery *globals.Delivery) {
+	for n := 1; n <= globals.WebhookMaxAttempts; n++ {
+		if n > 1 {
+			time.Sleep(globals.WebhookBackoff << (n - 2))
+		}
+
+		d.mu.Lock()
+		h, found := d.hook(delivery.WebhookId)
+		d.mu.Unlock()
+		if !found {
+			d.finish(delivery, globals.DeliveryFailed, globals.DeliveryAttempt{At: time.Now(), Err: "webhook has been removed"})
+			return
+		}
+
+		attempt := d.post(h, delivery)
+		if attempt.Err == "" {
+			d.finish(delivery, globals.DeliverySucceeded, attempt)
+			return
+		}
+		if n == globals.WebhookMaxAttempts {
+			d.finish(delivery, globals.DeliveryFailed, attempt)
+			return
+		}
+		d.finish(delivery, globals.DeliveryPending, attempt)
+	}
+}
+
+func (d *Dispatcher) finish(delivery *globals.Delivery, status string, attempt globals.DeliveryAttempt) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delivery.Status = status
+	delivery.Attempts = append(delivery.Attempts, attempt)
+}
+
+// post 发送一次请求, 每次请求重新生成时间戳和签名
+func (d *Dispatcher) post(h globals.Webhook, delivery *globals.Delivery) globals.DeliveryAttempt {
+	start := time.Now()
+	attempt := globals.DeliveryAttempt{At: start}
+
+	req, err := http.NewRequest(http.MethodPost, h.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Err = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hots-webhook/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.Id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(start.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(h.Secret, start.Unix(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	attempt.Duration = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Err = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Err = resp.Status
+	}
+	return attempt
+}
+
+// Run 订阅事件中心并转换为 Webhook 事件, 一般在启动时以 goroutine 运行
+func (d *Dispatcher) Run(hub *events.Hub) {
+	sub := hub.Subscribe(256, nil)
+	defer hub.Unsubscribe(sub)
+
+	dropped := sub.Dropped()
+	for e := range sub.C {
+		if n := sub.Dropped(); n != dropped {
+			globals.GoLogger.Errorf("WEBHOOK DROPPED %d EVENTS", n-dropped)
+			dropped = n
+		}
+
+		// 从实例的事件来自同步的快照, 由 leader 投递
+		if !leader.IsLeader() {
+			continue
+		}
+
+		switch e.Type {
+		case globals.EventBoardUpdated:
+			d.Emit(globals.WebhookBoardUpdated, e.Platform, e.Diff)
+			if len(e.Diff.Entered) > 0 {
+				d.Emit(globals.WebhookNewEntry, e.Platform, e.Diff.Entered)
+			}
+		case globals.EventAlertFired:
+			d.Emit(globals.WebhookAlertFired, e.Platform, e.Alert)
+		case globals.EventProviderStatus:
+			if !e.Status.Ok {
+				d.Emit(globals.WebhookProviderDown, e.Platform, e.Status)
+			}
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/turbo-uid/hots/globals"
+)
+
+// receiver 记录收到的请求, 按 codes 依次返回状态码, 之后返回 200
+type receiver struct {
+	mu       sync.Mutex
+	codes    []int
+	requests []received
+}
+
+type received struct {
+	at     time.Time
+	header http.Header
+	body   []byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	r.requests = append(r.requests, received{at: time.Now(), header: req.Header.Clone(), body: body})
+	code := http.StatusOK
+	if len(r.codes) > 0 {
+		code, r.codes = r.codes[0], r.codes[1:]
+	}
+	r.mu.Unlock()
+
+	w.WriteHeader(code)
+}
+
+func (r *receiver) received() []received {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]received{}, r.requests...)
+}
+
+// newTestDispatcher 直接加入 Webhook, 绕过注册时的内网地址检查
+func newTestDispatcher(t *testing.T, r *receiver) (*Dispatcher, globals.Webhook) {
+	t.Helper()
+	globals.GoLogger = logrus.New()
+	globals.GoLogger.SetOutput(io.Discard)
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	backoff := globals.WebhookBackoff
+	globals.WebhookBackoff = 20 * time.Millisecond
+	t.Cleanup(func() { globals.WebhookBackoff = backoff })
+
+	d := NewDispatcher(srv.Client())
+	h := globals.Webhook{Id: "h1", Url: srv.URL + "/hook", Secret: "s3cret", Events: []string{globals.WebhookBoardUpdated}}
+	d.hooks = append(d.hooks, h)
+	return d, h
+}
+
+// waitDelivery 等待投递结束
+func waitDelivery(t *testing.T, d *Dispatcher, id string) globals.Delivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if v, _ := d.Delivery(id); v.Status != globals.DeliveryPending {
+			return v
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("delivery %s still pending", id)
+	return globals.Delivery{}
+}
+
+func emit(t *testing.T, d *Dispatcher) globals.Delivery {
+	t.Helper()
+	if n := d.Emit(globals.WebhookBoardUpdated, globals.WeiboFlag, map[string]int{"n": 1}); n != 1 {
+		t.Fatalf("emit created %d deliveries, want 1", n)
+	}
+	return waitDelivery(t, d, d.Deliveries("", 1)[0].Id)
+}
+
+func TestDeliverSigned(t *testing.T) {
+	r := &receiver{}
+	d, h := newTestDispatcher(t, r)
+
+	delivery := emit(t, d)
+	if delivery.Status != globals.DeliverySucceeded || len(delivery.Attempts) != 1 || delivery.Attempts[0].StatusCode != http.StatusOK {
+		t.Fatalf("delivery = %+v", delivery)
+	}
+
+	req := r.received()[0]
+	if req.header.Get(HeaderEvent) != globals.WebhookBoardUpdated || req.header.Get(HeaderDelivery) != delivery.Id {
+		t.Errorf("headers = %v", req.header)
+	}
+	if err := Verify(h.Secret, req.header.Get(HeaderTimestamp), req.header.Get(HeaderSignature), req.body, time.Minute); err != nil {
+		t.Errorf("verify: %s", err)
+	}
+	if err := Verify("other", req.header.Get(HeaderTimestamp), req.header.Get(HeaderSignature), req.body, time.Minute); err == nil {
+		t.Error("verify with wrong secret succeeded")
+	}
+	if err := Verify(h.Secret, req.header.Get(HeaderTimestamp), req.header.Get(HeaderSignature), append(req.body, ' '), time.Minute); err == nil {
+		t.Error("verify with modified body succeeded")
+	}
+
+	var payload globals.WebhookPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil || payload.Event != globals.WebhookBoardUpdated || payload.Platform != globals.WeiboFlag {
+		t.Errorf("payload = %+v, err = %v", payload, err)
+	}
+
+	// 未订阅的事件不投递
+	if n := d.Emit(globals.WebhookAlertFired, globals.WeiboFlag, nil); n != 0 {
+		t.Errorf("unsubscribed event created %d deliveries", n)
+	}
+}
+
+func TestVerifyTolerance(t *testing.T) {
+	body := []byte("{}")
+	old := time.Now().Add(-time.Hour).Unix()
+	ts, sig := strconv.FormatInt(old, 10), Sign("s", old, body)
+
+	if err := Verify("s", "yesterday", sig, body, 0); err == nil {
+		t.Error("invalid timestamp accepted")
+	}
+	if err := Verify("s", ts, sig, body, time.Minute); err == nil {
+		t.Error("expired timestamp accepted")
+	}
+	if err := Verify("s", ts, sig, body, 0); err != nil {
+		t.Errorf("tolerance 0: %s", err)
+	}
+}
+
+func TestDeliverRetry(t *testing.T) {
+	r := &receiver{codes: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	d, _ := newTestDispatcher(t, r)
+
+	delivery := emit(t, d)
+	if delivery.Status != globals.DeliverySucceeded || len(delivery.Attempts) != 3 {
+		t.Fatalf("delivery = %+v", delivery)
+	}
+	for k, want := range []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK} {
+		if a := delivery.Attempts[k]; a.StatusCode != want || (want != http.StatusOK) != (a.Err != "") {
+			t.Errorf("attempt %d = %+v, want status %d", k, a, want)
+		}
+	}
+
+	// 第 n 次重试前等待 WebhookBackoff * 2^(n-1), 每次重新签名
+	reqs := r.received()
+	for k, want := range []time.Duration{globals.WebhookBackoff, 2 * globals.WebhookBackoff} {
+		if gap := reqs[k+1].at.Sub(reqs[k].at); gap < want {
+			t.Errorf("retry %d after %s, want at least %s", k+1, gap, want)
+		}
+		if reqs[k+1].header.Get(HeaderDelivery) != delivery.Id {
+			t.Errorf("retry %d delivery id = %q", k+1, reqs[k+1].header.Get(HeaderDelivery))
+		}
+	}
+}
+
+func TestDeliverGiveUp(t *testing.T) {
+	attempts := globals.WebhookMaxAttempts
+	globals.WebhookMaxAttempts = 3
+	t.Cleanup(func() { globals.WebhookMaxAttempts = attempts })
+
+	r := &receiver{codes: []int{500, 500, 500, 500}}
+	d, _ := newTestDispatcher(t, r)
+
+	delivery := emit(t, d)
+	if delivery.Status != globals.DeliveryFailed || len(delivery.Attempts) != 3 || len(r.received()) != 3 {
+		t.Fatalf("delivery = %+v, %d requests", delivery, len(r.received()))
+	}
+	if last := delivery.Attempts[2]; last.StatusCode != 500 || last.Err == "" {
+		t.Errorf("last attempt = %+v", last)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	r := &receiver{}
+	d, h := newTestDispatcher(t, r)
+	origin := emit(t, d)
+
+	replay, err := d.Replay(origin.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.ReplayOf != origin.Id || replay.Id == origin.Id || string(replay.Payload) != string(origin.Payload) {
+		t.Errorf("replay = %+v", replay)
+	}
+	replay = waitDelivery(t, d, replay.Id)
+	if replay.Status != globals.DeliverySucceeded {
+		t.Errorf("replay = %+v", replay)
+	}
+
+	// 请求体不变, 接收方可按 payload id 去重; 投递ID不同
+	reqs := r.received()
+	if len(reqs) != 2 || string(reqs[0].body) != string(reqs[1].body) || reqs[1].header.Get(HeaderDelivery) != replay.Id {
+		t.Errorf("requests = %+v", reqs)
+	}
+	if got := d.Deliveries(h.Id, 0); len(got) != 2 || got[0].Id != replay.Id {
+		t.Errorf("deliveries = %+v", got)
+	}
+
+	if _, err := d.Replay("nope"); err == nil {
+		t.Error("replay unknown delivery succeeded")
+	}
+	d.Remove(h.Id)
+	if _, err := d.Replay(origin.Id); err == nil || err.Error() != "webhook has been removed" {
+		t.Errorf("replay after remove err = %v", err)
+	}
+}
+
+func TestLoadKeepsHooksOnError(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "webhooks.json")
+	os.WriteFile(file, []byte(`[{"id":"h1","url":"https://example.com/hook"}]`), 0600)
+
+	d := NewDispatcher(newClient())
+	if err := d.Load(file); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(file, []byte(`[{"id":"h2","url":`), 0600)
+	if err := d.Load(file); err == nil {
+		t.Fatal("load malformed file succeeded")
+	}
+	if list := d.List(); len(list) != 1 || list[0].Id != "h1" {
+		t.Errorf("hooks after failed load = %+v", list)
+	}
+}
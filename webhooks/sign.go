@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 请求头
+const (
+	HeaderEvent     = "X-Hots-Event"
+	HeaderDelivery  = "X-Hots-Delivery"
+	HeaderTimestamp = "X-Hots-Timestamp"
+	HeaderSignature = "X-Hots-Signature"
+)
+
+// Sign 对 "timestamp.body" 做 HMAC-SHA256, 返回 sha256=<hex>
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 供接收方校验签名, tolerance 为允许的时间误差, 用于防重放; 为 0 时不检查时间
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid timestamp")
+	}
+	if tolerance > 0 {
+		diff := time.Since(time.Unix(ts, 0))
+		if diff > tolerance || diff < -tolerance {
+			return errors.New("timestamp out of tolerance")
+		}
+	}
+	if !strings.HasPrefix(signature, "sha256=") || !hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+// newClient 投递使用的客户端, 在建立连接时检查实际连接的地址, 重定向和 DNS 变化同样受限
+func newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowedIP(ip) {
+				return fmt.Errorf("webhook target %s is not allowed", host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport, Timeout: globals.WebhookTimeout}
+}
+
+// checkTarget 注册时检查回调地址, 地址为域名时按解析结果检查, 解析失败时由投递时的检查兜底
+func checkTarget(host string) error {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		host = "127.0.0.1"
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil
+		}
+		ips = ips[:0]
+		for _, v := range addrs {
+			ips = append(ips, v.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if !allowedIP(ip) {
+			return fmt.Errorf("url must not point to a loopback, link-local or private address: %s", ip)
+		}
+	}
+	return nil
+}
+
+// allowedIP 公网地址, 或在 WebhookAllowNets 中的地址
+func allowedIP(ip net.IP) bool {
+	for _, v := range globals.WebhookAllowNets {
+		if _, network, err := net.ParseCIDR(v); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+		} else if allowed := net.ParseIP(v); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+
+	return !(ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || ip.IsInterfaceLocalMulticast())
+}
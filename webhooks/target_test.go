@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/turbo-uid/hots/globals"
+)
+
+func TestAddRejectsInternalTargets(t *testing.T) {
+	d := NewDispatcher(newClient())
+	for _, u := range []string{
+		"http://169.254.169.254/latest/meta-data",
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.1.2.3/hook",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		if _, err := d.Add(globals.Webhook{Url: u}); err == nil {
+			t.Errorf("Add(%s) accepted", u)
+		}
+	}
+
+	if _, err := d.Add(globals.Webhook{Url: "https://93.184.216.34/hook"}); err != nil {
+		t.Errorf("Add public ip: %s", err)
+	}
+}
+
+func TestDeliveryChecksDialedAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	d := NewDispatcher(newClient())
+	hook := globals.Webhook{Url: srv.URL, Secret: "s"}
+	delivery := &globals.Delivery{Id: "1", Payload: []byte("{}")}
+
+	// 注册时的检查可能被 DNS 变化绕过, 投递时同样拒绝
+	if attempt := d.post(hook, delivery); attempt.Err == "" {
+		t.Fatalf("post to %s succeeded", srv.URL)
+	}
+
+	globals.WebhookAllowNets = []string{"127.0.0.0/8"}
+	defer func() { globals.WebhookAllowNets = nil }()
+	if attempt := d.post(hook, delivery); attempt.Err != "" || attempt.StatusCode != http.StatusOK {
+		t.Fatalf("post with allowlist: %+v", attempt)
+	}
+}